@@ -10,6 +10,7 @@ require (
 	github.com/gin-gonic/gin v1.10.1
 	github.com/golang-jwt/jwt/v5 v5.3.0
 	github.com/prometheus/client_golang v1.20.5
+	github.com/robfig/cron/v3 v3.0.1
 	golang.org/x/crypto v0.41.0
 	golang.org/x/net v0.42.0
 	golang.org/x/oauth2 v0.28.0
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
 	"strings"
-	"time"
+	"syscall"
 	"velero-manager/pkg/config"
 	"velero-manager/pkg/handlers"
 	"velero-manager/pkg/k8s"
@@ -16,7 +21,16 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// Set at build time with -ldflags "-X main.version=... -X main.commit=..."
+var (
+	version = "dev"
+	commit  = "unknown"
+)
+
 func main() {
+	// Structured JSON logs; the standard logger is routed through it as well
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
 	// Initialize Kubernetes client
 	k8sClient, err := k8s.NewClient()
 	if err != nil {
@@ -39,20 +53,46 @@ func main() {
 		log.Println("OIDC authentication disabled, using legacy authentication")
 	}
 
+	// Serve backup/restore/schedule/cronjob lists from watch-based informers
+	informerCtx, stopInformers := context.WithCancel(context.Background())
+	var informerCache *k8s.InformerCache
+	if config.GetServerConfig().InformersEnabled {
+		informerCache = k8s.NewInformerCache(k8sClient.DynamicClient, k8s.VeleroNamespace)
+		k8sClient.ListCache.UseInformers(informerCache)
+		go informerCache.Start(informerCtx)
+	}
+
 	// Initialize metrics
 	veleroMetrics := metrics.NewVeleroMetrics(k8sClient)
+	veleroMetrics.SetManagerInfo(version, commit)
 
-	// Start metrics collector (collect every 30 seconds)
-	metricsCollector := metrics.NewMetricsCollector(veleroMetrics, 30*time.Second)
+	// Start metrics collector (every 30 seconds unless METRICS_INTERVAL is set)
+	metricsCollector := metrics.NewMetricsCollector(veleroMetrics, config.GetMetricsConfig().CollectionInterval)
 	go metricsCollector.Start()
 
-	// Initialize Gin router
-	router := gin.Default()
+	// Retry validation of Unavailable storage locations in the background
+	storageLocationReconciler := handlers.NewStorageLocationReconciler(k8sClient)
+	go storageLocationReconciler.Start()
+
+	// Record when each user was last seen, saved to a ConfigMap periodically
+	userActivityTracker := handlers.NewUserActivityTracker(k8sClient)
+	go userActivityTracker.Start()
+
+	// Initialize Gin router with our own access logger so noisy paths can be skipped
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.RequestLogger(config.GetServerConfig().LogExcludedPaths))
+	router.Use(gin.Recovery())
 
-	// CORS configuration for development
+	// CORS configuration; every origin is allowed unless CORS_ALLOWED_ORIGINS is set
 	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowAllOrigins = true
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Auth-Token"}
+	if origins := config.GetServerConfig().CORSAllowedOrigins; len(origins) > 0 {
+		corsConfig.AllowOrigins = origins
+	} else {
+		corsConfig.AllowAllOrigins = true
+	}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Auth-Token", middleware.RequestIDHeader, "If-None-Match"}
+	corsConfig.ExposeHeaders = []string{middleware.RequestIDHeader, "ETag"}
 	router.Use(cors.New(corsConfig))
 
 	// Add Prometheus metrics middleware
@@ -61,108 +101,35 @@ func main() {
 	// Initialize handlers
 	veleroHandler := handlers.NewVeleroHandler(k8sClient, veleroMetrics)
 	userHandler := handlers.NewUserHandler(k8sClient)
-	oidcConfigHandler := handlers.NewOIDCConfigHandler(k8sClient)
+	settingsHandler := handlers.NewSettingsHandler()
+	openAPIHandler := handlers.NewOpenAPIHandler(router, version)
+	healthHandler := handlers.NewHealthHandler(k8sClient)
 
 	// Initialize auth handler with OIDC support
 	authHandler, err := handlers.NewAuthHandler(k8sClient, oidcConfig)
 	if err != nil {
 		log.Fatalf("Failed to create auth handler: %v", err)
 	}
+	oidcConfigHandler := handlers.NewOIDCConfigHandler(k8sClient, authHandler)
 
 	// Set user validator for admin middleware
 	middleware.SetUserValidator(userHandler)
 
 	// API routes
-	api := router.Group("/api/v1")
-	{
-		// Public endpoints (no auth required)
-		api.GET("/health", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"status": "healthy"})
-		})
-
-		// Test endpoint for generating mock metrics data
-		api.POST("/test/generate-mock-data", veleroHandler.GenerateTestData)
-
-		// Auth endpoints
-		auth := api.Group("/auth")
-		{
-			auth.GET("/info", authHandler.GetAuthInfo)                 // Get auth config and user info
-			auth.POST("/login", authHandler.LegacyLogin)               // Legacy username/password login
-			auth.GET("/oidc/login", authHandler.InitiateOIDCLogin)     // Start OIDC flow
-			auth.GET("/oidc/callback", authHandler.HandleOIDCCallback) // OIDC callback
-			auth.POST("/logout", authHandler.Logout)                   // Logout (both OIDC and legacy)
-		}
+	registerAPIRoutes(router, apiHandlers{
+		velero:           veleroHandler,
+		user:             userHandler,
+		auth:             authHandler,
+		oidcConfig:       oidcConfigHandler,
+		settings:         settingsHandler,
+		openAPI:          openAPIHandler,
+		storageLocations: storageLocationReconciler,
+		userActivity:     userActivityTracker,
+	})
 
-		// Protected endpoints (authentication required)
-		protected := api.Group("/")
-		protected.Use(middleware.RequireOIDCAuth(authHandler.GetOIDCProvider()))
-		{
-			// User management - admin only
-			admin := protected.Group("/")
-			admin.Use(middleware.RequireAdmin())
-			{
-				admin.GET("/users", userHandler.ListUsers)
-				admin.POST("/users", userHandler.CreateUser)
-				admin.DELETE("/users/:username", userHandler.DeleteUser)
-				admin.POST("/clusters", veleroHandler.AddCluster)
-				admin.POST("/storage-locations", veleroHandler.CreateStorageLocation)
-				admin.DELETE("/storage-locations/:name", veleroHandler.DeleteStorageLocation)
-
-				// OIDC configuration management - admin only for modify operations
-				admin.PUT("/oidc/config", oidcConfigHandler.UpdateOIDCConfig)
-				admin.POST("/oidc/test", oidcConfigHandler.TestOIDCConnection)
-			}
-
-			// User can change their own password
-			protected.PUT("/users/:username/password", userHandler.ChangePassword)
-
-			// OIDC configuration view - all authenticated users can view
-			protected.GET("/oidc/config", oidcConfigHandler.GetOIDCConfig)
-
-			// Backup operations (authenticated users)
-			protected.GET("/backups", veleroHandler.ListBackups)
-			protected.POST("/backups", veleroHandler.CreateBackup)
-			protected.DELETE("/backups/:name", veleroHandler.DeleteBackup)
-			protected.GET("/backups/:name/details", veleroHandler.GetBackupDetails)
-			protected.GET("/backups/:name/logs", veleroHandler.GetBackupLogs)
-			protected.GET("/backups/:name/download", veleroHandler.DownloadBackup)
-			protected.GET("/backups/:name/describe", veleroHandler.DescribeBackup)
-
-			// Restore operations (authenticated users)
-			protected.GET("/restores", veleroHandler.ListRestores)
-			protected.POST("/restores", veleroHandler.CreateRestore)
-			protected.DELETE("/restores/:name", veleroHandler.DeleteRestore)
-			protected.GET("/restores/:name/logs", veleroHandler.GetRestoreLogs)
-			protected.GET("/restores/:name/describe", veleroHandler.DescribeRestore)
-
-			// Schedule operations (authenticated users)
-			protected.GET("/schedules", veleroHandler.ListSchedules)
-			protected.POST("/schedules", veleroHandler.CreateSchedule)
-			protected.DELETE("/schedules/:name", veleroHandler.DeleteSchedule)
-			protected.PUT("/schedules/:name", veleroHandler.UpdateSchedule)
-			protected.POST("/schedules/:name/backup", veleroHandler.CreateBackupFromSchedule)
-
-			// CronJob operations (authenticated users)
-			protected.GET("/cronjobs", veleroHandler.ListCronJobs)
-			protected.POST("/cronjobs", veleroHandler.CreateCronJob)
-			protected.DELETE("/cronjobs/:name", veleroHandler.DeleteCronJob)
-			protected.PUT("/cronjobs/:name", veleroHandler.UpdateCronJob)
-			protected.POST("/cronjobs/:name/trigger", veleroHandler.TriggerCronJob)
-
-			// Cluster operations (read operations for all authenticated users)
-			protected.GET("/clusters", veleroHandler.ListClusters)
-			protected.PUT("/clusters/:cluster/description", veleroHandler.UpdateClusterDescription)
-			protected.GET("/clusters/:cluster/backups", veleroHandler.ListBackupsByCluster)
-			protected.GET("/clusters/:cluster/health", veleroHandler.GetClusterHealth)
-			protected.GET("/clusters/:cluster/details", veleroHandler.GetClusterDetails)
-
-			// Storage locations (read operations for all authenticated users)
-			protected.GET("/storage-locations", veleroHandler.ListStorageLocations)
-
-			// Dashboard metrics
-			protected.GET("/dashboard/metrics", veleroHandler.GetDashboardMetrics)
-		}
-	}
+	// Kubernetes probes
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
 
 	// Prometheus metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
@@ -182,7 +149,49 @@ func main() {
 		c.File("./frontend/build/index.html")
 	})
 
-	log.Println("🚀 Velero Manager starting on :8080")
-	log.Println("📁 Serving frontend from ./frontend/build/")
-	log.Fatal(router.Run(":8080"))
+	// Long-lived streams (SSE) watch this context so they end when shutdown starts
+	streamCtx, cancelStreams := context.WithCancel(context.Background())
+	server := &http.Server{
+		Addr:        ":8080",
+		Handler:     router,
+		BaseContext: func(net.Listener) context.Context { return streamCtx },
+	}
+	server.RegisterOnShutdown(cancelStreams)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	go func() {
+		log.Println("🚀 Velero Manager starting on :8080")
+		log.Println("📁 Serving frontend from ./frontend/build/")
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+
+	shutdownTimeout := config.GetServerConfig().ShutdownTimeout
+	log.Printf("🛑 Shutting down, draining in-flight requests (timeout %s)...", shutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️  Server did not drain cleanly: %v", err)
+	} else {
+		log.Println("✅ All in-flight requests drained")
+	}
+
+	veleroMetrics.ManagerUp.Set(0)
+	metricsCollector.Stop()
+	veleroHandler.Stop()
+	storageLocationReconciler.Stop()
+	userActivityTracker.Stop()
+	stopInformers()
+	if informerCache != nil {
+		informerCache.Shutdown()
+	}
+	log.Println("👋 Velero Manager stopped")
 }
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// BackupConfig holds defaults applied to backups created by velero-manager
+type BackupConfig struct {
+	// Namespaces excluded from cluster backups unless the request overrides them
+	DefaultExcludedNamespaces []string `json:"default_excluded_namespaces"`
+
+	// Automatic re-validation of Unavailable backup storage locations
+	RevalidationEnabled     bool          `json:"revalidation_enabled"`
+	RevalidationInterval    time.Duration `json:"revalidation_interval"`
+	RevalidationMaxAttempts int           `json:"revalidation_max_attempts"`
+
+	// Notify once a storage location has been Unavailable this long
+	UnavailableAlertAfter time.Duration `json:"unavailable_alert_after"`
+
+	// Pause schedules targeting an Unavailable storage location until it recovers
+	AutoPauseSchedules bool `json:"auto_pause_schedules"`
+
+	// A schedule is overdue once its last backup is this many times older than the wait
+	// from that backup to the schedule's next run
+	ScheduleOverdueFactor float64 `json:"schedule_overdue_factor"`
+
+	// Extracts the cluster from backup names without a cluster label; the "cluster"
+	// group, or else the first group, is the cluster name
+	ClusterNamePattern *regexp.Regexp `json:"cluster_name_pattern"`
+}
+
+// defaultScheduleOverdueFactor allows a run to take half a period before it is missed
+const defaultScheduleOverdueFactor = 1.5
+
+var (
+	backupConfig     *BackupConfig
+	backupConfigOnce sync.Once
+)
+
+// GetBackupConfig loads backup defaults from environment variables on first use
+func GetBackupConfig() *BackupConfig {
+	backupConfigOnce.Do(func() {
+		backupConfig = &BackupConfig{
+			// velero-manager's own namespace holds cluster tokens, which must not end up
+			// in cross-cluster backups
+			DefaultExcludedNamespaces: getEnvSlice("BACKUP_EXCLUDED_NAMESPACES",
+				[]string{"kube-system", "kube-public", "kube-node-lease", "velero-manager"}),
+
+			RevalidationEnabled:     getEnvBool("BSL_REVALIDATION_ENABLED", true),
+			RevalidationInterval:    getEnvDuration("BSL_REVALIDATION_INTERVAL", 2*time.Minute),
+			RevalidationMaxAttempts: getEnvInt("BSL_REVALIDATION_MAX_ATTEMPTS", 5),
+			UnavailableAlertAfter:   getEnvDuration("BSL_UNAVAILABLE_ALERT_AFTER", 30*time.Minute),
+
+			AutoPauseSchedules: getEnvBool("BSL_AUTO_PAUSE_SCHEDULES", false),
+
+			ScheduleOverdueFactor: getEnvFloat("SCHEDULE_OVERDUE_FACTOR", defaultScheduleOverdueFactor),
+		}
+
+		// Below 1 every schedule would be overdue before its next run
+		if backupConfig.ScheduleOverdueFactor < 1 {
+			log.Printf("⚠️  Ignoring SCHEDULE_OVERDUE_FACTOR %v: must be at least 1", backupConfig.ScheduleOverdueFactor)
+			backupConfig.ScheduleOverdueFactor = defaultScheduleOverdueFactor
+		}
+
+		if pattern := getEnv("BACKUP_CLUSTER_NAME_PATTERN", ""); pattern != "" {
+			compiled, err := parseClusterNamePattern(pattern)
+			if err != nil {
+				log.Printf("⚠️  Ignoring BACKUP_CLUSTER_NAME_PATTERN: %v", err)
+			} else {
+				backupConfig.ClusterNamePattern = compiled
+			}
+		}
+	})
+	return backupConfig
+}
+
+// parseClusterNamePattern compiles a cluster name pattern, which needs a group to capture
+func parseClusterNamePattern(pattern string) (*regexp.Regexp, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if compiled.NumSubexp() == 0 {
+		return nil, fmt.Errorf("pattern %q has no capture group for the cluster name", pattern)
+	}
+	return compiled, nil
+}
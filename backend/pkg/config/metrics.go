@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MetricsConfig holds settings for the Prometheus metrics
+type MetricsConfig struct {
+	// Upper bounds in seconds for the backup/restore duration histograms
+	DurationBuckets []float64 `json:"duration_buckets"`
+
+	// How often Velero metrics are collected
+	CollectionInterval time.Duration `json:"collection_interval"`
+}
+
+var (
+	metricsConfig     *MetricsConfig
+	metricsConfigOnce sync.Once
+)
+
+// defaultDurationBuckets matches ExponentialBuckets(30, 2, 10): 30s to ~4.3 hours
+var defaultDurationBuckets = []float64{30, 60, 120, 240, 480, 960, 1920, 3840, 7680, 15360}
+
+// GetMetricsConfig loads metrics settings from environment variables on first use
+func GetMetricsConfig() *MetricsConfig {
+	metricsConfigOnce.Do(func() {
+		buckets := defaultDurationBuckets
+		if values := getEnvSlice("METRICS_DURATION_BUCKETS", nil); values != nil {
+			parsed, err := parseBuckets(values)
+			if err != nil {
+				log.Printf("⚠️  Ignoring METRICS_DURATION_BUCKETS: %v", err)
+			} else {
+				buckets = parsed
+			}
+		}
+
+		metricsConfig = &MetricsConfig{
+			DurationBuckets:    buckets,
+			CollectionInterval: getEnvDuration("METRICS_INTERVAL", 30*time.Second),
+		}
+	})
+	return metricsConfig
+}
+
+// parseBuckets parses histogram bucket boundaries, which must be positive and strictly increasing
+func parseBuckets(values []string) ([]float64, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no bucket boundaries given")
+	}
+
+	buckets := make([]float64, 0, len(values))
+	for i, value := range values {
+		bucket, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bucket boundary %q: %w", value, err)
+		}
+		if bucket <= 0 {
+			return nil, fmt.Errorf("bucket boundary %q must be positive", value)
+		}
+		if i > 0 && bucket <= buckets[i-1] {
+			return nil, fmt.Errorf("bucket boundaries must be strictly increasing (%s after %v)", value, buckets[i-1])
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Webhook payload formats
+const (
+	WebhookFormatJSON  = "json"
+	WebhookFormatSlack = "slack"
+)
+
+// NotificationConfig holds settings for failure notifications
+type NotificationConfig struct {
+	// URL failed backups are POSTed to; empty disables notifications. It may embed a
+	// token, as Slack incoming webhooks do, so settings only show whether it's set.
+	WebhookURL string `json:"webhook_url" secret:"true"`
+
+	// Payload format: "json" for the event itself or "slack" for a Slack message
+	WebhookFormat string `json:"webhook_format"`
+
+	// How long a webhook call may take
+	WebhookTimeout time.Duration `json:"webhook_timeout"`
+}
+
+var (
+	notificationConfig     *NotificationConfig
+	notificationConfigOnce sync.Once
+)
+
+// GetNotificationConfig loads notification settings from environment variables on first use
+func GetNotificationConfig() *NotificationConfig {
+	notificationConfigOnce.Do(func() {
+		notificationConfig = &NotificationConfig{
+			WebhookURL:     getEnv("NOTIFICATION_WEBHOOK_URL", ""),
+			WebhookFormat:  getEnv("NOTIFICATION_WEBHOOK_FORMAT", WebhookFormatJSON),
+			WebhookTimeout: getEnvDuration("NOTIFICATION_WEBHOOK_TIMEOUT", 10*time.Second),
+		}
+
+		if format := notificationConfig.WebhookFormat; format != WebhookFormatJSON && format != WebhookFormatSlack {
+			log.Printf("⚠️  Ignoring NOTIFICATION_WEBHOOK_FORMAT %q, must be json or slack", format)
+			notificationConfig.WebhookFormat = WebhookFormatJSON
+		}
+	})
+	return notificationConfig
+}
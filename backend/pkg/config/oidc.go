@@ -1,9 +1,16 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 // OIDCConfig holds OIDC configuration for Keycloak integration
@@ -11,22 +18,43 @@ type OIDCConfig struct {
 	Enabled      bool   `json:"enabled"`
 	IssuerURL    string `json:"issuer_url"`
 	ClientID     string `json:"client_id"`
-	ClientSecret string `json:"client_secret"`
+	ClientSecret string `json:"client_secret" secret:"true"`
 	RedirectURL  string `json:"redirect_url"`
 
 	// Role mapping configuration
-	RolesClaim  string   `json:"roles_claim"`  // JWT claim containing roles
-	GroupsClaim string   `json:"groups_claim"` // JWT claim containing groups
-	AdminRoles  []string `json:"admin_roles"`  // Keycloak roles that map to admin
-	AdminGroups []string `json:"admin_groups"` // Keycloak groups that map to admin
-	DefaultRole string   `json:"default_role"` // Default role for authenticated users
+	RolesClaim   string   `json:"roles_claim"`   // JWT claim containing roles
+	GroupsClaim  string   `json:"groups_claim"`  // JWT claim containing groups
+	AdminRoles   []string `json:"admin_roles"`   // Keycloak roles that map to admin
+	AdminGroups  []string `json:"admin_groups"`  // Keycloak groups that map to admin
+	UserRoles    []string `json:"user_roles"`    // Keycloak roles that map to user
+	ViewerRoles  []string `json:"viewer_roles"`  // Keycloak roles that map to read-only viewer
+	ViewerGroups []string `json:"viewer_groups"` // Keycloak groups that map to read-only viewer
+	DefaultRole  string   `json:"default_role"`  // Role for users matching none of the above, or NoAccessRole
 
 	// Optional claims mapping
 	UsernameClaim string `json:"username_claim"`  // Claim for username (default: preferred_username)
 	EmailClaim    string `json:"email_claim"`     // Claim for email (default: email)
 	FullNameClaim string `json:"full_name_claim"` // Claim for full name (default: name)
+
+	// TLS configuration for providers behind an internal PKI
+	CAFile             string `json:"ca_file"`              // Path to a PEM CA bundle trusted in addition to the system roots
+	CAData             string `json:"ca_data"`              // Inline PEM CA bundle, same as CAFile
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"` // Development only: disables TLS verification
 }
 
+const (
+	// NoAccessRole denies access; as DefaultRole only users with a mapped role can log in
+	NoAccessRole = "no-access"
+	// ViewerRole can read everything but not create, change or delete anything
+	ViewerRole = "viewer"
+)
+
+// DefaultUserRoles are the Keycloak roles that map to user unless configured otherwise
+var DefaultUserRoles = []string{"velero-user"}
+
+// DefaultViewerRoles are the Keycloak roles that map to viewer unless configured otherwise
+var DefaultViewerRoles = []string{"velero-viewer"}
+
 var (
 	currentConfig *OIDCConfig
 	configMutex   sync.RWMutex
@@ -59,11 +87,19 @@ func GetOIDCConfig() *OIDCConfig {
 		GroupsClaim: getEnv("OIDC_GROUPS_CLAIM", "groups"),
 		AdminRoles:  getEnvSlice("OIDC_ADMIN_ROLES", []string{"velero-admin", "admin"}),
 		AdminGroups: getEnvSlice("OIDC_ADMIN_GROUPS", []string{"velero-administrators", "administrators"}),
+		UserRoles:   getEnvSlice("OIDC_USER_ROLES", DefaultUserRoles),
 		DefaultRole: getEnv("OIDC_DEFAULT_ROLE", "user"),
 
+		ViewerRoles:  getEnvSlice("OIDC_VIEWER_ROLES", DefaultViewerRoles),
+		ViewerGroups: getEnvSlice("OIDC_VIEWER_GROUPS", []string{}),
+
 		UsernameClaim: getEnv("OIDC_USERNAME_CLAIM", "preferred_username"),
 		EmailClaim:    getEnv("OIDC_EMAIL_CLAIM", "email"),
 		FullNameClaim: getEnv("OIDC_FULL_NAME_CLAIM", "name"),
+
+		CAFile:             getEnv("OIDC_CA_FILE", ""),
+		CAData:             getEnv("OIDC_CA_DATA", ""),
+		InsecureSkipVerify: getEnvBool("OIDC_INSECURE_SKIP_VERIFY", false),
 	}
 
 	currentConfig = config
@@ -96,6 +132,52 @@ func (c *OIDCConfig) IsValid() bool {
 		c.RedirectURL != ""
 }
 
+// HTTPClient returns the client used to talk to the provider: nil, meaning the default
+// client, unless a CA bundle or InsecureSkipVerify is configured
+func (c *OIDCConfig) HTTPClient() (*http.Client, error) {
+	return NewOIDCHTTPClient(c.CAFile, c.CAData, c.InsecureSkipVerify)
+}
+
+// NewOIDCHTTPClient builds an HTTP client trusting the system roots plus the CA bundle
+// in caFile and/or caData. insecureSkipVerify disables verification entirely and must
+// only be used in development
+func NewOIDCHTTPClient(caFile, caData string, insecureSkipVerify bool) (*http.Client, error) {
+	if caFile == "" && caData == "" && !insecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" || caData != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if caFile != "" {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read OIDC CA file: %v", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no PEM certificates found in OIDC CA file %s", caFile)
+			}
+		}
+		if caData != "" && !pool.AppendCertsFromPEM([]byte(caData)) {
+			return nil, fmt.Errorf("no PEM certificates found in OIDC CA data")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if insecureSkipVerify {
+		log.Printf("⚠️ OIDC TLS certificate verification is disabled; do not use this in production")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport, Timeout: 30 * time.Second}, nil
+}
+
 // Helper functions
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -111,6 +193,33 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
 func getEnvSlice(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
 		// Simple comma-separated parsing
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+// ServerConfig holds settings for the HTTP server itself
+type ServerConfig struct {
+	// Request paths (prefix match) that are not written to the access log
+	LogExcludedPaths []string `json:"log_excluded_paths"`
+
+	// How long in-flight requests get to finish on SIGTERM/SIGINT
+	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
+
+	// How long a request handler waits on the Kubernetes API before giving up
+	KubernetesRequestTimeout time.Duration `json:"kubernetes_request_timeout"`
+
+	// How long full backup/restore/cronjob lists are reused across requests (0 disables)
+	ListCacheTTL time.Duration `json:"list_cache_ttl"`
+
+	// Watch backups, restores, schedules and cronjobs with shared informers and read
+	// lists from them instead of the API server
+	InformersEnabled bool `json:"informers_enabled"`
+
+	// Origins allowed to call the API cross-origin; empty allows every origin
+	CORSAllowedOrigins []string `json:"cors_allowed_origins"`
+
+	// How often recorded user activity is written to the velero-manager-user-activity ConfigMap
+	ActivityFlushInterval time.Duration `json:"activity_flush_interval"`
+
+	// Password for the admin user created when no users exist yet; without it the
+	// password comes from the velero-manager-bootstrap secret or is generated
+	AdminInitialPassword string `json:"-"`
+}
+
+var (
+	serverConfig     *ServerConfig
+	serverConfigOnce sync.Once
+)
+
+// GetServerConfig loads server settings from environment variables on first use
+func GetServerConfig() *ServerConfig {
+	serverConfigOnce.Do(func() {
+		serverConfig = &ServerConfig{
+			LogExcludedPaths: getEnvSlice("LOG_EXCLUDED_PATHS",
+				[]string{"/api/v1/health", "/healthz", "/readyz", "/metrics", "/static/", "/favicon.ico", "/manifest.json"}),
+
+			ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+			ListCacheTTL:    getEnvDuration("LIST_CACHE_TTL", 10*time.Second),
+
+			KubernetesRequestTimeout: getEnvDuration("K8S_REQUEST_TIMEOUT", 30*time.Second),
+			InformersEnabled:         getEnvBool("INFORMERS_ENABLED", true),
+
+			CORSAllowedOrigins: getEnvSlice("CORS_ALLOWED_ORIGINS", nil),
+
+			ActivityFlushInterval: getEnvDuration("ACTIVITY_FLUSH_INTERVAL", time.Minute),
+
+			AdminInitialPassword: getEnv("ADMIN_INITIAL_PASSWORD", ""),
+		}
+	})
+	return serverConfig
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Stable error codes clients can match on
+const (
+	ErrCodeVeleroNotInstalled      = "VELERO_NOT_INSTALLED"
+	ErrCodeVeleroVersionFailed     = "VELERO_VERSION_FAILED"
+	ErrCodeInvalidRequest          = "INVALID_REQUEST"
+	ErrCodeInvalidQuery            = "INVALID_QUERY"
+	ErrCodeInvalidName             = "INVALID_NAME"
+	ErrCodeInvalidTimeout          = "INVALID_TIMEOUT"
+	ErrCodeInvalidTTL              = "INVALID_TTL"
+	ErrCodeInvalidHooks            = "INVALID_HOOKS"
+	ErrCodeInvalidOrderedResources = "INVALID_ORDERED_RESOURCES"
+	ErrCodeInvalidResourceFilters  = "INVALID_RESOURCE_FILTERS"
+	ErrCodeInvalidResourcePolicy   = "INVALID_RESOURCE_POLICY"
+	ErrCodeInvalidNamespaceMapping = "INVALID_NAMESPACE_MAPPING"
+	ErrCodeInvalidManifest         = "INVALID_MANIFEST"
+	ErrCodeInvalidMetadata         = "INVALID_METADATA"
+	ErrCodeResourcePolicyGetFailed = "RESOURCE_POLICY_GET_FAILED"
+	ErrCodeNamespaceNotFound       = "NAMESPACE_NOT_FOUND"
+	ErrCodeNamespaceGetFailed      = "NAMESPACE_GET_FAILED"
+	ErrCodeBackupNotFound          = "BACKUP_NOT_FOUND"
+	ErrCodeBackupExists            = "BACKUP_EXISTS"
+	ErrCodeBackupNotReady          = "BACKUP_NOT_READY"
+	ErrCodeBackupNotCompleted      = "BACKUP_NOT_COMPLETED"
+	ErrCodeBackupGetFailed         = "BACKUP_GET_FAILED"
+	ErrCodeBackupListFailed        = "BACKUP_LIST_FAILED"
+	ErrCodeNoCompletedBackup       = "NO_COMPLETED_BACKUP"
+	ErrCodeBackupCreateFailed      = "BACKUP_CREATE_FAILED"
+	ErrCodeBackupDeleteFailed      = "BACKUP_DELETE_FAILED"
+	ErrCodeBackupUpdateFailed      = "BACKUP_UPDATE_FAILED"
+	ErrCodeResourceListNotFound    = "RESOURCE_LIST_NOT_FOUND"
+	ErrCodeBackupLogNotFound       = "BACKUP_LOG_NOT_FOUND"
+	ErrCodeDeleteRequestListFailed = "DELETE_REQUEST_LIST_FAILED"
+	ErrCodeDownloadFailed          = "DOWNLOAD_FAILED"
+	ErrCodeDownloadTimeout         = "DOWNLOAD_TIMEOUT"
+	ErrCodeRestoreNotFound         = "RESTORE_NOT_FOUND"
+	ErrCodeRestoreGetFailed        = "RESTORE_GET_FAILED"
+	ErrCodeRestoreExists           = "RESTORE_EXISTS"
+	ErrCodeRestoreListFailed       = "RESTORE_LIST_FAILED"
+	ErrCodeRestoreCreateFailed     = "RESTORE_CREATE_FAILED"
+	ErrCodeRestoreDeleteFailed     = "RESTORE_DELETE_FAILED"
+	ErrCodeRestoreNotReady         = "RESTORE_NOT_READY"
+	ErrCodeRestoreResultsNotFound  = "RESTORE_RESULTS_NOT_FOUND"
+	ErrCodeScheduleNotFound        = "SCHEDULE_NOT_FOUND"
+	ErrCodeScheduleGetFailed       = "SCHEDULE_GET_FAILED"
+	ErrCodeScheduleListFailed      = "SCHEDULE_LIST_FAILED"
+	ErrCodeScheduleUpdateFailed    = "SCHEDULE_UPDATE_FAILED"
+	ErrCodeClusterNotFound         = "CLUSTER_NOT_FOUND"
+	ErrCodeClusterConnectFailed    = "CLUSTER_CONNECT_FAILED"
+	ErrCodeClusterUpdateFailed     = "CLUSTER_UPDATE_FAILED"
+	ErrCodeInvalidCredentials      = "INVALID_CREDENTIALS"
+	ErrCodeCronJobNotFound         = "CRONJOB_NOT_FOUND"
+	ErrCodeCronJobGetFailed        = "CRONJOB_GET_FAILED"
+	ErrCodeCronJobListFailed       = "CRONJOB_LIST_FAILED"
+	ErrCodeJobListFailed           = "JOB_LIST_FAILED"
+	ErrCodeJobNotFound             = "JOB_NOT_FOUND"
+	ErrCodeJobGetFailed            = "JOB_GET_FAILED"
+	ErrCodePodListFailed           = "POD_LIST_FAILED"
+	ErrCodePodNotFound             = "POD_NOT_FOUND"
+	ErrCodePodNotStarted           = "POD_NOT_STARTED"
+	ErrCodeWaitTimeout             = "WAIT_TIMEOUT"
+	ErrCodeWaitFailed              = "WAIT_FAILED"
+	ErrCodeUserActivityGetFailed   = "USER_ACTIVITY_GET_FAILED"
+)
+
+var errorMessages = map[string]string{
+	ErrCodeVeleroNotInstalled:      "Velero not installed or CRDs not found",
+	ErrCodeVeleroVersionFailed:     "Failed to read the Velero server version",
+	ErrCodeInvalidRequest:          "Invalid request body",
+	ErrCodeInvalidQuery:            "Invalid query parameters",
+	ErrCodeInvalidName:             "Invalid name",
+	ErrCodeInvalidTimeout:          "Invalid timeout",
+	ErrCodeInvalidTTL:              "Invalid TTL",
+	ErrCodeInvalidHooks:            "Invalid hooks",
+	ErrCodeInvalidOrderedResources: "Invalid orderedResources",
+	ErrCodeInvalidResourceFilters:  "Invalid resource filters",
+	ErrCodeInvalidResourcePolicy:   "Invalid resource policy",
+	ErrCodeInvalidNamespaceMapping: "Invalid namespace mapping",
+	ErrCodeInvalidManifest:         "Invalid manifest",
+	ErrCodeInvalidMetadata:         "Invalid labels or annotations",
+	ErrCodeResourcePolicyGetFailed: "Failed to check resource policy",
+	ErrCodeNamespaceNotFound:       "Included namespaces do not exist",
+	ErrCodeNamespaceGetFailed:      "Failed to check included namespaces",
+	ErrCodeBackupNotFound:          "Backup not found",
+	ErrCodeBackupExists:            "Backup already exists",
+	ErrCodeBackupNotReady:          "Backup has not finished yet",
+	ErrCodeBackupNotCompleted:      "Backup did not complete successfully",
+	ErrCodeBackupGetFailed:         "Failed to get backup",
+	ErrCodeBackupListFailed:        "Failed to list backups",
+	ErrCodeNoCompletedBackup:       "Cluster has no completed backups",
+	ErrCodeBackupCreateFailed:      "Failed to create backup",
+	ErrCodeBackupDeleteFailed:      "Failed to delete backup",
+	ErrCodeBackupUpdateFailed:      "Failed to update backup",
+	ErrCodeResourceListNotFound:    "Resource list not found for backup",
+	ErrCodeBackupLogNotFound:       "Log not found for backup",
+	ErrCodeDeleteRequestListFailed: "Failed to list delete backup requests",
+	ErrCodeDownloadFailed:          "Failed to download from backup storage",
+	ErrCodeDownloadTimeout:         "Download request timed out",
+	ErrCodeRestoreNotFound:         "Restore not found",
+	ErrCodeRestoreGetFailed:        "Failed to get restore",
+	ErrCodeRestoreExists:           "Restore already exists",
+	ErrCodeRestoreListFailed:       "Failed to list restores",
+	ErrCodeRestoreCreateFailed:     "Failed to create restore",
+	ErrCodeRestoreDeleteFailed:     "Failed to delete restore",
+	ErrCodeRestoreNotReady:         "Restore has not finished yet",
+	ErrCodeRestoreResultsNotFound:  "Results not found for restore",
+	ErrCodeScheduleNotFound:        "Schedule not found",
+	ErrCodeScheduleGetFailed:       "Failed to get schedule",
+	ErrCodeScheduleListFailed:      "Failed to list schedules",
+	ErrCodeScheduleUpdateFailed:    "Failed to update schedule",
+	ErrCodeClusterNotFound:         "Cluster not found",
+	ErrCodeClusterConnectFailed:    "Failed to connect to cluster",
+	ErrCodeClusterUpdateFailed:     "Failed to update cluster credentials",
+	ErrCodeInvalidCredentials:      "Invalid cluster credentials",
+	ErrCodeCronJobNotFound:         "CronJob not found",
+	ErrCodeCronJobGetFailed:        "Failed to get CronJob",
+	ErrCodeCronJobListFailed:       "Failed to list CronJobs",
+	ErrCodeJobListFailed:           "Failed to list jobs",
+	ErrCodeJobNotFound:             "Job not found",
+	ErrCodeJobGetFailed:            "Failed to get job",
+	ErrCodePodListFailed:           "Failed to list pods",
+	ErrCodePodNotFound:             "No pods found for job",
+	ErrCodePodNotStarted:           "Job pod has not started yet",
+	ErrCodeWaitTimeout:             "Timed out waiting for a terminal phase",
+	ErrCodeWaitFailed:              "Failed while waiting for a terminal phase",
+	ErrCodeUserActivityGetFailed:   "Failed to read user activity",
+}
+
+// APIError is the JSON body of every error response
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
+	// Error repeats Message for clients that predate Code
+	Error string `json:"error"`
+}
+
+// respondError writes an APIError for code, using err (if any) as the details.
+// Server-side errors are logged with the request context.
+func respondError(c *gin.Context, status int, code string, err error) {
+	message, ok := errorMessages[code]
+	if !ok {
+		message = code
+	}
+
+	apiError := APIError{
+		Code:    code,
+		Message: message,
+		Error:   message,
+	}
+	if err != nil {
+		apiError.Details = err.Error()
+	}
+
+	if status >= 500 {
+		logRequestError(c, message, err)
+	}
+
+	c.AbortWithStatusJSON(status, apiError)
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
 	"log"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 	"velero-manager/pkg/config"
 	"velero-manager/pkg/k8s"
@@ -16,15 +18,34 @@ import (
 	"golang.org/x/oauth2"
 )
 
+// Delays between attempts to initialize an OIDC provider that was unreachable at startup
+const (
+	oidcRetryInitialDelay = 10 * time.Second
+	oidcRetryMaxDelay     = 5 * time.Minute
+)
+
+// OIDC states reported by GetAuthInfo
+const (
+	oidcStatusDisabled    = "disabled"
+	oidcStatusReady       = "ready"
+	oidcStatusUnavailable = "unavailable"
+)
+
 // AuthHandler handles authentication operations for both legacy and OIDC
 type AuthHandler struct {
-	k8sClient    *k8s.Client
-	userHandler  *UserHandler
-	oidcProvider *middleware.OIDCProvider
+	k8sClient   *k8s.Client
+	userHandler *UserHandler
+
+	// The provider is set once discovery succeeds, which may be after startup, and is
+	// replaced when the configuration is updated
+	oidcMutex    sync.RWMutex
 	oidcConfig   *config.OIDCConfig
+	oidcProvider *middleware.OIDCProvider
+	oidcError    string
 }
 
-// NewAuthHandler creates a new auth handler with optional OIDC support
+// NewAuthHandler creates a new auth handler with optional OIDC support. If the provider
+// can't be reached, legacy auth is used until a background retry initializes it
 func NewAuthHandler(k8sClient *k8s.Client, oidcConfig *config.OIDCConfig) (*AuthHandler, error) {
 	handler := &AuthHandler{
 		k8sClient:   k8sClient,
@@ -34,22 +55,127 @@ func NewAuthHandler(k8sClient *k8s.Client, oidcConfig *config.OIDCConfig) (*Auth
 
 	// Initialize OIDC provider if configured
 	if oidcConfig != nil && oidcConfig.IsValid() {
-		provider, err := middleware.NewOIDCProvider(oidcConfig)
-		if err != nil {
-			return nil, fmt.Errorf("failed to initialize OIDC provider: %v", err)
+		if err := handler.initOIDCProvider(oidcConfig); err != nil {
+			log.Printf("⚠️ OIDC provider unavailable, using legacy authentication until it can be reached: %v", err)
+			go handler.retryOIDCProvider(oidcConfig)
 		}
-		handler.oidcProvider = provider
 	}
 
 	return handler, nil
 }
 
+// initOIDCProvider runs OIDC discovery and, on success, switches the handler to OIDC,
+// unless oidcConfig has been replaced in the meantime
+func (h *AuthHandler) initOIDCProvider(oidcConfig *config.OIDCConfig) error {
+	provider, err := middleware.NewOIDCProvider(oidcConfig)
+
+	h.oidcMutex.Lock()
+	defer h.oidcMutex.Unlock()
+	if h.oidcConfig != oidcConfig {
+		if provider != nil {
+			provider.Close()
+		}
+		return nil
+	}
+	if err != nil {
+		h.oidcError = err.Error()
+		return err
+	}
+	h.oidcProvider = provider
+	h.oidcError = ""
+	return nil
+}
+
+// retryOIDCProvider retries initialization with exponential backoff until it succeeds or
+// the configuration is replaced
+func (h *AuthHandler) retryOIDCProvider(oidcConfig *config.OIDCConfig) {
+	delay := oidcRetryInitialDelay
+	for {
+		time.Sleep(delay)
+
+		if h.currentOIDCConfig() != oidcConfig {
+			return
+		}
+
+		err := h.initOIDCProvider(oidcConfig)
+		if err == nil {
+			log.Printf("✅ OIDC provider initialized, OIDC authentication is now enabled")
+			return
+		}
+
+		delay = min(delay*2, oidcRetryMaxDelay)
+		log.Printf("⚠️ OIDC provider still unavailable, retrying in %s: %v", delay, err)
+	}
+}
+
+// ReloadOIDCProvider applies an updated OIDC configuration to the running handler. The
+// new provider must complete discovery and serve signing keys before it replaces the
+// current one; on failure the current provider stays in place
+func (h *AuthHandler) ReloadOIDCProvider(ctx context.Context, oidcConfig *config.OIDCConfig) error {
+	var provider *middleware.OIDCProvider
+	if oidcConfig.IsValid() {
+		var err error
+		provider, err = middleware.NewOIDCProvider(oidcConfig)
+		if err != nil {
+			return err
+		}
+		if err := provider.CheckSigningKeys(ctx); err != nil {
+			provider.Close()
+			return err
+		}
+	}
+
+	h.oidcMutex.Lock()
+	previous := h.oidcProvider
+	h.oidcConfig = oidcConfig
+	h.oidcProvider = provider
+	h.oidcError = ""
+	h.oidcMutex.Unlock()
+
+	if previous != nil {
+		previous.Close()
+	}
+
+	if provider != nil {
+		log.Printf("✅ OIDC provider reloaded with issuer: %s", oidcConfig.IssuerURL)
+	} else {
+		log.Println("OIDC authentication disabled, using legacy authentication")
+	}
+	return nil
+}
+
+func (h *AuthHandler) currentOIDCConfig() *config.OIDCConfig {
+	h.oidcMutex.RLock()
+	defer h.oidcMutex.RUnlock()
+	return h.oidcConfig
+}
+
+// oidcStatus reports whether OIDC is disabled, ready or configured but not yet reachable
+func (h *AuthHandler) oidcStatus() (string, string) {
+	h.oidcMutex.RLock()
+	defer h.oidcMutex.RUnlock()
+
+	switch {
+	case h.oidcProvider != nil:
+		return oidcStatusReady, ""
+	case h.oidcError != "":
+		return oidcStatusUnavailable, h.oidcError
+	default:
+		return oidcStatusDisabled, ""
+	}
+}
+
 // GetAuthInfo returns current authentication configuration and user info
 func (h *AuthHandler) GetAuthInfo(c *gin.Context) {
+	status, oidcError := h.oidcStatus()
 	info := gin.H{
-		"oidcEnabled":       h.oidcConfig != nil && h.oidcConfig.Enabled,
+		"oidcEnabled":       status == oidcStatusReady,
+		"oidcStatus":        status,
 		"legacyAuthEnabled": true, // Always available as fallback
 	}
+	if oidcError != "" {
+		info["oidcError"] = oidcError
+	}
 
 	// If user is authenticated, add user info
 	if username := c.GetString("username"); username != "" {
@@ -65,7 +191,8 @@ func (h *AuthHandler) GetAuthInfo(c *gin.Context) {
 
 // InitiateOIDCLogin starts the OIDC authentication flow
 func (h *AuthHandler) InitiateOIDCLogin(c *gin.Context) {
-	if h.oidcProvider == nil || !h.oidcConfig.Enabled {
+	oidcProvider := h.GetOIDCProvider()
+	if oidcProvider == nil || !oidcProvider.Config.Enabled {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "OIDC authentication not enabled"})
 		return
 	}
@@ -82,7 +209,7 @@ func (h *AuthHandler) InitiateOIDCLogin(c *gin.Context) {
 	storeState(c, state)
 
 	// Get authorization URL
-	authURL := h.oidcProvider.OAuth2Config.AuthCodeURL(state, oauth2.AccessTypeOffline)
+	authURL := oidcProvider.OAuth2Config.AuthCodeURL(state, oauth2.AccessTypeOffline)
 
 	c.JSON(http.StatusOK, gin.H{
 		"authUrl": authURL,
@@ -92,7 +219,8 @@ func (h *AuthHandler) InitiateOIDCLogin(c *gin.Context) {
 
 // HandleOIDCCallback handles the OIDC callback after successful authentication
 func (h *AuthHandler) HandleOIDCCallback(c *gin.Context) {
-	if h.oidcProvider == nil || !h.oidcConfig.Enabled {
+	oidcProvider := h.GetOIDCProvider()
+	if oidcProvider == nil || !oidcProvider.Config.Enabled {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "OIDC authentication not enabled"})
 		return
 	}
@@ -113,7 +241,7 @@ func (h *AuthHandler) HandleOIDCCallback(c *gin.Context) {
 	}
 
 	// Exchange code for tokens
-	oauth2Token, err := h.oidcProvider.OAuth2Config.Exchange(c.Request.Context(), code)
+	oauth2Token, err := oidcProvider.OAuth2Config.Exchange(oidcProvider.ClientContext(c.Request.Context()), code)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to exchange code for token"})
 		return
@@ -127,19 +255,19 @@ func (h *AuthHandler) HandleOIDCCallback(c *gin.Context) {
 	}
 
 	// Verify and extract user info
-	userInfo, err := h.oidcProvider.ValidateOIDCToken(rawIDToken)
+	userInfo, err := oidcProvider.ValidateOIDCToken(rawIDToken)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate ID token"})
 		return
 	}
 
 	// SECURITY: Block users without proper roles
-	if userInfo.MappedRole == "no-access" || userInfo.MappedRole == "" {
+	if userInfo.MappedRole == config.NoAccessRole || userInfo.MappedRole == "" {
 		log.Printf("Access denied for user %s - no valid role assigned (roles: %v, groups: %v)",
 			userInfo.Username, userInfo.Roles, userInfo.Groups)
 
 		// Redirect to login page with error message
-		errorMsg := "Access denied. You need velero-user or velero-admin role in Keycloak."
+		errorMsg := "Access denied. Your account has no Velero Manager role in Keycloak."
 		redirectURL := fmt.Sprintf("/login?error=%s", errorMsg)
 		c.Redirect(http.StatusFound, redirectURL)
 		return
@@ -147,6 +275,7 @@ func (h *AuthHandler) HandleOIDCCallback(c *gin.Context) {
 
 	// Log successful authentication
 	log.Printf("User %s authenticated successfully with role: %s", userInfo.Username, userInfo.MappedRole)
+	h.userHandler.recordLogin(userInfo.Username)
 
 	// Create JWT token for client
 	jwtToken, err := middleware.CreateJWTToken(userInfo.Username, userInfo.MappedRole)
@@ -191,9 +320,9 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	// If OIDC is enabled, provide logout URL
 	response := gin.H{"message": "Logged out successfully"}
 
-	if h.oidcProvider != nil && h.oidcConfig.Enabled {
+	if oidcProvider := h.GetOIDCProvider(); oidcProvider != nil && oidcProvider.Config.Enabled {
 		// Construct Keycloak logout URL properly
-		issuerURL := h.oidcConfig.IssuerURL
+		issuerURL := oidcProvider.Config.IssuerURL
 		// Remove trailing slash if present
 		issuerURL = strings.TrimSuffix(issuerURL, "/")
 
@@ -215,21 +344,25 @@ func generateSecureState() (string, error) {
 }
 
 // Simple in-memory state storage (use Redis/DB in production)
-var stateStore = make(map[string]time.Time)
+var (
+	stateStore = make(map[string]time.Time)
+	stateMutex = sync.Mutex{}
+)
 
 func storeState(c *gin.Context, state string) {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
 	// Store with expiration (10 minutes)
 	stateStore[state] = time.Now().Add(10 * time.Minute)
 
 	// Clean expired states
-	go func() {
-		now := time.Now()
-		for s, expiry := range stateStore {
-			if now.After(expiry) {
-				delete(stateStore, s)
-			}
+	now := time.Now()
+	for s, expiry := range stateStore {
+		if now.After(expiry) {
+			delete(stateStore, s)
 		}
-	}()
+	}
 }
 
 func verifyState(c *gin.Context, state string) bool {
@@ -237,22 +370,23 @@ func verifyState(c *gin.Context, state string) bool {
 		return false
 	}
 
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
 	expiry, exists := stateStore[state]
 	if !exists {
 		return false
 	}
 
-	if time.Now().After(expiry) {
-		delete(stateStore, state)
-		return false
-	}
-
 	// Remove state after verification (single use)
 	delete(stateStore, state)
-	return true
+
+	return !time.Now().After(expiry)
 }
 
-// GetOIDCProvider returns the OIDC provider (for use in main.go)
+// GetOIDCProvider returns the OIDC provider, or nil while OIDC is disabled or unreachable
 func (h *AuthHandler) GetOIDCProvider() *middleware.OIDCProvider {
+	h.oidcMutex.RLock()
+	defer h.oidcMutex.RUnlock()
 	return h.oidcProvider
 }
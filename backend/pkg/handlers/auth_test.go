@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+	"velero-manager/pkg/config"
+	"velero-manager/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+)
+
+// newTestOIDCAuthHandler returns an auth handler with a ready OIDC provider that is never
+// contacted; starting a login only builds the authorization URL
+func newTestOIDCAuthHandler() *AuthHandler {
+	return &AuthHandler{
+		oidcProvider: &middleware.OIDCProvider{
+			Config: &config.OIDCConfig{Enabled: true},
+			OAuth2Config: &oauth2.Config{
+				ClientID:    "velero-manager",
+				Endpoint:    oauth2.Endpoint{AuthURL: "https://keycloak.example.com/auth"},
+				RedirectURL: "https://velero.example.com/auth/callback",
+			},
+		},
+	}
+}
+
+func TestConcurrentOIDCLoginStates(t *testing.T) {
+	handler := newTestOIDCAuthHandler()
+	router := gin.New()
+	router.GET("/api/v1/auth/oidc/login", handler.InitiateOIDCLogin)
+	router.GET("/verify", func(c *gin.Context) {
+		if verifyState(c, c.Query("state")) {
+			c.Status(http.StatusOK)
+		} else {
+			c.Status(http.StatusBadRequest)
+		}
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// An abandoned login, cleaned up by the logins below
+	stateMutex.Lock()
+	stateStore["expired-state"] = time.Now().Add(-time.Minute)
+	stateMutex.Unlock()
+
+	const logins = 20
+	states := make(chan string, logins)
+	var wg sync.WaitGroup
+	for i := 0; i < logins; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Get(server.URL + "/api/v1/auth/oidc/login")
+			if err != nil {
+				t.Errorf("login: %v", err)
+				return
+			}
+			defer resp.Body.Close()
+
+			var body struct {
+				AuthURL string `json:"authUrl"`
+				State   string `json:"state"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || resp.StatusCode != http.StatusOK {
+				t.Errorf("login: status %d, %v", resp.StatusCode, err)
+				return
+			}
+			authURL, err := url.Parse(body.AuthURL)
+			if err != nil || authURL.Query().Get("state") != body.State {
+				t.Errorf("authUrl %q doesn't carry state %q", body.AuthURL, body.State)
+			}
+			states <- body.State
+		}()
+	}
+	wg.Wait()
+	close(states)
+
+	stateMutex.Lock()
+	_, expiredKept := stateStore["expired-state"]
+	stateMutex.Unlock()
+	if expiredKept {
+		t.Error("expired state was not cleaned up")
+	}
+
+	// Every state verifies exactly once, also when callbacks race
+	seen := make(map[string]bool)
+	for state := range states {
+		if seen[state] {
+			t.Errorf("state %s handed out twice", state)
+		}
+		seen[state] = true
+
+		results := make(chan int, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				resp, err := http.Get(server.URL + "/verify?state=" + url.QueryEscape(state))
+				if err != nil {
+					t.Errorf("verify: %v", err)
+					results <- 0
+					return
+				}
+				resp.Body.Close()
+				results <- resp.StatusCode
+			}()
+		}
+		if first, second := <-results, <-results; first+second != http.StatusOK+http.StatusBadRequest {
+			t.Errorf("state %s verified with statuses %d and %d, want one success", state, first, second)
+		}
+	}
+	if len(seen) != logins {
+		t.Errorf("got %d states from %d logins", len(seen), logins)
+	}
+}
+
+func TestVerifyStateRejectsUnknownAndExpiredStates(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	stateMutex.Lock()
+	stateStore["stale-state"] = time.Now().Add(-time.Second)
+	stateMutex.Unlock()
+
+	for _, state := range []string{"", "never-issued", "stale-state"} {
+		if verifyState(c, state) {
+			t.Errorf("state %q verified", state)
+		}
+	}
+
+	stateMutex.Lock()
+	_, kept := stateStore["stale-state"]
+	stateMutex.Unlock()
+	if kept {
+		t.Error("expired state kept after a verification attempt")
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+// clusterBackupSettings are the backup settings AddCluster baked into a cluster's CronJob
+type clusterBackupSettings struct {
+	TTL                string
+	StorageLocation    string
+	ExcludedNamespaces []string
+}
+
+var (
+	backupCommandTTL      = regexp.MustCompile(`(?m)^\s*ttl:\s*(\S+)\s*$`)
+	backupCommandLocation = regexp.MustCompile(`(?m)^\s*storageLocation:\s*(\S+)\s*$`)
+	backupCommandExcluded = regexp.MustCompile(`(?m)^\s*excludedNamespaces:\s*$((?:\n\s*-\s*.+)+)`)
+)
+
+// parseClusterBackupCommand reads the settings back out of a command rendered by
+// buildClusterBackupCommand, using AddCluster's defaults for anything missing
+func parseClusterBackupCommand(command string) clusterBackupSettings {
+	settings := clusterBackupSettings{
+		TTL:             "720h",
+		StorageLocation: "default",
+	}
+
+	if match := backupCommandTTL.FindStringSubmatch(command); match != nil {
+		settings.TTL = match[1]
+	}
+	if match := backupCommandLocation.FindStringSubmatch(command); match != nil {
+		settings.StorageLocation = match[1]
+	}
+	if match := backupCommandExcluded.FindStringSubmatch(command); match != nil {
+		for _, line := range strings.Split(strings.TrimSpace(match[1]), "\n") {
+			namespace := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "-"))
+			settings.ExcludedNamespaces = append(settings.ExcludedNamespaces, strings.Trim(namespace, `"`))
+		}
+	}
+
+	return settings
+}
+
+// clusterBackupSettings finds the backup settings of a cluster from its CronJob
+func (h *VeleroHandler) clusterBackupSettings(clusterName string) (clusterBackupSettings, error) {
+	cronJob, err := h.k8sClient.DynamicClient.
+		Resource(k8s.CronJobGVR).
+		Namespace("velero").
+		Get(h.k8sClient.Context, fmt.Sprintf("backup-%s-daily", clusterName), metav1.GetOptions{})
+
+	if err != nil {
+		return clusterBackupSettings{}, err
+	}
+
+	containers, _, _ := unstructured.NestedSlice(cronJob.Object,
+		"spec", "jobTemplate", "spec", "template", "spec", "containers")
+	for _, container := range containers {
+		command, _, _ := unstructured.NestedStringSlice(container.(map[string]interface{}), "command")
+		if len(command) > 0 {
+			return parseClusterBackupCommand(command[len(command)-1]), nil
+		}
+	}
+
+	return parseClusterBackupCommand(""), nil
+}
+
+// clusterDynamicClient connects to a managed cluster with the credentials AddCluster stored
+func (h *VeleroHandler) clusterDynamicClient(clusterName string) (dynamic.Interface, error) {
+	secret, err := h.k8sClient.Clientset.CoreV1().
+		Secrets("velero").
+		Get(h.k8sClient.Context, fmt.Sprintf("%s-sa-token", clusterName), metav1.GetOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamic.NewForConfig(clusterRestConfig(secret.Data["server"], secret.Data["token"], secret.Data["ca.crt"]))
+}
+
+// clusterRestConfig is the client config for a managed cluster's API server
+func clusterRestConfig(server, token, caData []byte) *rest.Config {
+	return &rest.Config{
+		Host:        string(server),
+		BearerToken: string(token),
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: caData,
+		},
+		Timeout: 30 * time.Second,
+	}
+}
+
+// TriggerClusterBackup creates a Velero Backup on a managed cluster right away, with the
+// same settings as its scheduled backups. It shows up here once Velero syncs it from the
+// shared storage location.
+func (h *VeleroHandler) TriggerClusterBackup(c *gin.Context) {
+	clusterName := c.Param("cluster")
+
+	settings, err := h.clusterBackupSettings(clusterName)
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusNotFound, ErrCodeClusterNotFound, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeCronJobGetFailed, err)
+		return
+	}
+
+	clusterClient, err := h.clusterDynamicClient(clusterName)
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusNotFound, ErrCodeClusterNotFound, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeClusterConnectFailed, err)
+		return
+	}
+
+	backupName := fmt.Sprintf("%s-manual-%s", clusterName, time.Now().UTC().Format("20060102150405"))
+	spec := map[string]interface{}{
+		"ttl":                settings.TTL,
+		"storageLocation":    settings.StorageLocation,
+		"includedNamespaces": []interface{}{"*"},
+	}
+	if len(settings.ExcludedNamespaces) > 0 {
+		excluded := make([]interface{}, 0, len(settings.ExcludedNamespaces))
+		for _, namespace := range settings.ExcludedNamespaces {
+			excluded = append(excluded, namespace)
+		}
+		spec["excludedNamespaces"] = excluded
+	}
+
+	backup := map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       "Backup",
+		"metadata": map[string]interface{}{
+			"name":      backupName,
+			"namespace": "velero",
+			"labels": map[string]interface{}{
+				"velero.io/cluster":   clusterName,
+				"velero.io/triggered": "manual",
+			},
+		},
+		"spec": spec,
+	}
+
+	result, err := clusterClient.
+		Resource(k8s.BackupGVR).
+		Namespace("velero").
+		Create(c.Request.Context(), &unstructured.Unstructured{Object: backup}, metav1.CreateOptions{})
+
+	if apierrors.IsAlreadyExists(err) {
+		respondError(c, http.StatusConflict, ErrCodeBackupExists, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusBadGateway, ErrCodeBackupCreateFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":         "Backup created successfully",
+		"backup":          result.GetName(),
+		"cluster":         clusterName,
+		"storageLocation": settings.StorageLocation,
+		"ttl":             settings.TTL,
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// RotateClusterToken replaces the token, and optionally the CA certificate, that a
+// cluster's backup CronJob uses. The new credentials must reach the cluster's Velero
+// before they're stored, and the CronJob is left alone, so rotation causes no downtime.
+func (h *VeleroHandler) RotateClusterToken(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	clusterName := c.Param("cluster")
+
+	var request struct {
+		Token string `json:"token" binding:"required"`
+		// Base64 encoded like in AddCluster; empty keeps the current CA
+		CACert string `json:"caCert"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, err)
+		return
+	}
+
+	var caData []byte
+	if request.CACert != "" {
+		decoded, err := base64.StdEncoding.DecodeString(request.CACert)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest,
+				fmt.Errorf("CA certificate must be base64 encoded"))
+			return
+		}
+		caData = decoded
+	}
+
+	secretName := fmt.Sprintf("%s-sa-token", clusterName)
+	secrets := h.k8sClient.Clientset.CoreV1().Secrets("velero")
+
+	secret, err := secrets.Get(ctx, secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusNotFound, ErrCodeClusterNotFound, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeClusterConnectFailed, err)
+		return
+	}
+	if caData == nil {
+		caData = secret.Data["ca.crt"]
+	}
+
+	// Only store credentials that can actually list the cluster's backups
+	probeClient, err := dynamic.NewForConfig(clusterRestConfig(secret.Data["server"], []byte(request.Token), caData))
+	if err == nil {
+		_, err = probeClient.Resource(k8s.BackupGVR).
+			Namespace("velero").
+			List(ctx, metav1.ListOptions{Limit: 1})
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidCredentials,
+			fmt.Errorf("new credentials can't list backups on cluster %s: %v", clusterName, err))
+		return
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := secrets.Get(ctx, secretName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if current.Data == nil {
+			current.Data = map[string][]byte{}
+		}
+		current.Data["token"] = []byte(request.Token)
+		current.Data["ca.crt"] = caData
+
+		_, err = secrets.Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeClusterUpdateFailed, err)
+		return
+	}
+
+	response := gin.H{
+		"message":   "Cluster token rotated",
+		"cluster":   clusterName,
+		"secret":    secretName,
+		"caUpdated": request.CACert != "",
+		"expiresAt": nil,
+	}
+	if expiresAt := tokenExpiry(request.Token); expiresAt != nil {
+		response["expiresAt"] = expiresAt
+		response["expiresIn"] = time.Until(*expiresAt).Round(time.Minute).String()
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// tokenExpiry reads the exp claim of a service account token. The signature can't be
+// checked here and doesn't need to be, the cluster just accepted the token. Legacy
+// secret-based tokens don't expire and return nil.
+func tokenExpiry(token string) *time.Time {
+	claims := jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil {
+		return nil
+	}
+	if claims.ExpiresAt == nil {
+		return nil
+	}
+	return &claims.ExpiresAt.Time
+}
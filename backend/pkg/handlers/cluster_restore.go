@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// RestoreLatestBackup restores the newest Completed backup of a cluster, so operators
+// don't have to look its name up first. The body is optional and takes the same
+// options as CreateRestore plus an optional restore name.
+func (h *VeleroHandler) RestoreLatestBackup(c *gin.Context) {
+	clusterName := c.Param("cluster")
+
+	var request struct {
+		Name string `json:"name,omitempty"`
+		RestoreOptions
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, err)
+		return
+	}
+
+	if request.Name == "" {
+		request.Name = fmt.Sprintf("%s-restore-%s", clusterName, time.Now().UTC().Format("20060102150405"))
+	}
+	if errs := validation.IsDNS1123Subdomain(request.Name); len(errs) > 0 {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidName, fmt.Errorf("%s", strings.Join(errs, "; ")))
+		return
+	}
+
+	if request.Hooks != nil {
+		if err := request.Hooks.validate(); err != nil {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidHooks, err)
+			return
+		}
+	}
+
+	if err := validateNamespaceMapping(request.NamespaceMapping); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidNamespaceMapping, err)
+		return
+	}
+
+	backup, err := h.latestCompletedBackup(clusterName)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeBackupListFailed, err)
+		return
+	}
+	if backup == nil {
+		respondError(c, http.StatusNotFound, ErrCodeNoCompletedBackup,
+			fmt.Errorf("no completed backup found for cluster %s", clusterName))
+		return
+	}
+
+	restore := request.RestoreOptions.restoreObject(request.Name, backup.GetName())
+
+	// Attribute the restore to the cluster even when no target cluster was given
+	restoreObject := unstructured.Unstructured{Object: restore}
+	labels := restoreObject.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[k8s.SourceClusterLabel] = clusterName
+	restoreObject.SetLabels(labels)
+
+	h.submitRestore(c, restoreObject.Object, gin.H{
+		"message":       "Restore of latest backup created successfully",
+		"cluster":       clusterName,
+		"backup":        backup.GetName(),
+		"backupCreated": backup.GetCreationTimestamp(),
+		"status":        "created",
+	})
+}
+
+// latestCompletedBackup returns the newest Completed backup of a cluster, or nil if it
+// has none
+func (h *VeleroHandler) latestCompletedBackup(clusterName string) (*unstructured.Unstructured, error) {
+	backups, err := h.backupsForCluster(h.k8sClient.Context, clusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *unstructured.Unstructured
+	for i := range backups {
+		backup := &backups[i]
+		if phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase"); phase != "Completed" {
+			continue
+		}
+		if latest == nil || backup.GetCreationTimestamp().After(latest.GetCreationTimestamp().Time) {
+			latest = backup
+		}
+	}
+	return latest, nil
+}
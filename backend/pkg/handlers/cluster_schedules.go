@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	scheduleTypeCronJob = "CronJob"
+	scheduleTypeVelero  = "Schedule"
+)
+
+// ClusterSchedule is a backup CronJob or Velero Schedule of a cluster
+type ClusterSchedule struct {
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	Schedule  string     `json:"schedule"`
+	Suspended bool       `json:"suspended"`
+	NextRun   *time.Time `json:"nextRun"`
+	// Set when the schedule expression can't be parsed
+	NextRunError string `json:"nextRunError,omitempty"`
+}
+
+// ListClusterSchedules returns every way a cluster is backed up on a schedule: the
+// CronJobs AddCluster created, matched by name, and Velero Schedules, matched by label
+func (h *VeleroHandler) ListClusterSchedules(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	clusterName := c.Param("cluster")
+
+	cronJobList, err := h.k8sClient.ListCache.List(ctx, k8s.CronJobGVR, "velero")
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeCronJobListFailed, err)
+		return
+	}
+
+	scheduleList, err := h.k8sClient.ListCache.List(ctx, k8s.ScheduleGVR, "velero")
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeScheduleListFailed, err)
+		return
+	}
+
+	now := time.Now()
+	schedules := []ClusterSchedule{}
+
+	for _, cronJob := range cronJobList.Items {
+		if extractClusterFromCronJobName(cronJob.GetName()) != clusterName {
+			continue
+		}
+
+		expression, _, _ := unstructured.NestedString(cronJob.Object, "spec", "schedule")
+		suspended, _, _ := unstructured.NestedBool(cronJob.Object, "spec", "suspend")
+		if timeZone, _, _ := unstructured.NestedString(cronJob.Object, "spec", "timeZone"); timeZone != "" {
+			expression = fmt.Sprintf("CRON_TZ=%s %s", timeZone, expression)
+		}
+		schedules = append(schedules, newClusterSchedule(cronJob.GetName(), scheduleTypeCronJob, expression, suspended, now))
+	}
+
+	for _, schedule := range scheduleList.Items {
+		if k8s.ScheduleCluster(&schedule) != clusterName {
+			continue
+		}
+
+		expression, _, _ := unstructured.NestedString(schedule.Object, "spec", "schedule")
+		paused, _, _ := unstructured.NestedBool(schedule.Object, "spec", "paused")
+		schedules = append(schedules, newClusterSchedule(schedule.GetName(), scheduleTypeVelero, expression, paused, now))
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		if schedules[i].Type != schedules[j].Type {
+			return schedules[i].Type < schedules[j].Type
+		}
+		return schedules[i].Name < schedules[j].Name
+	})
+
+	c.JSON(http.StatusOK, ClusterScheduleList{Cluster: clusterName, Schedules: schedules, Count: len(schedules)})
+}
+
+// newClusterSchedule builds a ClusterSchedule, working out the next run of a schedule
+// that isn't suspended
+func newClusterSchedule(name, scheduleType, expression string, suspended bool, now time.Time) ClusterSchedule {
+	schedule := ClusterSchedule{
+		Name:      name,
+		Type:      scheduleType,
+		Schedule:  expression,
+		Suspended: suspended,
+	}
+	if suspended {
+		return schedule
+	}
+
+	parsed, err := k8s.ParseSchedule(expression)
+	if err != nil {
+		schedule.NextRunError = fmt.Sprintf("Invalid schedule %q: %v", expression, err)
+	} else if next := parsed.Next(now); !next.IsZero() {
+		schedule.NextRun = &next
+	}
+	return schedule
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"velero-manager/pkg/k8s"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ClusterInfo is a managed cluster, discovered from its backup CronJob
+type ClusterInfo struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	CronJob     string       `json:"cronJob"`
+	SecretName  string       `json:"secretName"`
+	BackupCount int          `json:"backupCount"`
+	LastBackup  *metav1.Time `json:"lastBackup"`
+}
+
+// discoverClusters returns every cluster with a backup CronJob, sorted by name, with the
+// count and time of its latest backup
+func (h *VeleroHandler) discoverClusters() ([]ClusterInfo, error) {
+	cronJobList, err := h.k8sClient.ListCache.List(h.k8sClient.Context, k8s.CronJobGVR, "velero")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+
+	h.mutex.RLock()
+	clusterMap := make(map[string]*ClusterInfo)
+	for _, cronJob := range cronJobList.Items {
+		clusterName := extractClusterFromCronJobName(cronJob.GetName())
+		if clusterName == k8s.UnknownCluster || clusterName == "" {
+			continue
+		}
+		clusterMap[clusterName] = &ClusterInfo{
+			Name:        clusterName,
+			Description: h.clusterDescriptions[clusterName],
+			CronJob:     cronJob.GetName(),
+			SecretName:  cronJobSecretName(&cronJob, clusterName),
+		}
+	}
+	h.mutex.RUnlock()
+
+	h.countBackups(clusterMap)
+
+	clusters := make([]ClusterInfo, 0, len(clusterMap))
+	for _, cluster := range clusterMap {
+		clusters = append(clusters, *cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	return clusters, nil
+}
+
+// clusterDetails returns a cluster by name. A cluster without a backup CronJob, such as
+// one whose CronJob was removed, is still reported with its backups and an empty CronJob.
+func (h *VeleroHandler) clusterDetails(clusterName string) (ClusterInfo, error) {
+	clusters, err := h.discoverClusters()
+	if err != nil {
+		return ClusterInfo{}, err
+	}
+	for _, cluster := range clusters {
+		if cluster.Name == clusterName {
+			return cluster, nil
+		}
+	}
+
+	h.mutex.RLock()
+	cluster := &ClusterInfo{
+		Name:        clusterName,
+		Description: h.clusterDescriptions[clusterName],
+		SecretName:  fmt.Sprintf("%s-credentials", clusterName),
+	}
+	h.mutex.RUnlock()
+	h.countBackups(map[string]*ClusterInfo{clusterName: cluster})
+
+	return *cluster, nil
+}
+
+// countBackups sets the backup count and latest backup time of each cluster in
+// clusterMap. Counts are left at zero if backups can't be listed.
+func (h *VeleroHandler) countBackups(clusterMap map[string]*ClusterInfo) {
+	backupList, err := h.k8sClient.ListCache.List(h.k8sClient.Context, k8s.BackupGVR, "velero")
+	if err != nil {
+		return
+	}
+
+	for _, backup := range backupList.Items {
+		cluster, exists := clusterMap[k8s.BackupCluster(&backup)]
+		if !exists {
+			continue
+		}
+
+		cluster.BackupCount++
+		backupTime := backup.GetCreationTimestamp()
+		if cluster.LastBackup == nil || backupTime.After(cluster.LastBackup.Time) {
+			cluster.LastBackup = &backupTime
+		}
+	}
+}
+
+// cronJobSecretName returns the secret mounted by a cluster's backup CronJob, falling
+// back to the <cluster>-credentials naming pattern
+func cronJobSecretName(cronJob *unstructured.Unstructured, clusterName string) string {
+	volumes, _, _ := unstructured.NestedSlice(cronJob.Object,
+		"spec", "jobTemplate", "spec", "template", "spec", "volumes")
+	if len(volumes) > 0 {
+		if volume, ok := volumes[0].(map[string]interface{}); ok {
+			if name, found, _ := unstructured.NestedString(volume, "secret", "secretName"); found {
+				return name
+			}
+		}
+	}
+	return fmt.Sprintf("%s-credentials", clusterName)
+}
+
+// backupsForCluster returns a cluster's backups from the informers' cluster index, so
+// per-cluster views don't scan every backup of every cluster
+func (h *VeleroHandler) backupsForCluster(ctx context.Context, clusterName string) ([]unstructured.Unstructured, error) {
+	list, err := h.k8sClient.ListCache.ListByCluster(ctx, k8s.BackupGVR, "velero", clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// restoresForCluster returns a cluster's restores from the informers' cluster index
+func (h *VeleroHandler) restoresForCluster(ctx context.Context, clusterName string) ([]unstructured.Unstructured, error) {
+	list, err := h.k8sClient.ListCache.ListByCluster(ctx, k8s.RestoreGVR, "velero", clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newClusterBackup(name, cluster string, created time.Time) *unstructured.Unstructured {
+	backup := newTestBackup(name, map[string]string{"velero.io/cluster": cluster}, nil)
+	backup.SetCreationTimestamp(metav1.NewTime(created))
+	return backup
+}
+
+func TestClusterCallersAgree(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	prod := newTestCronJob("velero", "backup-prod-daily")
+	unstructured.SetNestedSlice(prod.Object, []interface{}{
+		map[string]interface{}{"name": "kubeconfig", "secret": map[string]interface{}{"secretName": "prod-kubeconfig"}},
+	}, "spec", "jobTemplate", "spec", "template", "spec", "volumes")
+
+	client := newTestClient(
+		prod,
+		newTestCronJob("velero", "backup-staging-daily"),
+		newClusterBackup("prod-manual-1", "prod", now.Add(-2*time.Hour)),
+		newClusterBackup("prod-manual-2", "prod", now.Add(-time.Hour)),
+		// legacy's CronJob was removed, but its backups remain
+		newClusterBackup("legacy-manual-1", "legacy", now.Add(-3*time.Hour)),
+	)
+	handler := NewVeleroHandler(client, nil)
+
+	w := serve(handler.ListClusters, http.MethodGet, "/api/v1/clusters", nil, nil, "viewer")
+	assertStatus(t, w, http.StatusOK)
+	listed := map[string]map[string]interface{}{}
+	clusters, _ := decodeBody(t, w)["clusters"].([]interface{})
+	for _, item := range clusters {
+		cluster, _ := item.(map[string]interface{})
+		name, _ := cluster["name"].(string)
+		listed[name] = cluster
+	}
+	if len(listed) != 2 || listed["prod"] == nil || listed["staging"] == nil {
+		t.Fatalf("listed clusters = %v, want prod and staging", clusters)
+	}
+
+	for name, want := range listed {
+		w := serve(handler.GetClusterDetails, http.MethodGet, "/api/v1/clusters/"+name, nil,
+			gin.Params{{Key: "cluster", Value: name}}, "viewer")
+		assertStatus(t, w, http.StatusOK)
+		details := decodeBody(t, w)
+		for _, key := range []string{"secretName", "backupCount", "lastBackup", "description"} {
+			if details[key] != want[key] {
+				t.Errorf("%s: details %s = %v, cluster list has %v", name, key, details[key], want[key])
+			}
+		}
+		if details["cronJob"] != true {
+			t.Errorf("%s: details cronJob = %v, want true", name, details["cronJob"])
+		}
+	}
+	if listed["prod"]["secretName"] != "prod-kubeconfig" || listed["prod"]["backupCount"] != float64(2) ||
+		listed["prod"]["lastBackup"] != now.Add(-time.Hour).UTC().Format(time.RFC3339) {
+		t.Errorf("prod = %v, want 2 backups, the latest an hour ago, and the CronJob's secret", listed["prod"])
+	}
+
+	w = serve(handler.GetDashboardMetrics, http.MethodGet, "/api/v1/dashboard/metrics", nil, nil, "viewer")
+	assertStatus(t, w, http.StatusOK)
+	dashboardClusters, _ := decodeBody(t, w)["clusters"].(map[string]interface{})
+	if dashboardClusters["total"] != float64(len(listed)) {
+		t.Errorf("dashboard clusters total = %v, cluster list has %d", dashboardClusters["total"], len(listed))
+	}
+
+	w = serve(handler.GetClusterDetails, http.MethodGet, "/api/v1/clusters/legacy", nil,
+		gin.Params{{Key: "cluster", Value: "legacy"}}, "viewer")
+	assertStatus(t, w, http.StatusOK)
+	legacy := decodeBody(t, w)
+	if legacy["cronJob"] != false || legacy["backupCount"] != float64(1) || legacy["secretName"] != "legacy-credentials" {
+		t.Errorf("legacy = %v, want 1 backup, no CronJob and the default secret", legacy)
+	}
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Label Kubernetes sets on the pods of a Job
+const jobNameLabel = "job-name"
+
+// Job run statuses
+const (
+	jobStatusRunning   = "Running"
+	jobStatusSucceeded = "Succeeded"
+	jobStatusFailed    = "Failed"
+	jobStatusPending   = "Pending"
+)
+
+// CronJobRun is one Job spawned by a CronJob, scheduled or triggered manually
+type CronJobRun struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	Triggered  string     `json:"triggered"` // "schedule" or "manual"
+	StartTime  *time.Time `json:"startTime,omitempty"`
+	FinishTime *time.Time `json:"finishTime,omitempty"`
+	Succeeded  int32      `json:"succeeded"`
+	Failed     int32      `json:"failed"`
+	Active     int32      `json:"active"`
+	Message    string     `json:"message,omitempty"`
+	PodPhases  []string   `json:"podPhases"`
+}
+
+// isCronJobRun reports whether a Job was spawned by the CronJob, either by the CronJob
+// controller (owner reference) or by TriggerCronJob (cronjob-name label)
+func isCronJobRun(job *batchv1.Job, cronJobName string) bool {
+	if job.Labels["cronjob-name"] == cronJobName {
+		return true
+	}
+	for _, owner := range job.OwnerReferences {
+		if owner.Kind == "CronJob" && owner.Name == cronJobName {
+			return true
+		}
+	}
+	return false
+}
+
+// cronJobRun summarizes a Job and the phases of its pods
+func cronJobRun(job *batchv1.Job, pods []corev1.Pod) CronJobRun {
+	run := CronJobRun{
+		Name:      job.Name,
+		Status:    jobStatusPending,
+		Triggered: "schedule",
+		Succeeded: job.Status.Succeeded,
+		Failed:    job.Status.Failed,
+		Active:    job.Status.Active,
+		PodPhases: make([]string, 0, len(pods)),
+	}
+	if job.Labels["velero.io/triggered"] == "manual" {
+		run.Triggered = "manual"
+	}
+	if job.Status.StartTime != nil {
+		run.StartTime = &job.Status.StartTime.Time
+	}
+	if job.Status.Active > 0 {
+		run.Status = jobStatusRunning
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			run.Status = jobStatusSucceeded
+		case batchv1.JobFailed:
+			run.Status = jobStatusFailed
+			run.Message = condition.Message
+		default:
+			continue
+		}
+		finishTime := condition.LastTransitionTime.Time
+		run.FinishTime = &finishTime
+	}
+	if job.Status.CompletionTime != nil {
+		run.FinishTime = &job.Status.CompletionTime.Time
+	}
+
+	for _, pod := range pods {
+		run.PodPhases = append(run.PodPhases, string(pod.Status.Phase))
+	}
+
+	return run
+}
+
+// ListCronJobRuns lists the Jobs a CronJob has spawned with their outcome, newest first
+func (h *VeleroHandler) ListCronJobRuns(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	cronJobName := c.Param("name")
+
+	_, err := h.k8sClient.Clientset.BatchV1().
+		CronJobs("velero").
+		Get(ctx, cronJobName, metav1.GetOptions{})
+
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusNotFound, ErrCodeCronJobNotFound, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeCronJobGetFailed, err)
+		return
+	}
+
+	jobList, err := h.k8sClient.Clientset.BatchV1().
+		Jobs("velero").
+		List(ctx, metav1.ListOptions{})
+
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeJobListFailed, err)
+		return
+	}
+
+	podList, err := h.k8sClient.Clientset.CoreV1().
+		Pods("velero").
+		List(ctx, metav1.ListOptions{LabelSelector: jobNameLabel})
+
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodePodListFailed, err)
+		return
+	}
+
+	podsByJob := make(map[string][]corev1.Pod)
+	for _, pod := range podList.Items {
+		jobName := pod.Labels[jobNameLabel]
+		podsByJob[jobName] = append(podsByJob[jobName], pod)
+	}
+
+	var jobs []batchv1.Job
+	for _, job := range jobList.Items {
+		if isCronJobRun(&job, cronJobName) {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreationTimestamp.After(jobs[j].CreationTimestamp.Time)
+	})
+
+	runs := make([]CronJobRun, 0, len(jobs))
+	for i := range jobs {
+		runs = append(runs, cronJobRun(&jobs[i], podsByJob[jobs[i].Name]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cronJob": cronJobName,
+		"cluster": extractClusterFromCronJobName(cronJobName),
+		"jobs":    runs,
+		"count":   len(runs),
+	})
+}
+
+// getCronJobRun returns the Job when it exists and belongs to the CronJob
+func (h *VeleroHandler) getCronJobRun(cronJobName, jobName string) (*batchv1.Job, error) {
+	job, err := h.k8sClient.Clientset.BatchV1().
+		Jobs("velero").
+		Get(h.k8sClient.Context, jobName, metav1.GetOptions{})
+
+	if err != nil {
+		return nil, err
+	}
+	if !isCronJobRun(job, cronJobName) {
+		return nil, apierrors.NewNotFound(batchv1.Resource("jobs"), jobName)
+	}
+	return job, nil
+}
+
+// podStarted reports whether any container of the pod has started, i.e. has logs
+func podStarted(pod *corev1.Pod) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Running != nil || status.State.Terminated != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCronJobRunLogs streams the logs of a Job's pods as plain text: ?tail=100
+func (h *VeleroHandler) GetCronJobRunLogs(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	cronJobName := c.Param("name")
+	jobName := c.Param("job")
+
+	var tailLines *int64
+	if tail := c.Query("tail"); tail != "" {
+		lines, err := strconv.ParseInt(tail, 10, 64)
+		if err != nil || lines <= 0 {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidQuery, fmt.Errorf("tail must be a positive integer"))
+			return
+		}
+		tailLines = &lines
+	}
+
+	_, err := h.getCronJobRun(cronJobName, jobName)
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusNotFound, ErrCodeJobNotFound, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeJobGetFailed, err)
+		return
+	}
+
+	podList, err := h.k8sClient.Clientset.CoreV1().
+		Pods("velero").
+		List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", jobNameLabel, jobName),
+		})
+
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodePodListFailed, err)
+		return
+	}
+	if len(podList.Items) == 0 {
+		respondError(c, http.StatusNotFound, ErrCodePodNotFound,
+			fmt.Errorf("job %s has no pods; they may have been garbage-collected", jobName))
+		return
+	}
+
+	// Oldest first so retries read in order
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+	})
+
+	var started []corev1.Pod
+	for _, pod := range pods {
+		if podStarted(&pod) {
+			started = append(started, pod)
+		}
+	}
+	if len(started) == 0 {
+		respondError(c, http.StatusConflict, ErrCodePodNotStarted,
+			fmt.Errorf("pod %s is %s and has not started yet", pods[0].Name, pods[0].Status.Phase))
+		return
+	}
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	for _, pod := range started {
+		if len(started) > 1 {
+			fmt.Fprintf(c.Writer, "==> pod %s (%s) <==\n", pod.Name, pod.Status.Phase)
+		}
+
+		stream, err := h.k8sClient.Clientset.CoreV1().
+			Pods("velero").
+			GetLogs(pod.Name, &corev1.PodLogOptions{TailLines: tailLines}).
+			Stream(c.Request.Context())
+
+		if err != nil {
+			// Headers are already sent, so report the failure inline
+			logRequestError(c, "Failed to stream pod logs", err)
+			fmt.Fprintf(c.Writer, "failed to read logs of pod %s: %v\n", pod.Name, err)
+			continue
+		}
+
+		_, err = io.Copy(c.Writer, stream)
+		stream.Close()
+		if err != nil {
+			logRequestError(c, "Failed to stream pod logs", err)
+			return
+		}
+		c.Writer.Flush()
+	}
+}
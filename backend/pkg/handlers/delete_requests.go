@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Velero marks a DeleteBackupRequest Processed once it has finished, successfully or not
+const deleteRequestProcessed = "Processed"
+
+// DeleteBackupRequestInfo summarizes a Velero DeleteBackupRequest
+type DeleteBackupRequestInfo struct {
+	Name              string      `json:"name"`
+	Backup            string      `json:"backup"`
+	Phase             string      `json:"phase"`
+	InProgress        bool        `json:"inProgress"`
+	Errors            []string    `json:"errors,omitempty"`
+	CreationTimestamp metav1.Time `json:"creationTimestamp"`
+}
+
+func deleteRequestInfo(request unstructured.Unstructured) DeleteBackupRequestInfo {
+	backupName, _, _ := unstructured.NestedString(request.Object, "spec", "backupName")
+	phase, _, _ := unstructured.NestedString(request.Object, "status", "phase")
+	errors, _, _ := unstructured.NestedStringSlice(request.Object, "status", "errors")
+	if phase == "" {
+		phase = "New"
+	}
+
+	return DeleteBackupRequestInfo{
+		Name:              request.GetName(),
+		Backup:            backupName,
+		Phase:             phase,
+		InProgress:        phase != deleteRequestProcessed,
+		Errors:            errors,
+		CreationTimestamp: request.GetCreationTimestamp(),
+	}
+}
+
+// listDeleteRequests returns all DeleteBackupRequests, newest first. available is false
+// when the DeleteBackupRequest CRD isn't installed.
+func (h *VeleroHandler) listDeleteRequests() (requests []DeleteBackupRequestInfo, available bool, err error) {
+	list, err := h.k8sClient.DynamicClient.
+		Resource(k8s.DeleteBackupRequestGVR).
+		Namespace("velero").
+		List(h.k8sClient.Context, metav1.ListOptions{})
+
+	if apierrors.IsNotFound(err) {
+		return []DeleteBackupRequestInfo{}, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	requests = make([]DeleteBackupRequestInfo, 0, len(list.Items))
+	for _, item := range list.Items {
+		requests = append(requests, deleteRequestInfo(item))
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].CreationTimestamp.After(requests[j].CreationTimestamp.Time)
+	})
+
+	return requests, true, nil
+}
+
+// ListDeleteRequests lists pending and processed backup deletions
+func (h *VeleroHandler) ListDeleteRequests(c *gin.Context) {
+	requests, available, err := h.listDeleteRequests()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeDeleteRequestListFailed, err)
+		return
+	}
+
+	inProgress := 0
+	for _, request := range requests {
+		if request.InProgress {
+			inProgress++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deleteRequests": requests,
+		"count":          len(requests),
+		"inProgress":     inProgress,
+		"available":      available,
+	})
+}
+
+// GetBackupDeleteRequest reports whether a deletion of the backup is in progress
+func (h *VeleroHandler) GetBackupDeleteRequest(c *gin.Context) {
+	backupName := c.Param("name")
+
+	requests, available, err := h.listDeleteRequests()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeDeleteRequestListFailed, err)
+		return
+	}
+
+	// Matched on spec.backupName since Velero shortens long names in the label
+	backupRequests := []DeleteBackupRequestInfo{}
+	for _, request := range requests {
+		if request.Backup == backupName {
+			backupRequests = append(backupRequests, request)
+		}
+	}
+
+	response := gin.H{
+		"backup":         backupName,
+		"inProgress":     false,
+		"deleteRequest":  nil,
+		"deleteRequests": backupRequests,
+		"available":      available,
+	}
+	if len(backupRequests) > 0 {
+		response["deleteRequest"] = backupRequests[0]
+		response["inProgress"] = backupRequests[0].InProgress
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondJSONWithETag responds with body as JSON and a weak ETag of etagSource, the
+// part of the body that identifies its content. ETags are opt-in: only requests with an
+// If-None-Match header get one, and they get an empty 304 while it still matches. A
+// polling client can start with any value, such as W/"".
+func respondJSONWithETag(c *gin.Context, body, etagSource interface{}) {
+	ifNoneMatch := c.GetHeader("If-None-Match")
+	if ifNoneMatch == "" {
+		c.JSON(http.StatusOK, body)
+		return
+	}
+
+	payload, err := json.Marshal(etagSource)
+	if err != nil {
+		// Not worth failing the request over; the body may still serialize
+		c.JSON(http.StatusOK, body)
+		return
+	}
+
+	sum := sha256.Sum256(payload)
+	etag := fmt.Sprintf(`W/"%x"`, sum[:16])
+	c.Header("ETag", etag)
+
+	if etagMatches(ifNoneMatch, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.JSON(http.StatusOK, body)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing weakly
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutKey returns a copy of a JSON-style value with key dropped from every object,
+// so fields like updatedAt that change on each request don't change the ETag
+func withoutKey(value interface{}, key string) interface{} {
+	switch v := value.(type) {
+	case gin.H:
+		return withoutKey(map[string]interface{}(v), key)
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			if k != key {
+				copied[k] = withoutKey(item, key)
+			}
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = withoutKey(item, key)
+		}
+		return copied
+	default:
+		return value
+	}
+}
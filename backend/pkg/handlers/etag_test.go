@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRespondJSONWithETag(t *testing.T) {
+	body := gin.H{"items": []string{"nightly-1"}, "updatedAt": "now"}
+	respond := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/backups", nil)
+		if ifNoneMatch != "" {
+			c.Request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		respondJSONWithETag(c, body, withoutKey(body, "updatedAt"))
+		c.Writer.WriteHeaderNow() // gin does this after the handlers when serving
+		return w
+	}
+
+	w := respond("")
+	assertStatus(t, w, http.StatusOK)
+	if etag := w.Header().Get("ETag"); etag != "" {
+		t.Errorf("ETag = %q without If-None-Match, want none", etag)
+	}
+
+	w = respond(`W/""`)
+	assertStatus(t, w, http.StatusOK)
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag for a request with If-None-Match")
+	}
+
+	w = respond(etag)
+	assertStatus(t, w, http.StatusNotModified)
+	if w.Body.Len() != 0 {
+		t.Errorf("304 body = %q, want empty", w.Body.String())
+	}
+}
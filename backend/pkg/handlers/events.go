@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// StatusEvent is a backup/restore change pushed to event stream subscribers
+type StatusEvent struct {
+	Type      string    `json:"type"` // added, updated or deleted
+	Kind      string    `json:"kind"` // backup or restore
+	Name      string    `json:"name"`
+	Cluster   string    `json:"cluster"`
+	Phase     string    `json:"phase"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// eventHub watches Velero backups and restores and fans the changes out to subscribers
+type eventHub struct {
+	k8sClient   *k8s.Client
+	subscribers map[chan StatusEvent]struct{}
+	mutex       sync.RWMutex
+	startOnce   sync.Once
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+const (
+	eventBufferSize = 64
+	eventKeepAlive  = 30 * time.Second
+	// A watch that stayed open this long was healthy, so the next one starts without delay
+	eventWatchHealthyPeriod = time.Minute
+)
+
+// eventWatchBackoff spaces out the List and Watch calls when they fail or a watch
+// closes right away, so a struggling API server isn't hammered
+var eventWatchBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    6,
+	Cap:      30 * time.Second,
+}
+
+func newEventHub(k8sClient *k8s.Client) *eventHub {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &eventHub{
+		k8sClient:   k8sClient,
+		subscribers: make(map[chan StatusEvent]struct{}),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// stop ends the watches
+func (e *eventHub) stop() {
+	e.cancel()
+}
+
+// subscribe registers a new subscriber, starting the watches on first use
+func (e *eventHub) subscribe() chan StatusEvent {
+	e.startOnce.Do(func() {
+		go e.watch(k8s.BackupGVR, "backup")
+		go e.watch(k8s.RestoreGVR, "restore")
+	})
+
+	ch := make(chan StatusEvent, eventBufferSize)
+	e.mutex.Lock()
+	e.subscribers[ch] = struct{}{}
+	e.mutex.Unlock()
+	return ch
+}
+
+func (e *eventHub) unsubscribe(ch chan StatusEvent) {
+	e.mutex.Lock()
+	delete(e.subscribers, ch)
+	e.mutex.Unlock()
+	close(ch)
+}
+
+// publish delivers an event to every subscriber, dropping it for subscribers that can't keep up
+func (e *eventHub) publish(event StatusEvent) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	for ch := range e.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// watch keeps a watch open on the given resource until the hub is stopped
+func (e *eventHub) watch(gvr schema.GroupVersionResource, kind string) {
+	resource := e.k8sClient.DynamicClient.Resource(gvr).Namespace("velero")
+	backoff := eventWatchBackoff
+
+	for {
+		// Start from the current resource version so subscribers only see changes
+		list, err := resource.List(e.ctx, metav1.ListOptions{})
+		if err != nil {
+			log.Printf("Event stream: failed to list %ss: %v", kind, err)
+			if !e.pause(backoff.Step()) {
+				return
+			}
+			continue
+		}
+
+		watcher, err := resource.Watch(e.ctx, metav1.ListOptions{
+			ResourceVersion: list.GetResourceVersion(),
+		})
+		if err != nil {
+			log.Printf("Event stream: failed to watch %ss: %v", kind, err)
+			if !e.pause(backoff.Step()) {
+				return
+			}
+			continue
+		}
+
+		started := time.Now()
+		for event := range watcher.ResultChan() {
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+
+			var eventType string
+			switch event.Type {
+			case watch.Added:
+				eventType = "added"
+			case watch.Modified:
+				eventType = "updated"
+			case watch.Deleted:
+				eventType = "deleted"
+			default:
+				continue
+			}
+
+			e.publish(newStatusEvent(eventType, kind, obj))
+		}
+		watcher.Stop()
+
+		if time.Since(started) >= eventWatchHealthyPeriod {
+			backoff = eventWatchBackoff
+		}
+		if !e.pause(backoff.Step()) {
+			return
+		}
+	}
+}
+
+// pause waits for the given duration and reports whether the hub is still running
+func (e *eventHub) pause(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-e.ctx.Done():
+		return false
+	}
+}
+
+func newStatusEvent(eventType, kind string, obj *unstructured.Unstructured) StatusEvent {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+
+	var cluster string
+	if kind == "restore" {
+		cluster = k8s.RestoreCluster(obj)
+	} else {
+		cluster = k8s.BackupCluster(obj)
+	}
+
+	return StatusEvent{
+		Type:      eventType,
+		Kind:      kind,
+		Name:      obj.GetName(),
+		Cluster:   cluster,
+		Phase:     phase,
+		Timestamp: time.Now(),
+	}
+}
+
+// StreamEvents pushes live backup/restore status changes to the client using Server-Sent Events
+func (h *VeleroHandler) StreamEvents(c *gin.Context) {
+	// Optional kind filter: ?kind=backup or ?kind=restore
+	kindFilter := strings.ToLower(c.Query("kind"))
+
+	events := h.events.subscribe()
+	defer h.events.unsubscribe(events)
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event := <-events:
+			if kindFilter == "" || kindFilter == event.Kind {
+				c.SSEvent(event.Kind, event)
+			}
+			return true
+		case <-keepAlive.C:
+			c.SSEvent("ping", gin.H{"timestamp": time.Now()})
+			return true
+		}
+	})
+}
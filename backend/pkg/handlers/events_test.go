@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+	"velero-manager/pkg/k8s"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestEventHubBacksOffAndStops(t *testing.T) {
+	defer func(backoff wait.Backoff) { eventWatchBackoff = backoff }(eventWatchBackoff)
+	eventWatchBackoff = wait.Backoff{Duration: 20 * time.Millisecond, Factor: 1, Steps: 1}
+
+	// Every watch closes right away, as it would against a flapping API server
+	client := newTestClient()
+	var watches atomic.Int32
+	fakeDynamic(client).PrependWatchReactor("backups", func(k8stesting.Action) (bool, watch.Interface, error) {
+		watches.Add(1)
+		watcher := watch.NewFake()
+		watcher.Stop()
+		return true, watcher, nil
+	})
+
+	hub := newEventHub(client)
+	done := make(chan struct{})
+	go func() {
+		hub.watch(k8s.BackupGVR, "backup")
+		close(done)
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	if got := watches.Load(); got < 2 || got > 15 {
+		t.Errorf("opened %d watches in 200ms with a 20ms backoff", got)
+	}
+
+	hub.stop()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch kept running after the hub was stopped")
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/retry"
+)
+
+// ExpiringBackup is a backup Velero will garbage-collect soon
+type ExpiringBackup struct {
+	Name       string      `json:"name"`
+	Cluster    string      `json:"cluster"`
+	Phase      string      `json:"phase"`
+	Created    metav1.Time `json:"created"`
+	Expiration metav1.Time `json:"expiration"`
+	// Seconds until expiration, for sorting and countdowns on the client
+	RemainingSeconds int64  `json:"remainingSeconds"`
+	Remaining        string `json:"remaining"`
+}
+
+// ListExpiringBackups lists the backups whose status.expiration falls within ?within=
+// (default 48h), soonest first, so important ones can be extended before they're gone
+func (h *VeleroHandler) ListExpiringBackups(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	within := 48 * time.Hour
+	if w := c.Query("within"); w != "" {
+		parsed, err := time.ParseDuration(w)
+		if err != nil || parsed <= 0 {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidQuery,
+				fmt.Errorf("within must be a positive duration such as 48h"))
+			return
+		}
+		within = parsed
+	}
+
+	backupList, err := h.k8sClient.ListCache.List(ctx, k8s.BackupGVR, "velero")
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeBackupListFailed, err)
+		return
+	}
+
+	now := time.Now()
+	deadline := now.Add(within)
+	backups := []ExpiringBackup{}
+	for i := range backupList.Items {
+		backup := &backupList.Items[i]
+
+		// Backups being deleted are already on their way out
+		if backup.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		expiration := statusTimestamp(backup, "expiration")
+		if expiration == nil || expiration.Time.Before(now) || expiration.Time.After(deadline) {
+			continue
+		}
+
+		remaining := expiration.Time.Sub(now)
+		phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase")
+		backups = append(backups, ExpiringBackup{
+			Name:             backup.GetName(),
+			Cluster:          k8s.BackupCluster(backup),
+			Phase:            phase,
+			Created:          backup.GetCreationTimestamp(),
+			Expiration:       *expiration,
+			RemainingSeconds: int64(remaining.Seconds()),
+			Remaining:        remaining.Round(time.Minute).String(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Expiration.Before(&backups[j].Expiration)
+	})
+
+	c.JSON(http.StatusOK, ExpiringBackupList{Backups: backups, Count: len(backups), Within: within.String()})
+}
+
+// UpdateBackupTTL sets a backup's spec.ttl, to keep a backup that is about to expire.
+// Velero works out status.expiration when the backup is created, so the new TTL only
+// takes effect once Velero recalculates it, e.g. when the backup is synced again.
+func (h *VeleroHandler) UpdateBackupTTL(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	backupName := c.Param("name")
+
+	var request struct {
+		TTL string `json:"ttl" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, err)
+		return
+	}
+
+	ttl, err := time.ParseDuration(request.TTL)
+	if err != nil || ttl <= 0 {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidTTL,
+			fmt.Errorf("ttl must be a positive duration such as 720h"))
+		return
+	}
+
+	var updated *unstructured.Unstructured
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		backup, err := h.k8sClient.DynamicClient.
+			Resource(k8s.BackupGVR).
+			Namespace("velero").
+			Get(ctx, backupName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if err := unstructured.SetNestedField(backup.Object, ttl.String(), "spec", "ttl"); err != nil {
+			return err
+		}
+
+		updated, err = h.k8sClient.DynamicClient.
+			Resource(k8s.BackupGVR).
+			Namespace("velero").
+			Update(ctx, backup, metav1.UpdateOptions{})
+		return err
+	})
+	h.k8sClient.ListCache.Invalidate(k8s.BackupGVR)
+
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusNotFound, ErrCodeBackupNotFound, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeBackupUpdateFailed, err)
+		return
+	}
+
+	created := updated.GetCreationTimestamp()
+	response := gin.H{
+		"message":             "Backup TTL updated",
+		"backup":              backupName,
+		"ttl":                 ttl.String(),
+		"requestedExpiration": metav1.NewTime(created.Add(ttl)),
+		"note":                "Velero sets status.expiration when a backup is created; the new TTL applies once Velero recalculates it",
+	}
+	if expiration := statusTimestamp(updated, "expiration"); expiration != nil {
+		response["expiration"] = expiration
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessCheckTimeout bounds how long readiness waits on the API server
+const readinessCheckTimeout = 5 * time.Second
+
+// HealthHandler serves the Kubernetes liveness and readiness probes
+type HealthHandler struct {
+	k8sClient *k8s.Client
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(k8sClient *k8s.Client) *HealthHandler {
+	return &HealthHandler{
+		k8sClient: k8sClient,
+	}
+}
+
+// Liveness reports that the process is up
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "alive"})
+}
+
+// Readiness reports whether the Kubernetes API server is reachable
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	type result struct {
+		version string
+		err     error
+	}
+
+	// ServerVersion has no context, so bound it ourselves
+	done := make(chan result, 1)
+	go func() {
+		info, err := h.k8sClient.Clientset.Discovery().ServerVersion()
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		done <- result{version: info.GitVersion}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status":  "not ready",
+				"error":   "Kubernetes API server unreachable",
+				"details": r.err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"status":            "ready",
+			"kubernetesVersion": r.version,
+		})
+	case <-time.After(readinessCheckTimeout):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "not ready",
+			"error":   "Kubernetes API server unreachable",
+			"details": "timed out waiting for server version",
+		})
+	}
+}
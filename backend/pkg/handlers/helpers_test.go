@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// testListKinds registers every resource the handlers read through the dynamic client
+var testListKinds = map[schema.GroupVersionResource]string{
+	k8s.BackupGVR:                "BackupList",
+	k8s.ScheduleGVR:              "ScheduleList",
+	k8s.RestoreGVR:               "RestoreList",
+	k8s.BackupStorageLocationGVR: "BackupStorageLocationList",
+	k8s.CronJobGVR:               "CronJobList",
+	k8s.JobGVR:                   "JobList",
+	k8s.SecretGVR:                "SecretList",
+	k8s.DownloadRequestGVR:       "DownloadRequestList",
+	k8s.DeleteBackupRequestGVR:   "DeleteBackupRequestList",
+}
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestClient returns a client backed by fake API servers. Unstructured objects seed the
+// dynamic client and typed objects the clientset; the two do not share storage.
+func newTestClient(objects ...runtime.Object) *k8s.Client {
+	var typed, untyped []runtime.Object
+	for _, obj := range objects {
+		if _, ok := obj.(*unstructured.Unstructured); ok {
+			untyped = append(untyped, obj)
+		} else {
+			typed = append(typed, obj)
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(typed...)
+	dynamicClient := jsonDynamicClient{dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), testListKinds, untyped...)}
+
+	return &k8s.Client{
+		Clientset:     clientset,
+		DynamicClient: dynamicClient,
+		Context:       context.Background(),
+		ListCache:     k8s.NewListCache(dynamicClient, 0),
+		VeleroVersion: k8s.NewVersionCache(clientset),
+	}
+}
+
+// fakeDynamic returns the fake behind a test client, for adding reactors
+func fakeDynamic(client *k8s.Client) *dynamicfake.FakeDynamicClient {
+	return client.DynamicClient.(jsonDynamicClient).Interface.(*dynamicfake.FakeDynamicClient)
+}
+
+// jsonDynamicClient sends created and updated objects through JSON like the real dynamic
+// client does. Handlers build objects with typed slices such as []string, which the fake
+// client can't deep-copy.
+type jsonDynamicClient struct {
+	dynamic.Interface
+}
+
+func (c jsonDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return jsonResource{c.Interface.Resource(gvr)}
+}
+
+type jsonResource struct {
+	dynamic.NamespaceableResourceInterface
+}
+
+func (r jsonResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return jsonNamespacedResource{r.NamespaceableResourceInterface.Namespace(namespace)}
+}
+
+type jsonNamespacedResource struct {
+	dynamic.ResourceInterface
+}
+
+func (r jsonNamespacedResource) Create(ctx context.Context, obj *unstructured.Unstructured, opts metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	normalized, err := jsonRoundTrip(obj)
+	if err != nil {
+		return nil, err
+	}
+	return r.ResourceInterface.Create(ctx, normalized, opts, subresources...)
+}
+
+func (r jsonNamespacedResource) Update(ctx context.Context, obj *unstructured.Unstructured, opts metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	normalized, err := jsonRoundTrip(obj)
+	if err != nil {
+		return nil, err
+	}
+	return r.ResourceInterface.Update(ctx, normalized, opts, subresources...)
+}
+
+func jsonRoundTrip(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	content := map[string]interface{}{}
+	if err := utiljson.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// newUnstructured builds a namespaced object for seeding the fake dynamic client
+func newUnstructured(apiVersion, kind, namespace, name string, content map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: content}
+	if obj.Object == nil {
+		obj.Object = map[string]interface{}{}
+	}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+// serve runs a single request against handler, with the given path parameters and
+// caller set the way the router and auth middleware would
+func serve(handler gin.HandlerFunc, method, target string, body interface{}, params gin.Params, role string) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, target, reader)
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = params
+	c.Set("username", "tester")
+	c.Set("role", role)
+	handler(c)
+	return w
+}
+
+// decodeBody unmarshals a JSON response body
+func decodeBody(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON: %v\n%s", err, w.Body.String())
+	}
+	return body
+}
+
+// assertStatus fails the test when a response has an unexpected status
+func assertStatus(t *testing.T, w *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if w.Code != want {
+		t.Fatalf("status = %d, want %d\n%s", w.Code, want, w.Body.String())
+	}
+}
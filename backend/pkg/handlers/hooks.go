@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+)
+
+// HookExec runs a command in a container of the selected pods
+type HookExec struct {
+	Container string   `json:"container,omitempty"`
+	Command   []string `json:"command"`
+	OnError   string   `json:"onError,omitempty"` // Continue or Fail
+	Timeout   string   `json:"timeout,omitempty"`
+}
+
+func (e *HookExec) validate() error {
+	if e == nil {
+		return fmt.Errorf("exec is required")
+	}
+	if len(e.Command) == 0 {
+		return fmt.Errorf("at least one command is required")
+	}
+	if e.OnError != "" && e.OnError != "Continue" && e.OnError != "Fail" {
+		return fmt.Errorf("onError must be Continue or Fail")
+	}
+	if e.Timeout != "" {
+		if _, err := time.ParseDuration(e.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout %q", e.Timeout)
+		}
+	}
+	return nil
+}
+
+// toSpec serializes the exec hook, using timeoutField for the timeout key since backup
+// hooks call it "timeout" and restore hooks "execTimeout"
+func (e *HookExec) toSpec(timeoutField string) map[string]interface{} {
+	exec := map[string]interface{}{
+		"command": e.Command,
+	}
+	if e.Container != "" {
+		exec["container"] = e.Container
+	}
+	if e.OnError != "" {
+		exec["onError"] = e.OnError
+	}
+	if e.Timeout != "" {
+		exec[timeoutField] = e.Timeout
+	}
+	return exec
+}
+
+// RestorePostHook is a hook run in restored pods
+type RestorePostHook struct {
+	Exec *HookExec `json:"exec"`
+}
+
+// RestoreHookResource selects restored pods and the hooks to run in them
+type RestoreHookResource struct {
+	Name               string            `json:"name"`
+	IncludedNamespaces []string          `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string          `json:"excludedNamespaces,omitempty"`
+	IncludedResources  []string          `json:"includedResources,omitempty"`
+	LabelSelector      map[string]string `json:"labelSelector,omitempty"`
+	PostHooks          []RestorePostHook `json:"postHooks"`
+}
+
+// RestoreHooks mirrors spec.hooks of a Velero Restore
+type RestoreHooks struct {
+	Resources []RestoreHookResource `json:"resources"`
+}
+
+func (h *RestoreHooks) validate() error {
+	for i, resource := range h.Resources {
+		if resource.Name == "" {
+			return fmt.Errorf("hooks.resources[%d]: name is required", i)
+		}
+		if len(resource.PostHooks) == 0 {
+			return fmt.Errorf("hooks.resources[%d] (%s): at least one post hook is required", i, resource.Name)
+		}
+		for j, hook := range resource.PostHooks {
+			if err := hook.Exec.validate(); err != nil {
+				return fmt.Errorf("hooks.resources[%d] (%s).postHooks[%d]: %v", i, resource.Name, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+// toSpec serializes the hooks into the shape Velero expects under spec.hooks
+func (h *RestoreHooks) toSpec() map[string]interface{} {
+	resources := make([]interface{}, 0, len(h.Resources))
+	for _, resource := range h.Resources {
+		postHooks := make([]interface{}, 0, len(resource.PostHooks))
+		for _, hook := range resource.PostHooks {
+			postHooks = append(postHooks, map[string]interface{}{
+				"exec": hook.Exec.toSpec("execTimeout"),
+			})
+		}
+
+		entry := map[string]interface{}{
+			"name":      resource.Name,
+			"postHooks": postHooks,
+		}
+		addHookSelectors(entry, resource.IncludedNamespaces, resource.ExcludedNamespaces, resource.IncludedResources, resource.LabelSelector)
+		resources = append(resources, entry)
+	}
+
+	return map[string]interface{}{
+		"resources": resources,
+	}
+}
+
+// addHookSelectors adds the optional namespace/resource/label filters to a hook resource entry
+func addHookSelectors(entry map[string]interface{}, includedNamespaces, excludedNamespaces, includedResources []string, labelSelector map[string]string) {
+	if len(includedNamespaces) > 0 {
+		entry["includedNamespaces"] = includedNamespaces
+	}
+	if len(excludedNamespaces) > 0 {
+		entry["excludedNamespaces"] = excludedNamespaces
+	}
+	if len(includedResources) > 0 {
+		entry["includedResources"] = includedResources
+	}
+	if len(labelSelector) > 0 {
+		matchLabels := make(map[string]interface{}, len(labelSelector))
+		for key, value := range labelSelector {
+			matchLabels[key] = value
+		}
+		entry["labelSelector"] = map[string]interface{}{
+			"matchLabels": matchLabels,
+		}
+	}
+}
+
+// BackupHook is a hook run in pods before or after they are backed up
+type BackupHook struct {
+	Exec *HookExec `json:"exec"`
+}
+
+// BackupHookResource selects pods and the hooks to run around their backup
+type BackupHookResource struct {
+	Name               string            `json:"name"`
+	IncludedNamespaces []string          `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string          `json:"excludedNamespaces,omitempty"`
+	IncludedResources  []string          `json:"includedResources,omitempty"`
+	LabelSelector      map[string]string `json:"labelSelector,omitempty"`
+	Pre                []BackupHook      `json:"pre,omitempty"`
+	Post               []BackupHook      `json:"post,omitempty"`
+}
+
+// BackupHooks mirrors spec.hooks of a Velero Backup (or a Schedule template)
+type BackupHooks struct {
+	Resources []BackupHookResource `json:"resources"`
+}
+
+func (h *BackupHooks) validate() error {
+	for i, resource := range h.Resources {
+		if resource.Name == "" {
+			return fmt.Errorf("hooks.resources[%d]: name is required", i)
+		}
+		if len(resource.Pre) == 0 && len(resource.Post) == 0 {
+			return fmt.Errorf("hooks.resources[%d] (%s): at least one pre or post hook is required", i, resource.Name)
+		}
+		for j, hook := range resource.Pre {
+			if err := hook.Exec.validate(); err != nil {
+				return fmt.Errorf("hooks.resources[%d] (%s).pre[%d]: %v", i, resource.Name, j, err)
+			}
+		}
+		for j, hook := range resource.Post {
+			if err := hook.Exec.validate(); err != nil {
+				return fmt.Errorf("hooks.resources[%d] (%s).post[%d]: %v", i, resource.Name, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+// toSpec serializes the hooks into the shape Velero expects under spec.hooks
+func (h *BackupHooks) toSpec() map[string]interface{} {
+	toHookList := func(hooks []BackupHook) []interface{} {
+		list := make([]interface{}, 0, len(hooks))
+		for _, hook := range hooks {
+			list = append(list, map[string]interface{}{
+				"exec": hook.Exec.toSpec("timeout"),
+			})
+		}
+		return list
+	}
+
+	resources := make([]interface{}, 0, len(h.Resources))
+	for _, resource := range h.Resources {
+		entry := map[string]interface{}{
+			"name": resource.Name,
+		}
+		if len(resource.Pre) > 0 {
+			entry["pre"] = toHookList(resource.Pre)
+		}
+		if len(resource.Post) > 0 {
+			entry["post"] = toHookList(resource.Post)
+		}
+		addHookSelectors(entry, resource.IncludedNamespaces, resource.ExcludedNamespaces, resource.IncludedResources, resource.LabelSelector)
+		resources = append(resources, entry)
+	}
+
+	return map[string]interface{}{
+		"resources": resources,
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// listQuery holds the sort and filter query parameters shared by the list endpoints:
+// ?sort=creationTimestamp|name&order=asc|desc&phase=<phase>
+type listQuery struct {
+	sortBy string
+	desc   bool
+	phase  string
+}
+
+// parseListQuery reads the list query parameters, defaulting to newest first
+func parseListQuery(c *gin.Context) (listQuery, error) {
+	query := listQuery{
+		sortBy: c.DefaultQuery("sort", "creationTimestamp"),
+		phase:  c.Query("phase"),
+	}
+
+	if query.sortBy != "creationTimestamp" && query.sortBy != "name" {
+		return query, fmt.Errorf("sort must be one of creationTimestamp, name")
+	}
+
+	switch c.Query("order") {
+	case "":
+		// Newest first for timestamps, alphabetical for names
+		query.desc = query.sortBy == "creationTimestamp"
+	case "asc":
+		query.desc = false
+	case "desc":
+		query.desc = true
+	default:
+		return query, fmt.Errorf("order must be one of asc, desc")
+	}
+
+	return query, nil
+}
+
+// listKey is what a listQuery filters and sorts a list item by
+type listKey struct {
+	name    string
+	created metav1.Time
+	phase   string
+}
+
+// apply filters items by phase and sorts them in place
+func (q listQuery) apply(items []map[string]interface{}) []map[string]interface{} {
+	return applyListQuery(q, items, func(item map[string]interface{}) listKey {
+		name, _ := item["name"].(string)
+		created, _ := item["creationTimestamp"].(metav1.Time)
+		return listKey{name: name, created: created, phase: itemPhase(item)}
+	})
+}
+
+// applyResources filters resources by phase and sorts them in place
+func (q listQuery) applyResources(items []VeleroResource) []VeleroResource {
+	return applyListQuery(q, items, func(item VeleroResource) listKey {
+		phase, _ := item.Status["phase"].(string)
+		return listKey{name: item.Name, created: item.CreationTimestamp, phase: phase}
+	})
+}
+
+func applyListQuery[T any](q listQuery, items []T, keyOf func(T) listKey) []T {
+	if q.phase != "" {
+		filtered := make([]T, 0, len(items))
+		for _, item := range items {
+			if strings.EqualFold(keyOf(item).phase, q.phase) {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := keyOf(items[i]), keyOf(items[j])
+		if q.desc {
+			a, b = b, a
+		}
+
+		if q.sortBy == "creationTimestamp" && !a.created.Equal(&b.created) {
+			return a.created.Before(&b.created)
+		}
+		return a.name < b.name
+	})
+
+	return items
+}
+
+// itemPhase returns the phase of a list item, preferring an explicit "phase" field over status.phase
+func itemPhase(item map[string]interface{}) string {
+	if phase, ok := item["phase"].(string); ok {
+		return phase
+	}
+	if status, ok := item["status"].(map[string]interface{}); ok {
+		if phase, ok := status["phase"].(string); ok {
+			return phase
+		}
+	}
+	return ""
+}
+
+// parseLabelSelector reads the optional ?labelSelector= query parameter, e.g.
+// tier=critical,app!=legacy; unset matches everything
+func parseLabelSelector(c *gin.Context) (labels.Selector, error) {
+	selector, err := labels.Parse(c.Query("labelSelector"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid labelSelector: %v", err)
+	}
+	return selector, nil
+}
+
+// pageQuery holds the optional ?limit=&offset= pagination parameters; a zero limit
+// returns everything from offset on
+type pageQuery struct {
+	limit  int
+	offset int
+}
+
+// parsePageQuery reads the pagination query parameters
+func parsePageQuery(c *gin.Context) (pageQuery, error) {
+	var query pageQuery
+	var err error
+
+	if value := c.Query("limit"); value != "" {
+		if query.limit, err = strconv.Atoi(value); err != nil || query.limit < 0 {
+			return query, fmt.Errorf("limit must be a non-negative integer")
+		}
+	}
+	if value := c.Query("offset"); value != "" {
+		if query.offset, err = strconv.Atoi(value); err != nil || query.offset < 0 {
+			return query, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+
+	return query, nil
+}
+
+// bounds returns the start and end indexes of the page within total items
+func (q pageQuery) bounds(total int) (int, int) {
+	start := min(q.offset, total)
+	if q.limit == 0 {
+		return start, total
+	}
+	return start, min(start+q.limit, total)
+}
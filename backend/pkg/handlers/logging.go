@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"log/slog"
+	"velero-manager/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// logRequestError writes a structured error log entry tied to the current request
+func logRequestError(c *gin.Context, msg string, err error) {
+	slog.Error(msg,
+		"request_id", c.GetString(middleware.RequestIDKey),
+		"username", c.GetString("username"),
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+		"error", err,
+	)
+}
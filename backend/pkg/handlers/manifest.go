@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// maxManifestSize bounds the body of the apply endpoints
+const maxManifestSize = 1 << 20
+
+// manifestKind describes a Velero kind that can be created from a raw manifest
+type manifestKind struct {
+	kind          string
+	gvr           schema.GroupVersionResource
+	existsCode    string
+	createFailed  string
+	validateSpec  func(spec map[string]interface{}) (string, error)
+	responseField string
+}
+
+var (
+	backupManifestKind = manifestKind{
+		kind:         "Backup",
+		gvr:          k8s.BackupGVR,
+		existsCode:   ErrCodeBackupExists,
+		createFailed: ErrCodeBackupCreateFailed,
+		validateSpec: func(spec map[string]interface{}) (string, error) {
+			if err := validateResourceFilters(spec); err != nil {
+				return ErrCodeInvalidResourceFilters, err
+			}
+			return "", nil
+		},
+		responseField: "backup",
+	}
+
+	restoreManifestKind = manifestKind{
+		kind:         "Restore",
+		gvr:          k8s.RestoreGVR,
+		existsCode:   ErrCodeRestoreExists,
+		createFailed: ErrCodeRestoreCreateFailed,
+		validateSpec: func(spec map[string]interface{}) (string, error) {
+			backupName, _, _ := unstructured.NestedString(spec, "backupName")
+			scheduleName, _, _ := unstructured.NestedString(spec, "scheduleName")
+			if backupName == "" && scheduleName == "" {
+				return ErrCodeInvalidManifest, fmt.Errorf("spec.backupName or spec.scheduleName is required")
+			}
+			mapping, _, err := unstructured.NestedStringMap(spec, "namespaceMapping")
+			if err != nil {
+				return ErrCodeInvalidManifest, fmt.Errorf("spec.namespaceMapping: %v", err)
+			}
+			if err := validateNamespaceMapping(mapping); err != nil {
+				return ErrCodeInvalidNamespaceMapping, err
+			}
+			return "", nil
+		},
+		responseField: "restore",
+	}
+)
+
+// ApplyBackupManifest creates a Backup from a full Velero manifest in YAML or JSON, for
+// spec fields CreateBackup doesn't model yet
+func (h *VeleroHandler) ApplyBackupManifest(c *gin.Context) {
+	h.applyManifest(c, backupManifestKind)
+}
+
+// ApplyRestoreManifest creates a Restore from a full Velero manifest in YAML or JSON, for
+// spec fields CreateRestore doesn't model yet
+func (h *VeleroHandler) ApplyRestoreManifest(c *gin.Context) {
+	h.applyManifest(c, restoreManifestKind)
+}
+
+func (h *VeleroHandler) applyManifest(c *gin.Context, kind manifestKind) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	object, err := decodeManifest(c.Request, kind.kind)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidManifest, err)
+		return
+	}
+
+	spec, _, err := unstructured.NestedMap(object.Object, "spec")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidManifest, fmt.Errorf("spec: %v", err))
+		return
+	}
+	if code, err := kind.validateSpec(spec); err != nil {
+		respondError(c, http.StatusBadRequest, code, err)
+		return
+	}
+
+	createOptions, dryRun := createOptionsFor(c)
+	result, err := k8s.RetryCreate(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.
+			Resource(kind.gvr).
+			Namespace("velero").
+			Create(ctx, object, createOptions)
+	})
+	if !dryRun {
+		h.k8sClient.ListCache.Invalidate(kind.gvr)
+	}
+
+	if apierrors.IsAlreadyExists(err) {
+		respondError(c, http.StatusConflict, kind.existsCode, err)
+		return
+	}
+	if apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidManifest, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, kind.createFailed, err)
+		return
+	}
+
+	if dryRun {
+		c.JSON(http.StatusOK, gin.H{
+			"message":  fmt.Sprintf("Dry run: the %s was validated but not created", strings.ToLower(kind.kind)),
+			"dryRun":   true,
+			"manifest": result.Object,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":          fmt.Sprintf("%s created successfully", kind.kind),
+		kind.responseField: result.GetName(),
+		"status":           "created",
+	})
+}
+
+// decodeManifest reads a YAML or JSON Velero object of the given kind from the request
+// body. The namespace must be velero or unset and is forced to velero; fields the API
+// server owns, such as status, are dropped
+func decodeManifest(req *http.Request, kind string) (*unstructured.Unstructured, error) {
+	body := http.MaxBytesReader(nil, req.Body, maxManifestSize)
+
+	object := &unstructured.Unstructured{}
+	if err := yaml.NewYAMLOrJSONDecoder(body, 4096).Decode(&object.Object); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %v", err)
+	}
+	if object.Object == nil {
+		return nil, fmt.Errorf("manifest is empty")
+	}
+
+	if apiVersion := object.GetAPIVersion(); apiVersion != "velero.io/v1" {
+		return nil, fmt.Errorf("apiVersion must be velero.io/v1, got %q", apiVersion)
+	}
+	if object.GetKind() != kind {
+		return nil, fmt.Errorf("kind must be %s, got %q", kind, object.GetKind())
+	}
+	if namespace := object.GetNamespace(); namespace != "" && namespace != "velero" {
+		return nil, fmt.Errorf("namespace must be velero, got %q", namespace)
+	}
+	object.SetNamespace("velero")
+
+	switch {
+	case object.GetName() != "":
+		if errs := validation.IsDNS1123Subdomain(object.GetName()); len(errs) > 0 {
+			return nil, fmt.Errorf("metadata.name: %s", strings.Join(errs, "; "))
+		}
+	case object.GetGenerateName() == "":
+		return nil, fmt.Errorf("metadata.name or metadata.generateName is required")
+	}
+
+	delete(object.Object, "status")
+	object.SetResourceVersion("")
+	object.SetUID("")
+	object.SetManagedFields(nil)
+	unstructured.RemoveNestedField(object.Object, "metadata", "creationTimestamp")
+
+	return object, nil
+}
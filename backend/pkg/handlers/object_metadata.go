@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// veleroKeyDomain is the label and annotation prefix Velero reserves for its own bookkeeping
+const veleroKeyDomain = "velero.io/"
+
+// ObjectMetadata are user labels and annotations set on created backups and schedules,
+// e.g. for cost centers or retention tiers
+type ObjectMetadata struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// validate checks keys and values against Kubernetes' rules and rejects velero.io/ keys
+func (m *ObjectMetadata) validate() error {
+	errs := metav1validation.ValidateLabels(m.Labels, field.NewPath("labels"))
+	errs = append(errs, apivalidation.ValidateAnnotations(m.Annotations, field.NewPath("annotations"))...)
+
+	for key := range m.Labels {
+		if strings.HasPrefix(key, veleroKeyDomain) {
+			errs = append(errs, field.Forbidden(field.NewPath("labels").Key(key), fmt.Sprintf("the %s prefix is reserved for Velero", veleroKeyDomain)))
+		}
+	}
+	for key := range m.Annotations {
+		if strings.HasPrefix(key, veleroKeyDomain) {
+			errs = append(errs, field.Forbidden(field.NewPath("annotations").Key(key), fmt.Sprintf("the %s prefix is reserved for Velero", veleroKeyDomain)))
+		}
+	}
+
+	return errs.ToAggregate()
+}
+
+// applyTo merges the labels and annotations into an object's metadata
+func (m *ObjectMetadata) applyTo(metadata map[string]interface{}) {
+	mergeStringMap(metadata, "labels", m.Labels)
+	mergeStringMap(metadata, "annotations", m.Annotations)
+}
+
+// applyToTemplate merges the labels into a schedule template's metadata, which Velero
+// copies onto every backup the schedule creates. The template has no annotations, so
+// those are only set on the schedule itself by applyTo.
+func (m *ObjectMetadata) applyToTemplate(template map[string]interface{}) {
+	if len(m.Labels) == 0 {
+		return
+	}
+	metadata, ok := template["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		template["metadata"] = metadata
+	}
+	mergeStringMap(metadata, "labels", m.Labels)
+}
+
+func mergeStringMap(metadata map[string]interface{}, key string, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	merged, ok := metadata[key].(map[string]interface{})
+	if !ok {
+		merged = map[string]interface{}{}
+		metadata[key] = merged
+	}
+	for k, v := range values {
+		merged[k] = v
+	}
+}
@@ -2,16 +2,20 @@ package handlers
 
 import (
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strings"
+	"time"
 	"velero-manager/pkg/config"
 	"velero-manager/pkg/k8s"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,17 +25,27 @@ const (
 	oidcConfigMapName = "velero-manager-oidc-config"
 	oidcSecretName    = "velero-manager-oidc-secret"
 	namespace         = "velero-manager"
+
+	// How long a reload may spend fetching the new provider's signing keys
+	oidcReloadTimeout = 30 * time.Second
 )
 
+// OIDCReloader applies an updated OIDC configuration to the running auth handler
+type OIDCReloader interface {
+	ReloadOIDCProvider(ctx context.Context, oidcConfig *config.OIDCConfig) error
+}
+
 // OIDCConfigHandler handles OIDC configuration management
 type OIDCConfigHandler struct {
 	k8sClient *k8s.Client
+	reloader  OIDCReloader
 }
 
 // NewOIDCConfigHandler creates a new OIDC configuration handler
-func NewOIDCConfigHandler(k8sClient *k8s.Client) *OIDCConfigHandler {
+func NewOIDCConfigHandler(k8sClient *k8s.Client, reloader OIDCReloader) *OIDCConfigHandler {
 	return &OIDCConfigHandler{
 		k8sClient: k8sClient,
+		reloader:  reloader,
 	}
 }
 
@@ -49,7 +63,15 @@ type OIDCConfigRequest struct {
 	GroupsClaim   string   `json:"groupsClaim"`
 	AdminRoles    []string `json:"adminRoles"`
 	AdminGroups   []string `json:"adminGroups"`
+	UserRoles     []string `json:"userRoles"`
+	ViewerRoles   []string `json:"viewerRoles"`
+	ViewerGroups  []string `json:"viewerGroups"`
 	DefaultRole   string   `json:"defaultRole"`
+
+	// TLS options for providers behind a private CA
+	CAFile             string `json:"caFile"`
+	CAData             string `json:"caData"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
 }
 
 // GetOIDCConfig retrieves the current OIDC configuration
@@ -71,6 +93,9 @@ func (h *OIDCConfigHandler) GetOIDCConfig(c *gin.Context) {
 				DefaultRole:   "user",
 				AdminRoles:    []string{},
 				AdminGroups:   []string{},
+				UserRoles:     config.DefaultUserRoles,
+				ViewerRoles:   config.DefaultViewerRoles,
+				ViewerGroups:  []string{},
 			})
 			return
 		}
@@ -86,7 +111,7 @@ func (h *OIDCConfigHandler) GetOIDCConfig(c *gin.Context) {
 	}
 
 	// Parse configuration
-	config := OIDCConfigRequest{
+	oidcConfig := OIDCConfigRequest{
 		Enabled:       configMap.Data["enabled"] == "true",
 		IssuerURL:     configMap.Data["issuerURL"],
 		ClientID:      configMap.Data["clientID"],
@@ -97,28 +122,35 @@ func (h *OIDCConfigHandler) GetOIDCConfig(c *gin.Context) {
 		RolesClaim:    configMap.Data["rolesClaim"],
 		GroupsClaim:   configMap.Data["groupsClaim"],
 		DefaultRole:   configMap.Data["defaultRole"],
+
+		CAFile:             configMap.Data["caFile"],
+		CAData:             configMap.Data["caData"],
+		InsecureSkipVerify: configMap.Data["insecureSkipVerify"] == "true",
 	}
 
 	// Parse JSON arrays
 	if adminRolesStr := configMap.Data["adminRoles"]; adminRolesStr != "" {
-		if err := json.Unmarshal([]byte(adminRolesStr), &config.AdminRoles); err != nil {
+		if err := json.Unmarshal([]byte(adminRolesStr), &oidcConfig.AdminRoles); err != nil {
 			log.Printf("Failed to parse adminRoles: %v, using defaults", err)
-			config.AdminRoles = []string{"velero-admin", "admin"}
+			oidcConfig.AdminRoles = []string{"velero-admin", "admin"}
 		}
 	}
 	if adminGroupsStr := configMap.Data["adminGroups"]; adminGroupsStr != "" {
-		if err := json.Unmarshal([]byte(adminGroupsStr), &config.AdminGroups); err != nil {
+		if err := json.Unmarshal([]byte(adminGroupsStr), &oidcConfig.AdminGroups); err != nil {
 			log.Printf("Failed to parse adminGroups: %v, using defaults", err)
-			config.AdminGroups = []string{"velero-administrators", "administrators"}
+			oidcConfig.AdminGroups = []string{"velero-administrators", "administrators"}
 		}
 	}
+	oidcConfig.UserRoles = parseRoleList(configMap.Data, "userRoles", config.DefaultUserRoles)
+	oidcConfig.ViewerRoles = parseRoleList(configMap.Data, "viewerRoles", config.DefaultViewerRoles)
+	oidcConfig.ViewerGroups = parseRoleList(configMap.Data, "viewerGroups", []string{})
 
 	// Get client secret from Secret
 	if secret != nil && secret.Data != nil {
-		config.ClientSecret = string(secret.Data["clientSecret"])
+		oidcConfig.ClientSecret = string(secret.Data["clientSecret"])
 	}
 
-	c.JSON(http.StatusOK, config)
+	c.JSON(http.StatusOK, oidcConfig)
 }
 
 // UpdateOIDCConfig updates the OIDC configuration
@@ -131,9 +163,18 @@ func (h *OIDCConfigHandler) UpdateOIDCConfig(c *gin.Context) {
 
 	ctx := context.Background()
 
+	// Catch an unreadable CA bundle now rather than at the next login
+	if _, err := config.NewOIDCHTTPClient(req.CAFile, req.CAData, req.InsecureSkipVerify); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid TLS configuration: %v", err)})
+		return
+	}
+
 	// Prepare ConfigMap data
 	adminRolesJSON, _ := json.Marshal(req.AdminRoles)
 	adminGroupsJSON, _ := json.Marshal(req.AdminGroups)
+	userRolesJSON, _ := json.Marshal(req.UserRoles)
+	viewerRolesJSON, _ := json.Marshal(req.ViewerRoles)
+	viewerGroupsJSON, _ := json.Marshal(req.ViewerGroups)
 
 	configMapData := map[string]string{
 		"enabled":       fmt.Sprintf("%t", req.Enabled),
@@ -147,7 +188,14 @@ func (h *OIDCConfigHandler) UpdateOIDCConfig(c *gin.Context) {
 		"groupsClaim":   req.GroupsClaim,
 		"adminRoles":    string(adminRolesJSON),
 		"adminGroups":   string(adminGroupsJSON),
+		"userRoles":     string(userRolesJSON),
+		"viewerRoles":   string(viewerRolesJSON),
+		"viewerGroups":  string(viewerGroupsJSON),
 		"defaultRole":   req.DefaultRole,
+
+		"caFile":             req.CAFile,
+		"caData":             req.CAData,
+		"insecureSkipVerify": fmt.Sprintf("%t", req.InsecureSkipVerify),
 	}
 
 	// Create or update ConfigMap
@@ -225,11 +273,28 @@ func (h *OIDCConfigHandler) UpdateOIDCConfig(c *gin.Context) {
 		}
 	}
 
-	// Trigger configuration reload by updating environment
-	// Note: In production, you might want to trigger a pod restart or use a webhook
-	config.ReloadOIDCConfig()
+	// Apply the stored configuration, including a client secret kept from before, to the
+	// running provider
+	oidcConfig, err := LoadOIDCConfigFromK8s(h.k8sClient)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("OIDC configuration saved, but could not be reloaded: %v", err)})
+		return
+	}
+	config.SetOIDCConfig(oidcConfig)
+
+	reloadCtx, cancel := context.WithTimeout(ctx, oidcReloadTimeout)
+	defer cancel()
+	if err := h.reloader.ReloadOIDCProvider(reloadCtx, oidcConfig); err != nil {
+		log.Printf("⚠️ Saved OIDC configuration could not be applied, keeping the current provider: %v", err)
+		c.JSON(http.StatusOK, gin.H{
+			"message": "OIDC configuration saved, but not applied: the current provider stays in use until it is fixed",
+			"applied": false,
+			"error":   err.Error(),
+		})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "OIDC configuration updated successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "OIDC configuration updated successfully", "applied": true})
 }
 
 // TestOIDCConnection tests the OIDC provider connection
@@ -238,6 +303,10 @@ func (h *OIDCConfigHandler) TestOIDCConnection(c *gin.Context) {
 		IssuerURL    string `json:"issuerURL" binding:"required"`
 		ClientID     string `json:"clientID" binding:"required"`
 		ClientSecret string `json:"clientSecret" binding:"required"`
+
+		CAFile             string `json:"caFile"`
+		CAData             string `json:"caData"`
+		InsecureSkipVerify bool   `json:"insecureSkipVerify"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -251,7 +320,16 @@ func (h *OIDCConfigHandler) TestOIDCConnection(c *gin.Context) {
 		return
 	}
 
+	httpClient, err := config.NewOIDCHTTPClient(req.CAFile, req.CAData, req.InsecureSkipVerify)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid TLS configuration: %v", err)})
+		return
+	}
+
 	ctx := context.Background()
+	if httpClient != nil {
+		ctx = oidc.ClientContext(ctx, httpClient)
+	}
 
 	// Try to create OIDC provider to test connection
 	provider, err := oidc.NewProvider(ctx, req.IssuerURL)
@@ -273,9 +351,19 @@ func (h *OIDCConfigHandler) TestOIDCConnection(c *gin.Context) {
 		return
 	}
 
+	credentials, err := checkClientCredentials(ctx, provider.Endpoint(), req.ClientID, req.ClientSecret)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     fmt.Sprintf("Connected to OIDC provider, but client authentication failed: %v", err),
+			"errorCode": credentials.ErrorCode,
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Successfully connected to OIDC provider",
-		"issuer":  claims.Issuer,
+		"message":     "Successfully connected to OIDC provider",
+		"issuer":      claims.Issuer,
+		"credentials": credentials,
 		"endpoints": gin.H{
 			"authorization": claims.AuthURL,
 			"token":         claims.TokenURL,
@@ -284,6 +372,54 @@ func (h *OIDCConfigHandler) TestOIDCConnection(c *gin.Context) {
 	})
 }
 
+// ClientCredentialsCheck is the outcome of authenticating the client at the token endpoint
+type ClientCredentialsCheck struct {
+	Valid     bool   `json:"valid"`
+	ErrorCode string `json:"errorCode,omitempty"`
+	Message   string `json:"message"`
+}
+
+// checkClientCredentials authenticates the client with a client_credentials token request.
+// The provider authenticates the client before checking whether it may use that grant, so
+// a refusal of the grant itself still means the credentials are good
+func checkClientCredentials(ctx context.Context, endpoint oauth2.Endpoint, clientID, clientSecret string) (ClientCredentialsCheck, error) {
+	credentialsConfig := &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     endpoint.TokenURL,
+		AuthStyle:    endpoint.AuthStyle,
+	}
+
+	if _, err := credentialsConfig.Token(ctx); err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if !stderrors.As(err, &retrieveErr) {
+			return ClientCredentialsCheck{Message: err.Error()}, fmt.Errorf("token request failed: %v", err)
+		}
+
+		check := ClientCredentialsCheck{ErrorCode: retrieveErr.ErrorCode, Message: retrieveErr.ErrorDescription}
+		if check.Message == "" {
+			check.Message = retrieveErr.Error()
+		}
+
+		// Older Keycloak releases report a wrong secret as unauthorized_client
+		wrongSecret := strings.Contains(strings.ToLower(retrieveErr.ErrorDescription), "secret")
+		switch retrieveErr.ErrorCode {
+		case "unauthorized_client", "unsupported_grant_type":
+			if !wrongSecret {
+				check.Valid = true
+				check.Message = fmt.Sprintf("Client credentials accepted (client_credentials grant not enabled: %s)", check.Message)
+				return check, nil
+			}
+		}
+		if check.ErrorCode == "" {
+			return check, fmt.Errorf("%s", check.Message)
+		}
+		return check, fmt.Errorf("%s: %s", check.ErrorCode, check.Message)
+	}
+
+	return ClientCredentialsCheck{Valid: true, Message: "Client credentials accepted"}, nil
+}
+
 // LoadOIDCConfigFromK8s loads OIDC configuration from Kubernetes ConfigMap and Secret
 func LoadOIDCConfigFromK8s(k8sClient *k8s.Client) (*config.OIDCConfig, error) {
 	ctx := context.Background()
@@ -316,6 +452,10 @@ func LoadOIDCConfigFromK8s(k8sClient *k8s.Client) (*config.OIDCConfig, error) {
 		RolesClaim:    configMap.Data["rolesClaim"],
 		GroupsClaim:   configMap.Data["groupsClaim"],
 		DefaultRole:   configMap.Data["defaultRole"],
+
+		CAFile:             configMap.Data["caFile"],
+		CAData:             configMap.Data["caData"],
+		InsecureSkipVerify: configMap.Data["insecureSkipVerify"] == "true",
 	}
 
 	// Set defaults if not specified
@@ -345,6 +485,9 @@ func LoadOIDCConfigFromK8s(k8sClient *k8s.Client) (*config.OIDCConfig, error) {
 	if adminGroupsStr := configMap.Data["adminGroups"]; adminGroupsStr != "" {
 		json.Unmarshal([]byte(adminGroupsStr), &oidcConfig.AdminGroups)
 	}
+	oidcConfig.UserRoles = parseRoleList(configMap.Data, "userRoles", config.DefaultUserRoles)
+	oidcConfig.ViewerRoles = parseRoleList(configMap.Data, "viewerRoles", config.DefaultViewerRoles)
+	oidcConfig.ViewerGroups = parseRoleList(configMap.Data, "viewerGroups", []string{})
 
 	// Get client secret from Secret
 	if secret != nil && secret.Data != nil {
@@ -353,3 +496,22 @@ func LoadOIDCConfigFromK8s(k8sClient *k8s.Client) (*config.OIDCConfig, error) {
 
 	return oidcConfig, nil
 }
+
+// parseRoleList reads a JSON array of role or group names from the ConfigMap, falling back
+// to defaults when the key is unset, e.g. in ConfigMaps written before it existed
+func parseRoleList(data map[string]string, key string, defaults []string) []string {
+	value := data[key]
+	if value == "" {
+		return defaults
+	}
+	var roles []string
+	if err := json.Unmarshal([]byte(value), &roles); err != nil {
+		log.Printf("Failed to parse %s: %v, using defaults", key, err)
+		return defaults
+	}
+	if roles == nil {
+		// "null" from a client that didn't send the key
+		return defaults
+	}
+	return roles
+}
@@ -0,0 +1,399 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// openAPIPrefix limits the spec to the REST API; probes and /metrics aren't part of it
+const openAPIPrefix = "/api/v1"
+
+// openAPIPublicPaths need no authentication, besides everything under /auth
+var openAPIPublicPaths = map[string]bool{
+	openAPIPrefix + "/health":                  true,
+	openAPIPrefix + "/openapi.json":            true,
+	openAPIPrefix + "/test/generate-mock-data": true,
+}
+
+// VeleroResource is a backup, restore or schedule as the list endpoints return it
+type VeleroResource struct {
+	Name              string                 `json:"name"`
+	Cluster           string                 `json:"cluster,omitempty"`
+	Namespace         string                 `json:"namespace"`
+	CreationTimestamp metav1.Time            `json:"creationTimestamp"`
+	Labels            map[string]string      `json:"labels"`
+	Spec              map[string]interface{} `json:"spec,omitempty"`
+	Status            map[string]interface{} `json:"status,omitempty"`
+}
+
+// newVeleroResource converts a Velero object into its list entry
+func newVeleroResource(obj unstructured.Unstructured, cluster string) VeleroResource {
+	spec, _ := obj.Object["spec"].(map[string]interface{})
+	status, _ := obj.Object["status"].(map[string]interface{})
+	return VeleroResource{
+		Name:              obj.GetName(),
+		Cluster:           cluster,
+		Namespace:         obj.GetNamespace(),
+		CreationTimestamp: obj.GetCreationTimestamp(),
+		Labels:            obj.GetLabels(),
+		Spec:              spec,
+		Status:            status,
+	}
+}
+
+// BackupList is the response of GET /backups
+type BackupList struct {
+	Backups []VeleroResource `json:"backups"`
+	Count   int              `json:"count"`
+}
+
+// RestoreList is the response of GET /restores
+type RestoreList struct {
+	Restores []VeleroResource `json:"restores"`
+	Count    int              `json:"count"`
+}
+
+// ScheduleList is the response of GET /schedules
+type ScheduleList struct {
+	Schedules []VeleroResource `json:"schedules"`
+	Count     int              `json:"count"`
+}
+
+// ClusterList is the response of GET /clusters
+type ClusterList struct {
+	Clusters []ClusterInfo `json:"clusters"`
+	Count    int           `json:"count"`
+}
+
+// ClusterScheduleList is the response of GET /clusters/:cluster/schedules
+type ClusterScheduleList struct {
+	Cluster   string            `json:"cluster"`
+	Schedules []ClusterSchedule `json:"schedules"`
+	Count     int               `json:"count"`
+}
+
+// ExpiringBackupList is the response of GET /backups/expiring
+type ExpiringBackupList struct {
+	Backups []ExpiringBackup `json:"backups"`
+	Count   int              `json:"count"`
+	Within  string           `json:"within"`
+}
+
+// OverdueScheduleList is the response of GET /schedules/overdue
+type OverdueScheduleList struct {
+	Schedules []OverdueSchedule `json:"schedules"`
+	Count     int               `json:"count"`
+	Factor    float64           `json:"factor"`
+}
+
+// CreatedResponse is the response of the create endpoints, which also name the created
+// object under its kind, e.g. "backup"
+type CreatedResponse struct {
+	Message string `json:"message"`
+	Status  string `json:"status"`
+	Warning string `json:"warning,omitempty"`
+}
+
+// RestoreChainStarted is the response of POST /restores/chains
+type RestoreChainStarted struct {
+	Message string       `json:"message"`
+	Chain   RestoreChain `json:"chain"`
+}
+
+// openAPIOperation documents one route. Routes without an entry are still listed, with
+// untyped bodies.
+type openAPIOperation struct {
+	Summary string
+	// Request and Response are zero values of the body types, nil for none/untyped
+	Request  interface{}
+	Response interface{}
+	// Status of a successful response, 200 unless set
+	Status int
+}
+
+// openAPIOperations documents the routes whose bodies have Go types, keyed by
+// "METHOD path" as registered with gin
+var openAPIOperations = map[string]openAPIOperation{
+	"GET /api/v1/backups":             {Summary: "List backups", Response: BackupList{}},
+	"POST /api/v1/backups":            {Summary: "Create a backup", Request: CreateBackupRequest{}, Response: CreatedResponse{}, Status: http.StatusCreated},
+	"DELETE /api/v1/backups/:name":    {Summary: "Delete a backup"},
+	"GET /api/v1/backups/expiring":    {Summary: "List backups expiring soon", Response: ExpiringBackupList{}},
+	"PATCH /api/v1/backups/:name/ttl": {Summary: "Change a backup's TTL"},
+	"POST /api/v1/backups/apply":      {Summary: "Create a backup from a Velero manifest (YAML or JSON)", Response: CreatedResponse{}, Status: http.StatusCreated},
+
+	"GET /api/v1/restores":          {Summary: "List restores", Response: RestoreList{}},
+	"POST /api/v1/restores":         {Summary: "Create a restore", Request: CreateRestoreRequest{}, Response: CreatedResponse{}, Status: http.StatusCreated},
+	"DELETE /api/v1/restores/:name": {Summary: "Delete a restore"},
+	"POST /api/v1/restores/apply":   {Summary: "Create a restore from a Velero manifest (YAML or JSON)", Response: CreatedResponse{}, Status: http.StatusCreated},
+	"POST /api/v1/restores/chains": {
+		Summary: "Start an ordered chain of restores", Request: CreateRestoreChainRequest{}, Response: RestoreChainStarted{}, Status: http.StatusAccepted,
+	},
+	"GET /api/v1/restores/chains/:id": {Summary: "Get a restore chain", Response: RestoreChain{}},
+
+	"GET /api/v1/schedules":          {Summary: "List schedules", Response: ScheduleList{}},
+	"POST /api/v1/schedules":         {Summary: "Create a schedule", Request: CreateScheduleRequest{}, Response: CreatedResponse{}, Status: http.StatusCreated},
+	"PUT /api/v1/schedules/:name":    {Summary: "Update a schedule", Request: UpdateScheduleRequest{}},
+	"DELETE /api/v1/schedules/:name": {Summary: "Delete a schedule"},
+	"GET /api/v1/schedules/overdue":  {Summary: "List schedules overdue for a backup", Response: OverdueScheduleList{}},
+
+	"GET /api/v1/clusters":                    {Summary: "List managed clusters", Response: ClusterList{}},
+	"GET /api/v1/clusters/:cluster/schedules": {Summary: "List a cluster's schedules and CronJobs", Response: ClusterScheduleList{}},
+	"POST /api/v1/clusters/:cluster/restore-latest": {
+		Summary: "Restore a cluster's latest completed backup", Response: CreatedResponse{}, Status: http.StatusCreated,
+	},
+}
+
+// OpenAPIHandler serves an OpenAPI 3 description of the API, built from the registered
+// routes and the Go types of their bodies
+type OpenAPIHandler struct {
+	router  *gin.Engine
+	version string
+
+	once sync.Once
+	spec map[string]interface{}
+}
+
+// NewOpenAPIHandler creates a handler describing the routes registered on router
+func NewOpenAPIHandler(router *gin.Engine, version string) *OpenAPIHandler {
+	return &OpenAPIHandler{router: router, version: version}
+}
+
+// GetSpec returns the OpenAPI document. It is built on first request, once every route
+// has been registered.
+func (h *OpenAPIHandler) GetSpec(c *gin.Context) {
+	h.once.Do(func() {
+		h.spec = buildOpenAPISpec(h.router.Routes(), h.version)
+	})
+	c.JSON(http.StatusOK, h.spec)
+}
+
+// buildOpenAPISpec describes the routes under openAPIPrefix
+func buildOpenAPISpec(routes gin.RoutesInfo, version string) map[string]interface{} {
+	schemas := newSchemaBuilder()
+	errorResponse := map[string]interface{}{
+		"description": "Error",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schemas.schema(reflect.TypeOf(APIError{}))},
+		},
+	}
+
+	paths := make(map[string]interface{})
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, openAPIPrefix) {
+			continue
+		}
+
+		path, parameters := openAPIPath(route.Path)
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[path] = item
+		}
+
+		doc := openAPIOperations[route.Method+" "+route.Path]
+		status := doc.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		success := map[string]interface{}{"description": http.StatusText(status)}
+		if doc.Response != nil {
+			success["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": schemas.schema(reflect.TypeOf(doc.Response))},
+			}
+		}
+
+		operation := map[string]interface{}{
+			"operationId": handlerName(route.Handler),
+			"tags":        []string{openAPITag(route.Path)},
+			"responses": map[string]interface{}{
+				strconv.Itoa(status): success,
+				"default":            errorResponse,
+			},
+		}
+		if doc.Summary != "" {
+			operation["summary"] = doc.Summary
+		}
+		if len(parameters) > 0 {
+			operation["parameters"] = parameters
+		}
+		if doc.Request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": schemas.schema(reflect.TypeOf(doc.Request))},
+				},
+			}
+		}
+		// Login, the OIDC flow and a few others are public
+		if strings.HasPrefix(route.Path, openAPIPrefix+"/auth/") || openAPIPublicPaths[route.Path] {
+			operation["security"] = []interface{}{}
+		}
+
+		item[strings.ToLower(route.Method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Velero Manager API",
+			"version": version,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas.components,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []interface{}{map[string]interface{}{"bearerAuth": []string{}}},
+	}
+}
+
+// openAPIPath converts a gin path such as /backups/:name to /backups/{name} and returns
+// its path parameters
+func openAPIPath(path string) (string, []interface{}) {
+	var parameters []interface{}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+		parameters = append(parameters, map[string]interface{}{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	return strings.Join(segments, "/"), parameters
+}
+
+// openAPITag groups operations by the first path segment after the prefix
+func openAPITag(path string) string {
+	rest := strings.TrimPrefix(path, openAPIPrefix+"/")
+	if i := strings.Index(rest, "/"); i != -1 {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+// handlerName turns gin's handler name, e.g.
+// velero-manager/pkg/handlers.(*VeleroHandler).ListBackups-fm, into ListBackups
+func handlerName(name string) string {
+	name = strings.TrimSuffix(name, "-fm")
+	if i := strings.LastIndex(name, "."); i != -1 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// schemaBuilder turns Go types into JSON schemas the way encoding/json serializes them,
+// collecting named structs as reusable components
+type schemaBuilder struct {
+	components map[string]interface{}
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{components: make(map[string]interface{})}
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	metaTimeType = reflect.TypeOf(metav1.Time{})
+)
+
+func (b *schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	if t == timeType || t == metaTimeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := b.schema(t.Elem())
+		if _, isRef := schema["$ref"]; !isRef {
+			schema["nullable"] = true
+		}
+		return schema
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		if _, exists := b.components[t.Name()]; !exists {
+			// Reserve the name first so self-referencing types terminate
+			b.components[t.Name()] = map[string]interface{}{}
+			b.components[t.Name()] = b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		// interface{} and anything else accepts any value
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema describes a struct's JSON fields, flattening embedded structs as
+// encoding/json does. Fields with a binding:"required" tag are required.
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	b.addFields(t, properties, &required)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (b *schemaBuilder) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			b.addFields(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = b.schema(field.Type)
+		if strings.Contains(field.Tag.Get("binding"), "required") {
+			*required = append(*required, name)
+		}
+	}
+}
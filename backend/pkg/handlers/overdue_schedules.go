@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+	"velero-manager/pkg/config"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// OverdueSchedule is an active schedule that has gone too long without a backup
+type OverdueSchedule struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	// LastBackup is nil for schedules that have never produced a backup
+	LastBackup *time.Time `json:"lastBackup"`
+	// OverdueSince is when the schedule crossed SCHEDULE_OVERDUE_FACTOR periods
+	OverdueSince     time.Time `json:"overdueSince"`
+	OverdueBySeconds int64     `json:"overdueBySeconds"`
+	Message          string    `json:"message"`
+}
+
+// ListOverdueSchedules lists the active schedules whose last backup is older than
+// SCHEDULE_OVERDUE_FACTOR times their period, longest overdue first. These are the
+// schedules that velero_schedule_overdue reports.
+func (h *VeleroHandler) ListOverdueSchedules(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	scheduleList, err := h.k8sClient.ListCache.List(ctx, k8s.ScheduleGVR, "velero")
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeScheduleListFailed, err)
+		return
+	}
+
+	factor := config.GetBackupConfig().ScheduleOverdueFactor
+	now := time.Now()
+	overdue := []OverdueSchedule{}
+	for i := range scheduleList.Items {
+		schedule := &scheduleList.Items[i]
+		if paused, _, _ := unstructured.NestedBool(schedule.Object, "spec", "paused"); paused {
+			continue
+		}
+
+		// Invalid expressions are reported by /schedules/broken and validation instead
+		last, deadline, err := k8s.ScheduleDeadline(schedule, factor)
+		if err != nil || deadline.IsZero() || !now.After(deadline) {
+			continue
+		}
+
+		expression, _, _ := unstructured.NestedString(schedule.Object, "spec", "schedule")
+		entry := OverdueSchedule{
+			Name:             schedule.GetName(),
+			Schedule:         expression,
+			OverdueSince:     deadline,
+			OverdueBySeconds: int64(now.Sub(deadline).Seconds()),
+		}
+
+		age := now.Sub(last).Round(time.Minute)
+		if lastBackup, _, _ := unstructured.NestedString(schedule.Object, "status", "lastBackup"); lastBackup != "" {
+			entry.LastBackup = &last
+			entry.Message = fmt.Sprintf("No backup for %s; schedule %q should have run by now", age, expression)
+		} else {
+			entry.Message = fmt.Sprintf("No backup since the schedule was created %s ago; schedule %q should have run by now", age, expression)
+		}
+		overdue = append(overdue, entry)
+	}
+
+	sort.Slice(overdue, func(i, j int) bool {
+		return overdue[i].OverdueSince.Before(overdue[j].OverdueSince)
+	})
+
+	c.JSON(http.StatusOK, OverdueScheduleList{Schedules: overdue, Count: len(overdue), Factor: factor})
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"fmt"
+)
+
+// ScopedResourceFilters are Velero's fine-grained resource filters (Velero 1.11+), which
+// replace includedResources/excludedResources/includeClusterResources
+type ScopedResourceFilters struct {
+	IncludedClusterScopedResources   []string `json:"includedClusterScopedResources,omitempty"`
+	ExcludedClusterScopedResources   []string `json:"excludedClusterScopedResources,omitempty"`
+	IncludedNamespaceScopedResources []string `json:"includedNamespaceScopedResources,omitempty"`
+	ExcludedNamespaceScopedResources []string `json:"excludedNamespaceScopedResources,omitempty"`
+}
+
+// scopedResourceFields are the spec keys of ScopedResourceFilters
+var scopedResourceFields = []string{
+	"includedClusterScopedResources",
+	"excludedClusterScopedResources",
+	"includedNamespaceScopedResources",
+	"excludedNamespaceScopedResources",
+}
+
+// legacyResourceFields are the older filters Velero refuses to combine with the scoped ones
+var legacyResourceFields = []string{
+	"includedResources",
+	"excludedResources",
+	"includeClusterResources",
+}
+
+func (f *ScopedResourceFilters) fields() map[string][]string {
+	return map[string][]string{
+		"includedClusterScopedResources":   f.IncludedClusterScopedResources,
+		"excludedClusterScopedResources":   f.ExcludedClusterScopedResources,
+		"includedNamespaceScopedResources": f.IncludedNamespaceScopedResources,
+		"excludedNamespaceScopedResources": f.ExcludedNamespaceScopedResources,
+	}
+}
+
+// applyTo writes the filters into a backup spec or schedule template. Omitted filters are
+// left untouched and empty ones are removed, so it serves both create and update.
+func (f *ScopedResourceFilters) applyTo(spec map[string]interface{}) {
+	for field, values := range f.fields() {
+		if values == nil {
+			continue
+		}
+		if len(values) > 0 {
+			spec[field] = values
+		} else {
+			delete(spec, field)
+		}
+	}
+}
+
+// validateResourceFilters rejects a backup spec that mixes the scoped filters with the
+// legacy ones, which Velero would otherwise fail with FailedValidation
+func validateResourceFilters(spec map[string]interface{}) error {
+	var scoped, legacy []string
+	for _, field := range scopedResourceFields {
+		if _, set := spec[field]; set {
+			scoped = append(scoped, field)
+		}
+	}
+	for _, field := range legacyResourceFields {
+		if _, set := spec[field]; set {
+			legacy = append(legacy, field)
+		}
+	}
+
+	if len(scoped) > 0 && len(legacy) > 0 {
+		return fmt.Errorf("%v cannot be combined with %v; use only the scoped resource filters", scoped, legacy)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// resourcePolicyKind is the only kind of resource policy Velero supports
+const resourcePolicyKind = "configmap"
+
+// errInvalidResourcePolicy wraps the ways a resource policy reference can be wrong, as
+// opposed to failing to check it
+var errInvalidResourcePolicy = errors.New("invalid resourcePolicy")
+
+// ResourcePolicy references a ConfigMap in the velero namespace holding Velero resource
+// policies, e.g. which volumes to skip
+type ResourcePolicy struct {
+	// Kind defaults to, and must be, configmap
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name"`
+}
+
+// validate checks the reference and that the ConfigMap exists. A wrong reference fails
+// with errInvalidResourcePolicy; anything else is a failure to read the ConfigMap.
+func (p *ResourcePolicy) validate(ctx context.Context, h *VeleroHandler) error {
+	if p.Kind == "" {
+		p.Kind = resourcePolicyKind
+	}
+	if !strings.EqualFold(p.Kind, resourcePolicyKind) {
+		return fmt.Errorf("%w: kind must be %s, got %q", errInvalidResourcePolicy, resourcePolicyKind, p.Kind)
+	}
+	if p.Name == "" {
+		return fmt.Errorf("%w: name is required", errInvalidResourcePolicy)
+	}
+
+	_, err := h.k8sClient.Clientset.CoreV1().ConfigMaps("velero").Get(ctx, p.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%w: ConfigMap %s not found in the velero namespace", errInvalidResourcePolicy, p.Name)
+	}
+	return err
+}
+
+// toSpec returns the resourcePolicy field of a backup spec or schedule template
+func (p *ResourcePolicy) toSpec() map[string]interface{} {
+	return map[string]interface{}{
+		"kind": p.Kind,
+		"name": p.Name,
+	}
+}
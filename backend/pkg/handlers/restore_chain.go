@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Restore chain and step statuses
+const (
+	chainStatusPending   = "Pending"
+	chainStatusRunning   = "Running"
+	chainStatusCompleted = "Completed"
+	chainStatusFailed    = "Failed"
+	chainStatusSkipped   = "Skipped"
+
+	restoreChainLabel       = "velero-manager.io/restore-chain"
+	restoreChainStepTimeout = 2 * time.Hour
+	// How long finished chains are kept for status reporting
+	restoreChainRetention = 24 * time.Hour
+)
+
+// RestoreChainStep is one restore in an ordered chain
+type RestoreChainStep struct {
+	Name               string            `json:"name" binding:"required"`
+	BackupName         string            `json:"backupName" binding:"required"`
+	IncludedNamespaces []string          `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string          `json:"excludedNamespaces,omitempty"`
+	NamespaceMapping   map[string]string `json:"namespaceMapping,omitempty"`
+	RestorePVs         *bool             `json:"restorePVs,omitempty"`
+	// Steps that must reach Completed before this one starts
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	Status      string     `json:"status"`
+	Restore     string     `json:"restore,omitempty"`
+	Phase       string     `json:"phase,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// RestoreChain runs a set of restores in dependency order, e.g. databases before apps
+type RestoreChain struct {
+	ID          string             `json:"id"`
+	Status      string             `json:"status"`
+	Steps       []RestoreChainStep `json:"steps"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	CompletedAt *time.Time         `json:"completedAt,omitempty"`
+}
+
+// restoreChainStore keeps restore chains in memory for status reporting, until
+// restoreChainRetention after they finish
+type restoreChainStore struct {
+	chains map[string]*RestoreChain
+	mutex  sync.RWMutex
+}
+
+func newRestoreChainStore() *restoreChainStore {
+	return &restoreChainStore{
+		chains: make(map[string]*RestoreChain),
+	}
+}
+
+// snapshot returns a copy of a chain that is safe to serialize while it runs
+func (s *restoreChainStore) snapshot(id string) (RestoreChain, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	chain, exists := s.chains[id]
+	if !exists {
+		return RestoreChain{}, false
+	}
+
+	copied := *chain
+	copied.Steps = append([]RestoreChainStep(nil), chain.Steps...)
+	return copied, true
+}
+
+// prune forgets chains that finished more than restoreChainRetention ago. The caller
+// holds the store lock.
+func (s *restoreChainStore) prune(now time.Time) {
+	for id, chain := range s.chains {
+		if chain.CompletedAt != nil && now.Sub(*chain.CompletedAt) > restoreChainRetention {
+			delete(s.chains, id)
+		}
+	}
+}
+
+// newRestoreChainID returns a chain ID that sorts by start time, with a random suffix so
+// chains started in the same second don't collide
+func newRestoreChainID(now time.Time) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("chain-%s-%s", now.Format("20060102-150405"), hex.EncodeToString(suffix)), nil
+}
+
+// update applies a change to a chain under the store lock
+func (s *restoreChainStore) update(id string, fn func(chain *RestoreChain)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if chain, exists := s.chains[id]; exists {
+		fn(chain)
+	}
+}
+
+// orderRestoreChainSteps validates step names, namespace mappings and dependencies and
+// returns the step indexes in an order where every step comes after its prerequisites.
+// Step names become part of restore names, so they must be DNS-1123 labels.
+func orderRestoreChainSteps(steps []RestoreChainStep) ([]int, error) {
+	index := make(map[string]int, len(steps))
+	for i, step := range steps {
+		if errs := validation.IsDNS1123Label(step.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid step name %q: %s", step.Name, strings.Join(errs, "; "))
+		}
+		if _, exists := index[step.Name]; exists {
+			return nil, fmt.Errorf("duplicate step name %q", step.Name)
+		}
+		if err := validateNamespaceMapping(step.NamespaceMapping); err != nil {
+			return nil, fmt.Errorf("step %q: %w", step.Name, err)
+		}
+		index[step.Name] = i
+	}
+
+	pending := make([]int, len(steps))
+	dependents := make(map[int][]int)
+	for i, step := range steps {
+		for _, dependency := range step.DependsOn {
+			j, exists := index[dependency]
+			if !exists {
+				return nil, fmt.Errorf("step %q depends on unknown step %q", step.Name, dependency)
+			}
+			if j == i {
+				return nil, fmt.Errorf("step %q depends on itself", step.Name)
+			}
+			pending[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	// Kahn's algorithm, keeping the request order among steps that are ready together
+	var ready, order []int
+	for i := range steps {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		current := ready[0]
+		ready = ready[1:]
+		order = append(order, current)
+
+		for _, dependent := range dependents[current] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+		sort.Ints(ready)
+	}
+
+	if len(order) != len(steps) {
+		return nil, fmt.Errorf("step dependencies contain a cycle")
+	}
+
+	return order, nil
+}
+
+// CreateRestoreChainRequest is the body of POST /restores/chains
+type CreateRestoreChainRequest struct {
+	Steps []RestoreChainStep `json:"steps" binding:"required,min=1,dive"`
+}
+
+// CreateRestoreChain starts an ordered chain of restores
+func (h *VeleroHandler) CreateRestoreChain(c *gin.Context) {
+	var request CreateRestoreChainRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	order, err := orderRestoreChainSteps(request.Steps)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid restore chain",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	id, err := newRestoreChainID(now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to generate a restore chain ID",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	chain := &RestoreChain{
+		ID:        id,
+		Status:    chainStatusPending,
+		Steps:     request.Steps,
+		CreatedAt: now,
+	}
+	for i := range chain.Steps {
+		step := &chain.Steps[i]
+		step.Status = chainStatusPending
+		step.Restore = fmt.Sprintf("%s-%s", chain.ID, step.Name)
+		step.Phase, step.Error = "", ""
+		step.StartedAt, step.CompletedAt = nil, nil
+	}
+
+	h.restoreChains.mutex.Lock()
+	h.restoreChains.prune(now)
+	h.restoreChains.chains[chain.ID] = chain
+	h.restoreChains.mutex.Unlock()
+
+	go h.runRestoreChain(chain.ID, order)
+
+	snapshot, _ := h.restoreChains.snapshot(chain.ID)
+	c.JSON(http.StatusAccepted, RestoreChainStarted{Message: "Restore chain started", Chain: snapshot})
+}
+
+// runRestoreChain executes the steps in order, waiting for each restore to complete.
+// A failed step aborts the chain and every step that hasn't started is skipped.
+func (h *VeleroHandler) runRestoreChain(id string, order []int) {
+	h.restoreChains.update(id, func(chain *RestoreChain) {
+		chain.Status = chainStatusRunning
+	})
+
+	for n, i := range order {
+		snapshot, _ := h.restoreChains.snapshot(id)
+		step := snapshot.Steps[i]
+
+		now := time.Now()
+		h.restoreChains.update(id, func(chain *RestoreChain) {
+			chain.Steps[i].Status = chainStatusRunning
+			chain.Steps[i].StartedAt = &now
+		})
+
+		phase, err := h.runRestoreChainStep(id, step)
+
+		completedAt := time.Now()
+		h.restoreChains.update(id, func(chain *RestoreChain) {
+			chain.Steps[i].Phase = phase
+			chain.Steps[i].CompletedAt = &completedAt
+			if err == nil {
+				chain.Steps[i].Status = chainStatusCompleted
+				return
+			}
+
+			chain.Steps[i].Status = chainStatusFailed
+			chain.Steps[i].Error = err.Error()
+			for _, remaining := range order[n+1:] {
+				chain.Steps[remaining].Status = chainStatusSkipped
+				chain.Steps[remaining].Error = fmt.Sprintf("prerequisite chain step %q failed", step.Name)
+			}
+			chain.Status = chainStatusFailed
+			chain.CompletedAt = &completedAt
+		})
+
+		if err != nil {
+			log.Printf("❌ Restore chain %s aborted: step %s failed: %v", id, step.Name, err)
+			return
+		}
+	}
+
+	completedAt := time.Now()
+	h.restoreChains.update(id, func(chain *RestoreChain) {
+		chain.Status = chainStatusCompleted
+		chain.CompletedAt = &completedAt
+	})
+	log.Printf("✅ Restore chain %s completed", id)
+}
+
+// runRestoreChainStep creates the step's restore and waits for it to finish
+func (h *VeleroHandler) runRestoreChainStep(chainID string, step RestoreChainStep) (string, error) {
+	spec := map[string]interface{}{
+		"backupName": step.BackupName,
+	}
+	if len(step.IncludedNamespaces) > 0 {
+		spec["includedNamespaces"] = step.IncludedNamespaces
+	}
+	if len(step.ExcludedNamespaces) > 0 {
+		spec["excludedNamespaces"] = step.ExcludedNamespaces
+	}
+	if len(step.NamespaceMapping) > 0 {
+		spec["namespaceMapping"] = step.NamespaceMapping
+	}
+	if step.RestorePVs != nil {
+		spec["restorePVs"] = *step.RestorePVs
+	}
+
+	restore := map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       "Restore",
+		"metadata": map[string]interface{}{
+			"name":      step.Restore,
+			"namespace": "velero",
+			"labels": map[string]interface{}{
+				restoreChainLabel: chainID,
+			},
+		},
+		"spec": spec,
+	}
+
+	result, err := h.k8sClient.DynamicClient.
+		Resource(k8s.RestoreGVR).
+		Namespace("velero").
+		Create(h.k8sClient.Context, &unstructured.Unstructured{Object: restore}, metav1.CreateOptions{})
+	h.k8sClient.ListCache.Invalidate(k8s.RestoreGVR)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to create restore: %v", err)
+	}
+
+	final, err := h.waitForPhase(k8s.RestoreGVR, result, restoreChainStepTimeout, restoreTerminalPhases)
+	phase, _, _ := unstructured.NestedString(final.Object, "status", "phase")
+	if err != nil {
+		return phase, err
+	}
+	if phase != "Completed" {
+		return phase, fmt.Errorf("restore %s finished with phase %s", step.Restore, phase)
+	}
+
+	return phase, nil
+}
+
+// ListRestoreChains returns the restore chains started by this instance that are running
+// or finished recently, newest first
+func (h *VeleroHandler) ListRestoreChains(c *gin.Context) {
+	h.restoreChains.mutex.Lock()
+	h.restoreChains.prune(time.Now())
+	ids := make([]string, 0, len(h.restoreChains.chains))
+	for id := range h.restoreChains.chains {
+		ids = append(ids, id)
+	}
+	h.restoreChains.mutex.Unlock()
+
+	chains := make([]RestoreChain, 0, len(ids))
+	for _, id := range ids {
+		if chain, exists := h.restoreChains.snapshot(id); exists {
+			chains = append(chains, chain)
+		}
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i].CreatedAt.After(chains[j].CreatedAt)
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"chains": chains,
+		"count":  len(chains),
+	})
+}
+
+// GetRestoreChain returns the status of a restore chain and each of its steps
+func (h *VeleroHandler) GetRestoreChain(c *gin.Context) {
+	id := c.Param("id")
+
+	chain, exists := h.restoreChains.snapshot(id)
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Restore chain not found",
+			"chain": id,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, chain)
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"velero-manager/pkg/k8s"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// finishRestores makes Velero finish every restore as soon as it is created, with the
+// phase returned by phaseOf, and records the order restores were created in
+func finishRestores(client *k8s.Client, phaseOf func(name string) string) func() []string {
+	var mutex sync.Mutex
+	var created []string
+	fakeDynamic(client).PrependReactor("create", "restores", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		restore := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		restore.Object["status"] = map[string]interface{}{"phase": phaseOf(restore.GetName())}
+
+		mutex.Lock()
+		created = append(created, restore.GetName())
+		mutex.Unlock()
+		return false, nil, nil
+	})
+	return func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string(nil), created...)
+	}
+}
+
+func TestOrderRestoreChainSteps(t *testing.T) {
+	steps := []RestoreChainStep{
+		{Name: "app", BackupName: "nightly", DependsOn: []string{"db"}},
+		{Name: "db", BackupName: "nightly"},
+	}
+	order, err := orderRestoreChainSteps(steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != 2 || order[0] != 1 || order[1] != 0 {
+		t.Errorf("order = %v, want db (1) before app (0)", order)
+	}
+
+	invalid := map[string][]RestoreChainStep{
+		"uppercase name":   {{Name: "Database", BackupName: "nightly"}},
+		"name with dots":   {{Name: "db.primary", BackupName: "nightly"}},
+		"name too long":    {{Name: strings.Repeat("a", 64), BackupName: "nightly"}},
+		"duplicate name":   {{Name: "db", BackupName: "nightly"}, {Name: "db", BackupName: "nightly"}},
+		"unknown step":     {{Name: "app", BackupName: "nightly", DependsOn: []string{"db"}}},
+		"self dependency":  {{Name: "db", BackupName: "nightly", DependsOn: []string{"db"}}},
+		"dependency cycle": {{Name: "a", BackupName: "nightly", DependsOn: []string{"b"}}, {Name: "b", BackupName: "nightly", DependsOn: []string{"a"}}},
+	}
+	for name, steps := range invalid {
+		if _, err := orderRestoreChainSteps(steps); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+func TestCreateRestoreChainRejectsInvalidStepName(t *testing.T) {
+	handler := NewVeleroHandler(newTestClient(), nil)
+	w := serve(handler.CreateRestoreChain, http.MethodPost, "/api/v1/restores/chains", map[string]interface{}{
+		"steps": []map[string]interface{}{{"name": "My_DB", "backupName": "nightly"}},
+	}, nil, "admin")
+	assertStatus(t, w, http.StatusBadRequest)
+}
+
+func TestRestoreChainRunsStepsInDependencyOrder(t *testing.T) {
+	client := newTestClient()
+	createdRestores := finishRestores(client, func(string) string { return "Completed" })
+	handler := NewVeleroHandler(client, nil)
+
+	steps := []RestoreChainStep{
+		{Name: "app", BackupName: "nightly", DependsOn: []string{"db"}, Restore: "chain-test-app"},
+		{Name: "db", BackupName: "nightly", IncludedNamespaces: []string{"postgres"}, Restore: "chain-test-db"},
+	}
+	order, err := orderRestoreChainSteps(steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.restoreChains.chains["chain-test"] = &RestoreChain{ID: "chain-test", Steps: steps}
+
+	handler.runRestoreChain("chain-test", order)
+
+	if got := createdRestores(); len(got) != 2 || got[0] != "chain-test-db" || got[1] != "chain-test-app" {
+		t.Errorf("restores created in order %v, want chain-test-db then chain-test-app", got)
+	}
+
+	chain, _ := handler.restoreChains.snapshot("chain-test")
+	if chain.Status != chainStatusCompleted || chain.CompletedAt == nil {
+		t.Errorf("chain status = %s, completedAt = %v; want Completed", chain.Status, chain.CompletedAt)
+	}
+	for _, step := range chain.Steps {
+		if step.Status != chainStatusCompleted || step.Phase != "Completed" {
+			t.Errorf("step %s: status %s, phase %s; want Completed", step.Name, step.Status, step.Phase)
+		}
+	}
+
+	restore, err := client.DynamicClient.Resource(k8s.RestoreGVR).Namespace("velero").
+		Get(context.Background(), "chain-test-db", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restore.GetLabels()[restoreChainLabel] != "chain-test" {
+		t.Errorf("restore labels = %v, want the chain label", restore.GetLabels())
+	}
+	if namespaces, _, _ := unstructured.NestedStringSlice(restore.Object, "spec", "includedNamespaces"); len(namespaces) != 1 || namespaces[0] != "postgres" {
+		t.Errorf("includedNamespaces = %v, want [postgres]", namespaces)
+	}
+}
+
+func TestRestoreChainAbortsWhenPrerequisiteFails(t *testing.T) {
+	client := newTestClient()
+	createdRestores := finishRestores(client, func(name string) string {
+		if strings.HasSuffix(name, "-db") {
+			return "PartiallyFailed"
+		}
+		return "Completed"
+	})
+	handler := NewVeleroHandler(client, nil)
+
+	handler.restoreChains.chains["chain-test"] = &RestoreChain{ID: "chain-test", Steps: []RestoreChainStep{
+		{Name: "db", BackupName: "nightly", Restore: "chain-test-db"},
+		{Name: "app", BackupName: "nightly", DependsOn: []string{"db"}, Restore: "chain-test-app"},
+	}}
+	handler.runRestoreChain("chain-test", []int{0, 1})
+
+	if got := createdRestores(); len(got) != 1 || got[0] != "chain-test-db" {
+		t.Errorf("restores created = %v, want only chain-test-db", got)
+	}
+
+	chain, _ := handler.restoreChains.snapshot("chain-test")
+	if chain.Status != chainStatusFailed || chain.CompletedAt == nil {
+		t.Errorf("chain status = %s, want Failed", chain.Status)
+	}
+	if db := chain.Steps[0]; db.Status != chainStatusFailed || db.Phase != "PartiallyFailed" || db.Error == "" {
+		t.Errorf("db step = %+v, want Failed with phase PartiallyFailed", db)
+	}
+	if app := chain.Steps[1]; app.Status != chainStatusSkipped || !strings.Contains(app.Error, `"db"`) {
+		t.Errorf("app step = %+v, want Skipped because db failed", app)
+	}
+}
+
+func TestCreateRestoreChainGeneratesUniqueIDs(t *testing.T) {
+	client := newTestClient()
+	finishRestores(client, func(string) string { return "Completed" })
+	handler := NewVeleroHandler(client, nil)
+
+	body := map[string]interface{}{"steps": []map[string]interface{}{{"name": "db", "backupName": "nightly"}}}
+	ids := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		w := serve(handler.CreateRestoreChain, http.MethodPost, "/api/v1/restores/chains", body, nil, "admin")
+		assertStatus(t, w, http.StatusAccepted)
+		chain, _ := decodeBody(t, w)["chain"].(map[string]interface{})
+		id, _ := chain["id"].(string)
+		if !strings.HasPrefix(id, "chain-") || ids[id] {
+			t.Errorf("chain ID %q is malformed or reused", id)
+		}
+		ids[id] = true
+	}
+}
+
+func TestRestoreChainStorePrunesFinishedChains(t *testing.T) {
+	now := time.Now()
+	finishedLongAgo := now.Add(-restoreChainRetention - time.Minute)
+	finishedRecently := now.Add(-time.Minute)
+
+	store := newRestoreChainStore()
+	store.chains["old"] = &RestoreChain{ID: "old", Status: chainStatusCompleted, CompletedAt: &finishedLongAgo}
+	store.chains["recent"] = &RestoreChain{ID: "recent", Status: chainStatusFailed, CompletedAt: &finishedRecently}
+	store.chains["running"] = &RestoreChain{ID: "running", Status: chainStatusRunning, CreatedAt: now.Add(-48 * time.Hour)}
+
+	store.prune(now)
+
+	if _, exists := store.chains["old"]; exists {
+		t.Error("chain finished past the retention is still kept")
+	}
+	for _, id := range []string{"recent", "running"} {
+		if _, exists := store.chains[id]; !exists {
+			t.Errorf("chain %s was pruned", id)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Outcomes of a schedule validation check; only failures make the schedule invalid
+const (
+	checkPassed  = "passed"
+	checkWarning = "warning"
+	checkFailed  = "failed"
+)
+
+// ScheduleCheck is the result of one schedule validation check
+type ScheduleCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type scheduleReport struct {
+	checks []ScheduleCheck
+}
+
+func (r *scheduleReport) add(name, status, format string, args ...interface{}) {
+	r.checks = append(r.checks, ScheduleCheck{Name: name, Status: status, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *scheduleReport) valid() bool {
+	for _, check := range r.checks {
+		if check.Status == checkFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateSchedule checks a schedule's cron expression and the Backup its template would
+// produce, without creating anything, so misconfigured schedules show up before they run
+func (h *VeleroHandler) ValidateSchedule(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	name := c.Param("name")
+
+	schedule, err := h.k8sClient.DynamicClient.
+		Resource(k8s.ScheduleGVR).
+		Namespace("velero").
+		Get(ctx, name, metav1.GetOptions{})
+
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusNotFound, ErrCodeScheduleNotFound, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeScheduleGetFailed, err)
+		return
+	}
+
+	report := &scheduleReport{}
+	h.checkScheduleExpression(report, schedule)
+
+	template, err := scheduleTemplate(schedule)
+	if err != nil {
+		report.add("template", checkFailed, "%v", err)
+	} else {
+		h.checkScheduleTemplate(c, report, template)
+	}
+
+	if validationErrors, _, _ := unstructured.NestedStringSlice(schedule.Object, "status", "validationErrors"); len(validationErrors) > 0 {
+		report.add("veleroValidation", checkFailed, "Velero rejected the schedule: %s", strings.Join(validationErrors, "; "))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"schedule": name,
+		"valid":    report.valid(),
+		"checks":   report.checks,
+	})
+}
+
+func (h *VeleroHandler) checkScheduleExpression(report *scheduleReport, schedule *unstructured.Unstructured) {
+	expression, _, _ := unstructured.NestedString(schedule.Object, "spec", "schedule")
+	if expression == "" {
+		report.add("schedule", checkFailed, "no cron expression set")
+		return
+	}
+
+	parsed, err := k8s.ParseSchedule(expression)
+	if err != nil {
+		report.add("schedule", checkFailed, "invalid cron expression %q: %v", expression, err)
+		return
+	}
+
+	next := parsed.Next(time.Now())
+	if next.IsZero() {
+		report.add("schedule", checkFailed, "cron expression %q never runs", expression)
+		return
+	}
+	report.add("schedule", checkPassed, "next run at %s", next.UTC().Format(time.RFC3339))
+}
+
+func (h *VeleroHandler) checkScheduleTemplate(c *gin.Context, report *scheduleReport, template map[string]interface{}) {
+	if ttl, _, _ := unstructured.NestedString(template, "ttl"); ttl != "" {
+		if duration, err := time.ParseDuration(ttl); err != nil {
+			report.add("ttl", checkFailed, "invalid TTL %q: %v", ttl, err)
+		} else if duration <= 0 {
+			report.add("ttl", checkFailed, "TTL %q must be positive", ttl)
+		} else {
+			report.add("ttl", checkPassed, "backups expire after %s", duration)
+		}
+	} else {
+		report.add("ttl", checkPassed, "no TTL set, Velero's default applies")
+	}
+
+	h.checkStorageLocation(c, report, template)
+	h.checkNamespaces(c, report, template)
+
+	if err := validateResourceFilters(template); err != nil {
+		report.add("resourceFilters", checkFailed, "%v", err)
+	} else {
+		report.add("resourceFilters", checkPassed, "resource filters are consistent")
+	}
+
+	if orderedResources, found, _ := unstructured.NestedStringMap(template, "orderedResources"); found {
+		if err := validateOrderedResources(orderedResources); err != nil {
+			report.add("orderedResources", checkFailed, "%v", err)
+		} else {
+			report.add("orderedResources", checkPassed, "ordered resources are valid")
+		}
+	}
+}
+
+func (h *VeleroHandler) checkStorageLocation(c *gin.Context, report *scheduleReport, template map[string]interface{}) {
+	locationName, _, _ := unstructured.NestedString(template, "storageLocation")
+	if locationName == "" {
+		locationName = "default"
+	}
+
+	location, err := h.k8sClient.DynamicClient.
+		Resource(k8s.BackupStorageLocationGVR).
+		Namespace("velero").
+		Get(c.Request.Context(), locationName, metav1.GetOptions{})
+
+	if apierrors.IsNotFound(err) {
+		report.add("storageLocation", checkFailed, "storage location %q does not exist", locationName)
+		return
+	}
+	if err != nil {
+		report.add("storageLocation", checkFailed, "could not get storage location %q: %v", locationName, err)
+		return
+	}
+
+	phase, _, _ := unstructured.NestedString(location.Object, "status", "phase")
+	if phase == "" {
+		phase = "Unknown"
+	}
+	if phase != "Available" {
+		report.add("storageLocation", checkWarning, "storage location %q is %s", locationName, phase)
+		return
+	}
+	report.add("storageLocation", checkPassed, "storage location %q is available", locationName)
+}
+
+func (h *VeleroHandler) checkNamespaces(c *gin.Context, report *scheduleReport, template map[string]interface{}) {
+	included, _, _ := unstructured.NestedStringSlice(template, "includedNamespaces")
+
+	missing, err := h.missingNamespaces(c.Request.Context(), included)
+	if err != nil {
+		report.add("namespaces", checkFailed, "%v", err)
+		return
+	}
+
+	switch {
+	case len(missing) > 0:
+		report.add("namespaces", checkFailed, "included namespaces do not exist: %s", strings.Join(missing, ", "))
+	case len(included) == 0:
+		report.add("namespaces", checkPassed, "all namespaces are included")
+	default:
+		report.add("namespaces", checkPassed, "all %d included namespaces exist", len(included))
+	}
+}
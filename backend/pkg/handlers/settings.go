@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+	"velero-manager/pkg/config"
+	"velero-manager/pkg/k8s"
+	"velero-manager/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// redactedValue replaces secret values in settings responses
+const redactedValue = "[REDACTED]"
+
+// SettingsHandler exposes the effective configuration of the running instance
+type SettingsHandler struct{}
+
+// NewSettingsHandler creates a new settings handler
+func NewSettingsHandler() *SettingsHandler {
+	return &SettingsHandler{}
+}
+
+// GetSettings returns every resolved configuration value, with secrets redacted
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	// Copy, since a ConfigMap reload may replace it while we read
+	oidcConfig := *config.GetOIDCConfig()
+
+	c.JSON(http.StatusOK, gin.H{
+		"server":        settingsOf(config.GetServerConfig()),
+		"backup":        settingsOf(config.GetBackupConfig()),
+		"metrics":       settingsOf(config.GetMetricsConfig()),
+		"notifications": settingsOf(config.GetNotificationConfig()),
+		"oidc":          settingsOf(&oidcConfig),
+	})
+}
+
+// settingsOf lists the fields of a config struct by their JSON names, so new settings show
+// up without changes here. Fields tagged json:"-" are left out, fields tagged
+// secret:"true" are redacted and durations are shown the way they are configured.
+func settingsOf(cfg interface{}) map[string]interface{} {
+	value := reflect.Indirect(reflect.ValueOf(cfg))
+	settings := make(map[string]interface{}, value.NumField())
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		switch {
+		case field.Tag.Get("secret") == "true":
+			settings[name] = redact(value.Field(i).String())
+		case field.Type == durationType:
+			settings[name] = time.Duration(value.Field(i).Int()).String()
+		default:
+			settings[name] = value.Field(i).Interface()
+		}
+	}
+
+	return settings
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// GetConfig returns the effective non-secret configuration, for checking what a running
+// instance actually uses when env or ConfigMap drift is suspected
+func (h *SettingsHandler) GetConfig(c *gin.Context) {
+	serverConfig := config.GetServerConfig()
+	oidcConfig := config.GetOIDCConfig()
+
+	corsMode := "allow-all-origins"
+	if len(serverConfig.CORSAllowedOrigins) > 0 {
+		corsMode = "allow-list"
+	}
+
+	authMode := "legacy"
+	if oidcConfig.Enabled {
+		authMode = "oidc"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"veleroNamespace": k8s.VeleroNamespace,
+		"metrics": gin.H{
+			"collectionInterval": config.GetMetricsConfig().CollectionInterval.String(),
+		},
+		"auth": gin.H{
+			"mode":        authMode,
+			"oidcEnabled": oidcConfig.Enabled,
+			"oidcIssuer":  oidcConfig.IssuerURL,
+			"sessionTTL":  middleware.SessionTTL.String(),
+		},
+		"cors": gin.H{
+			"mode":           corsMode,
+			"allowedOrigins": serverConfig.CORSAllowedOrigins,
+		},
+	})
+}
+
+// redact hides a secret value while still showing whether it is set
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"velero-manager/pkg/config"
+)
+
+func TestSettingsOf(t *testing.T) {
+	settings := settingsOf(&struct {
+		Name     string        `json:"name"`
+		Timeout  time.Duration `json:"timeout,omitempty"`
+		Token    string        `json:"token" secret:"true"`
+		Unset    string        `json:"unset" secret:"true"`
+		Password string        `json:"-"`
+		Untagged bool
+		internal string
+	}{
+		Name:     "velero",
+		Timeout:  90 * time.Second,
+		Token:    "t0ken",
+		Password: "hunter2",
+		Untagged: true,
+		internal: "hidden",
+	})
+
+	want := map[string]interface{}{
+		"name":     "velero",
+		"timeout":  "1m30s",
+		"token":    redactedValue,
+		"unset":    "",
+		"Untagged": true,
+	}
+	if len(settings) != len(want) {
+		t.Errorf("settings = %v, want %v", settings, want)
+	}
+	for key, value := range want {
+		if settings[key] != value {
+			t.Errorf("%s = %v, want %v", key, settings[key], value)
+		}
+	}
+}
+
+func TestGetSettingsRedactsSecrets(t *testing.T) {
+	previousOIDC := config.GetOIDCConfig()
+	defer config.SetOIDCConfig(previousOIDC)
+	oidcConfig := *previousOIDC
+	oidcConfig.ClientID = "velero-manager"
+	oidcConfig.ClientSecret = "oidc-client-s3cret"
+	config.SetOIDCConfig(&oidcConfig)
+
+	notifications := config.GetNotificationConfig()
+	defer func(url string) { notifications.WebhookURL = url }(notifications.WebhookURL)
+	notifications.WebhookURL = "https://hooks.slack.com/services/T000/B000/webhook-s3cret"
+
+	server := config.GetServerConfig()
+	defer func(password string) { server.AdminInitialPassword = password }(server.AdminInitialPassword)
+	server.AdminInitialPassword = "admin-s3cret"
+
+	w := serve(NewSettingsHandler().GetSettings, http.MethodGet, "/api/v1/admin/settings", nil, nil, "admin")
+	assertStatus(t, w, http.StatusOK)
+
+	if strings.Contains(w.Body.String(), "s3cret") {
+		t.Fatalf("settings leak a secret:\n%s", w.Body.String())
+	}
+
+	body := decodeBody(t, w)
+	section := func(name string) map[string]interface{} {
+		t.Helper()
+		values, ok := body[name].(map[string]interface{})
+		if !ok {
+			t.Fatalf("no %s section in %v", name, body)
+		}
+		return values
+	}
+
+	oidc := section("oidc")
+	if oidc["client_secret"] != redactedValue || oidc["client_id"] != "velero-manager" {
+		t.Errorf("oidc client_secret = %v, client_id = %v", oidc["client_secret"], oidc["client_id"])
+	}
+	if got := section("notifications")["webhook_url"]; got != redactedValue {
+		t.Errorf("notifications webhook_url = %v, want %s", got, redactedValue)
+	}
+
+	serverSettings := section("server")
+	if _, ok := serverSettings["admin_initial_password"]; ok {
+		t.Error("server settings include the admin initial password")
+	}
+	// Every non-secret field is listed, including ones added after the endpoint
+	for _, key := range []string{"kubernetes_request_timeout", "cors_allowed_origins", "activity_flush_interval", "list_cache_ttl"} {
+		if _, ok := serverSettings[key]; !ok {
+			t.Errorf("server settings are missing %s", key)
+		}
+	}
+	if got := serverSettings["kubernetes_request_timeout"]; got != server.KubernetesRequestTimeout.String() {
+		t.Errorf("kubernetes_request_timeout = %v, want %s", got, server.KubernetesRequestTimeout)
+	}
+	if _, ok := section("backup")["unavailable_alert_after"]; !ok {
+		t.Error("backup settings are missing unavailable_alert_after")
+	}
+}
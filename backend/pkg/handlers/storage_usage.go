@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// allNamespaces is the bucket for backups that include every namespace, whose size
+// can't be attributed to any one of them
+const allNamespaces = "*"
+
+// storageUsageNote explains how sizes are attributed, returned with every usage report
+const storageUsageNote = "Approximate: a backup's size is split evenly between its included " +
+	"namespaces, and backups of all namespaces are reported under \"*\""
+
+// NamespaceUsage is the backup storage attributed to one namespace
+type NamespaceUsage struct {
+	Namespace   string `json:"namespace"`
+	TotalBytes  int64  `json:"totalBytes"`
+	BackupCount int    `json:"backupCount"`
+}
+
+// GetStorageUsage returns backup storage per namespace across all backups, for chargeback.
+// Velero only records the size of a whole backup, so it is split evenly between the
+// namespaces the backup includes.
+func (h *VeleroHandler) GetStorageUsage(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	backupList, err := h.k8sClient.ListCache.List(ctx, k8s.BackupGVR, "velero")
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeBackupListFailed, err)
+		return
+	}
+
+	usage := make(map[string]*NamespaceUsage)
+	var totalBytes int64
+
+	for _, backup := range backupList.Items {
+		size := backupSize(&backup)
+		totalBytes += size
+
+		namespaces, _, _ := unstructured.NestedStringSlice(backup.Object, "spec", "includedNamespaces")
+		namespaces = uniqueNamespaces(namespaces)
+
+		for i, namespace := range namespaces {
+			share := size / int64(len(namespaces))
+			// The first namespace takes the remainder so shares add up to the backup size
+			if i == 0 {
+				share += size % int64(len(namespaces))
+			}
+
+			entry, exists := usage[namespace]
+			if !exists {
+				entry = &NamespaceUsage{Namespace: namespace}
+				usage[namespace] = entry
+			}
+			entry.TotalBytes += share
+			entry.BackupCount++
+		}
+	}
+
+	result := make([]NamespaceUsage, 0, len(usage))
+	for _, entry := range usage {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalBytes != result[j].TotalBytes {
+			return result[i].TotalBytes > result[j].TotalBytes
+		}
+		return result[i].Namespace < result[j].Namespace
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"usage":       result,
+		"totalBytes":  totalBytes,
+		"backupCount": len(backupList.Items),
+		"note":        storageUsageNote,
+	})
+}
+
+// uniqueNamespaces deduplicates a backup's included namespaces. Empty lists and lists
+// containing "*" mean every namespace and collapse to allNamespaces.
+func uniqueNamespaces(namespaces []string) []string {
+	if len(namespaces) == 0 {
+		return []string{allNamespaces}
+	}
+
+	seen := make(map[string]bool, len(namespaces))
+	unique := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		if namespace == allNamespaces {
+			return []string{allNamespaces}
+		}
+		if !seen[namespace] {
+			seen[namespace] = true
+			unique = append(unique, namespace)
+		}
+	}
+	return unique
+}
+
+// backupSize returns status.backupSizeBytes, or 0 if Velero hasn't recorded it
+func backupSize(backup *unstructured.Unstructured) int64 {
+	value, found, _ := unstructured.NestedFieldNoCopy(backup.Object, "status", "backupSizeBytes")
+	if !found {
+		return 0
+	}
+
+	switch size := value.(type) {
+	case int64:
+		return size
+	case float64:
+		return int64(size)
+	}
+	return 0
+}
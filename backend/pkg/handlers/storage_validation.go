@@ -0,0 +1,411 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+	"velero-manager/pkg/config"
+	"velero-manager/pkg/k8s"
+	"velero-manager/pkg/notify"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	revalidateRequestedAnnotation = "velero-manager.io/revalidate-requested-at"
+
+	// pausedForLocationAnnotation marks schedules paused by velero-manager, so only those
+	// are resumed and schedules paused by hand are left alone
+	pausedForLocationAnnotation = "velero-manager.io/paused-for-location"
+)
+
+// StorageLocationValidation tracks re-validation attempts for a single backup storage location
+type StorageLocationValidation struct {
+	Name             string     `json:"name"`
+	Phase            string     `json:"phase"`
+	Attempts         int        `json:"attempts"`
+	UnavailableSince *time.Time `json:"unavailableSince,omitempty"`
+	LastAttempt      *time.Time `json:"lastAttempt,omitempty"`
+	RecoveredAt      *time.Time `json:"recoveredAt,omitempty"`
+	GaveUp           bool       `json:"gaveUp"`
+	Alerted          bool       `json:"alerted"`
+	PausedSchedules  []string   `json:"pausedSchedules,omitempty"`
+}
+
+// StorageLocationReconciler periodically asks Velero to re-validate Unavailable storage
+// locations so fixed credentials are picked up without waiting for Velero's own cycle.
+// It notifies when a location stays Unavailable past alertAfter and when it recovers.
+// When enabled it also pauses schedules that target an Unavailable location.
+type StorageLocationReconciler struct {
+	k8sClient   *k8s.Client
+	interval    time.Duration
+	maxAttempts int
+	alertAfter  time.Duration
+	enabled     bool
+	autoPause   bool
+	notifier    notify.Notifier
+	locations   map[string]*StorageLocationValidation
+	mutex       sync.RWMutex
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+// NewStorageLocationReconciler creates a reconciler using the configured backup settings
+func NewStorageLocationReconciler(k8sClient *k8s.Client) *StorageLocationReconciler {
+	cfg := config.GetBackupConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &StorageLocationReconciler{
+		k8sClient:   k8sClient,
+		interval:    cfg.RevalidationInterval,
+		maxAttempts: cfg.RevalidationMaxAttempts,
+		alertAfter:  cfg.UnavailableAlertAfter,
+		enabled:     cfg.RevalidationEnabled,
+		autoPause:   cfg.AutoPauseSchedules,
+		notifier:    notify.FromConfig(),
+		locations:   make(map[string]*StorageLocationValidation),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Start runs the reconcile loop until Stop is called
+func (r *StorageLocationReconciler) Start() {
+	if !r.enabled && !r.autoPause {
+		log.Println("Storage location re-validation disabled")
+		return
+	}
+
+	if r.enabled {
+		log.Printf("🔁 Starting storage location re-validation (every %s, max %d attempts, alert after %s)",
+			r.interval, r.maxAttempts, r.alertAfter)
+	}
+	if r.autoPause {
+		log.Printf("⏸️  Pausing schedules for Unavailable storage locations (every %s)", r.interval)
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.reconcile(); err != nil {
+				log.Printf("⚠️  Storage location re-validation failed: %v", err)
+			}
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// Stop stops the reconcile loop
+func (r *StorageLocationReconciler) Stop() {
+	r.cancel()
+}
+
+func (r *StorageLocationReconciler) reconcile() error {
+	locationList, err := r.k8sClient.DynamicClient.
+		Resource(k8s.BackupStorageLocationGVR).
+		Namespace("velero").
+		List(r.k8sClient.Context, metav1.ListOptions{})
+
+	if err != nil {
+		return err
+	}
+
+	phases := make(map[string]string, len(locationList.Items))
+	defaultLocation := "default"
+	for _, location := range locationList.Items {
+		phase, _, _ := unstructured.NestedString(location.Object, "status", "phase")
+		phases[location.GetName()] = phase
+
+		if isDefault, _, _ := unstructured.NestedBool(location.Object, "spec", "default"); isDefault {
+			defaultLocation = location.GetName()
+		}
+	}
+
+	revalidate, events := r.observe(phases, time.Now())
+	r.notify(events)
+	if r.enabled {
+		for _, name := range revalidate {
+			if err := annotateStorageLocation(r.k8sClient, name, revalidateRequestedAnnotation); err != nil {
+				log.Printf("⚠️  Failed to request re-validation of storage location %s: %v", name, err)
+			}
+		}
+	}
+
+	if r.autoPause {
+		return r.reconcileSchedules(phases, defaultLocation)
+	}
+
+	return nil
+}
+
+// reconcileSchedules pauses schedules whose storage location is Unavailable and resumes
+// the ones it paused once their location is back
+func (r *StorageLocationReconciler) reconcileSchedules(phases map[string]string, defaultLocation string) error {
+	scheduleList, err := r.k8sClient.DynamicClient.
+		Resource(k8s.ScheduleGVR).
+		Namespace("velero").
+		List(r.k8sClient.Context, metav1.ListOptions{})
+
+	if err != nil {
+		return err
+	}
+
+	paused := make(map[string][]string)
+	// Transitions made by this pass, to notify about
+	pausedNow := make(map[string][]string)
+	resumedNow := make(map[string][]string)
+	for i := range scheduleList.Items {
+		schedule := &scheduleList.Items[i]
+
+		location, _, _ := unstructured.NestedString(schedule.Object, "spec", "template", "storageLocation")
+		if location == "" {
+			location = defaultLocation
+		}
+
+		isPaused, _, _ := unstructured.NestedBool(schedule.Object, "spec", "paused")
+		pausedFor, pausedByUs := schedule.GetAnnotations()[pausedForLocationAnnotation]
+		unavailable := phases[location] == "Unavailable"
+
+		switch {
+		case unavailable && !isPaused:
+			if err := r.setSchedulePaused(schedule, true, location); err != nil {
+				log.Printf("⚠️  Failed to pause schedule %s: %v", schedule.GetName(), err)
+				continue
+			}
+			log.Printf("⏸️  Paused schedule %s: storage location %s is Unavailable", schedule.GetName(), location)
+			paused[location] = append(paused[location], schedule.GetName())
+			pausedNow[location] = append(pausedNow[location], schedule.GetName())
+
+		case unavailable && pausedByUs:
+			paused[location] = append(paused[location], schedule.GetName())
+
+		case !unavailable && pausedByUs:
+			if err := r.setSchedulePaused(schedule, false, ""); err != nil {
+				log.Printf("⚠️  Failed to resume schedule %s: %v", schedule.GetName(), err)
+				paused[pausedFor] = append(paused[pausedFor], schedule.GetName())
+				continue
+			}
+			log.Printf("▶️  Resumed schedule %s: storage location %s recovered", schedule.GetName(), pausedFor)
+			resumedNow[pausedFor] = append(resumedNow[pausedFor], schedule.GetName())
+		}
+	}
+
+	r.mutex.Lock()
+	for name, state := range r.locations {
+		state.PausedSchedules = paused[name]
+		sort.Strings(state.PausedSchedules)
+	}
+	r.mutex.Unlock()
+
+	events := scheduleEvents(notify.SchedulesPausedEvent, pausedNow, phases)
+	r.notify(append(events, scheduleEvents(notify.SchedulesResumedEvent, resumedNow, phases)...))
+
+	return nil
+}
+
+// scheduleEvents builds one event per location for the schedules paused or resumed for it
+func scheduleEvents(event string, schedules map[string][]string, phases map[string]string) []notify.StorageLocationEvent {
+	locations := make([]string, 0, len(schedules))
+	for location := range schedules {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+
+	events := make([]notify.StorageLocationEvent, 0, len(locations))
+	for _, location := range locations {
+		names := schedules[location]
+		sort.Strings(names)
+		events = append(events, notify.StorageLocationEvent{
+			Event:     event,
+			Location:  location,
+			Phase:     phases[location],
+			Schedules: names,
+		})
+	}
+	return events
+}
+
+// setSchedulePaused pauses or resumes a schedule, recording the location it was paused for
+func (r *StorageLocationReconciler) setSchedulePaused(schedule *unstructured.Unstructured, paused bool, location string) error {
+	annotations := schedule.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if paused {
+		annotations[pausedForLocationAnnotation] = location
+	} else {
+		delete(annotations, pausedForLocationAnnotation)
+	}
+	schedule.SetAnnotations(annotations)
+
+	if err := unstructured.SetNestedField(schedule.Object, paused, "spec", "paused"); err != nil {
+		return err
+	}
+
+	_, err := r.k8sClient.DynamicClient.
+		Resource(k8s.ScheduleGVR).
+		Namespace("velero").
+		Update(r.k8sClient.Context, schedule, metav1.UpdateOptions{})
+	r.k8sClient.ListCache.Invalidate(k8s.ScheduleGVR)
+
+	return err
+}
+
+// observe records the latest phase of every location and returns the locations that
+// should be re-validated now, along with the recoveries and locations Unavailable past
+// alertAfter to notify about. Both are also logged here, as are exhausted retries.
+func (r *StorageLocationReconciler) observe(phases map[string]string, now time.Time) ([]string, []notify.StorageLocationEvent) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Forget locations that no longer exist
+	for name := range r.locations {
+		if _, exists := phases[name]; !exists {
+			delete(r.locations, name)
+		}
+	}
+
+	var revalidate []string
+	var events []notify.StorageLocationEvent
+	for name, phase := range phases {
+		state, tracked := r.locations[name]
+		if !tracked {
+			state = &StorageLocationValidation{Name: name}
+			r.locations[name] = state
+		}
+		state.Phase = phase
+
+		if phase != "Unavailable" {
+			if state.UnavailableSince != nil {
+				recoveredAt := now
+				state.RecoveredAt = &recoveredAt
+				log.Printf("✅ Storage location %s recovered after %d re-validation attempt(s)", name, state.Attempts)
+				events = append(events, notify.StorageLocationEvent{
+					Event:            notify.StorageLocationRecoveredEvent,
+					Location:         name,
+					Phase:            phase,
+					UnavailableSince: state.UnavailableSince,
+					RecoveredAt:      &recoveredAt,
+					Attempts:         state.Attempts,
+				})
+			}
+			state.UnavailableSince = nil
+			state.Attempts = 0
+			state.GaveUp = false
+			state.Alerted = false
+			continue
+		}
+
+		if state.UnavailableSince == nil {
+			since := now
+			state.UnavailableSince = &since
+			state.RecoveredAt = nil
+		}
+
+		if !state.Alerted && now.Sub(*state.UnavailableSince) >= r.alertAfter {
+			state.Alerted = true
+			log.Printf("❌ Storage location %s has been Unavailable since %s",
+				name, state.UnavailableSince.Format(time.RFC3339))
+			events = append(events, notify.StorageLocationEvent{
+				Event:            notify.StorageLocationUnavailableEvent,
+				Location:         name,
+				Phase:            phase,
+				UnavailableSince: state.UnavailableSince,
+				Attempts:         state.Attempts,
+			})
+		}
+
+		if state.Attempts >= r.maxAttempts {
+			if !state.GaveUp {
+				state.GaveUp = true
+				log.Printf("⚠️  Giving up re-validating storage location %s after %d attempts",
+					name, state.Attempts)
+			}
+			continue
+		}
+
+		state.Attempts++
+		lastAttempt := now
+		state.LastAttempt = &lastAttempt
+		revalidate = append(revalidate, name)
+	}
+
+	sort.Strings(revalidate)
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Location < events[j].Location
+	})
+	return revalidate, events
+}
+
+// notify sends storage location events, one at a time so a slow webhook only delays the
+// next reconcile
+func (r *StorageLocationReconciler) notify(events []notify.StorageLocationEvent) {
+	if r.notifier == nil {
+		return
+	}
+	for _, event := range events {
+		if err := r.notifier.NotifyStorageLocation(r.ctx, event); err != nil {
+			log.Printf("⚠️  Failed to send %s notification for storage location %s: %v", event.Event, event.Location, err)
+		}
+	}
+}
+
+// GetStatus returns the re-validation state of every known storage location
+func (r *StorageLocationReconciler) GetStatus(c *gin.Context) {
+	r.mutex.RLock()
+	locations := make([]StorageLocationValidation, 0, len(r.locations))
+	for _, state := range r.locations {
+		locations = append(locations, *state)
+	}
+	r.mutex.RUnlock()
+
+	sort.Slice(locations, func(i, j int) bool {
+		return locations[i].Name < locations[j].Name
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":     r.enabled,
+		"autoPause":   r.autoPause,
+		"interval":    r.interval.String(),
+		"maxAttempts": r.maxAttempts,
+		"alertAfter":  r.alertAfter.String(),
+		"locations":   locations,
+		"count":       len(locations),
+	})
+}
+
+// annotateStorageLocation sets a timestamp annotation on a storage location, which makes
+// Velero reconcile (validate and sync) it right away
+func annotateStorageLocation(k8sClient *k8s.Client, name, annotation string) error {
+	location, err := k8sClient.DynamicClient.
+		Resource(k8s.BackupStorageLocationGVR).
+		Namespace("velero").
+		Get(k8sClient.Context, name, metav1.GetOptions{})
+
+	if err != nil {
+		return err
+	}
+
+	annotations := location.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[annotation] = time.Now().UTC().Format(time.RFC3339)
+	location.SetAnnotations(annotations)
+
+	_, err = k8sClient.DynamicClient.
+		Resource(k8s.BackupStorageLocationGVR).
+		Namespace("velero").
+		Update(k8sClient.Context, location, metav1.UpdateOptions{})
+
+	return err
+}
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"velero-manager/pkg/k8s"
+	"velero-manager/pkg/notify"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// recordingNotifier keeps every notification instead of sending it
+type recordingNotifier struct {
+	mutex            sync.Mutex
+	failures         []notify.BackupFailure
+	storageLocations []notify.StorageLocationEvent
+}
+
+func (n *recordingNotifier) NotifyBackupFailure(ctx context.Context, failure notify.BackupFailure) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.failures = append(n.failures, failure)
+	return nil
+}
+
+func (n *recordingNotifier) NotifyStorageLocation(ctx context.Context, event notify.StorageLocationEvent) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.storageLocations = append(n.storageLocations, event)
+	return nil
+}
+
+func newTestReconciler(client *k8s.Client, notifier notify.Notifier) *StorageLocationReconciler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StorageLocationReconciler{
+		k8sClient:   client,
+		interval:    time.Minute,
+		maxAttempts: 3,
+		alertAfter:  10 * time.Minute,
+		enabled:     true,
+		notifier:    notifier,
+		locations:   make(map[string]*StorageLocationValidation),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+func newTestStorageLocation(name, phase string) *unstructured.Unstructured {
+	return newUnstructured("velero.io/v1", "BackupStorageLocation", "velero", name, map[string]interface{}{
+		"status": map[string]interface{}{"phase": phase},
+	})
+}
+
+func eventNames(events []notify.StorageLocationEvent) []string {
+	names := make([]string, 0, len(events))
+	for _, event := range events {
+		names = append(names, event.Event+":"+event.Location)
+	}
+	return names
+}
+
+func TestObserveDetectsRecovery(t *testing.T) {
+	r := newTestReconciler(nil, nil)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if revalidate, events := r.observe(map[string]string{"default": "Available"}, start); len(revalidate) != 0 || len(events) != 0 {
+		t.Fatalf("available location: revalidate %v, events %v", revalidate, eventNames(events))
+	}
+
+	for i := 1; i <= 2; i++ {
+		revalidate, events := r.observe(map[string]string{"default": "Unavailable"}, start.Add(time.Duration(i)*time.Minute))
+		if len(revalidate) != 1 || revalidate[0] != "default" || len(events) != 0 {
+			t.Fatalf("attempt %d: revalidate %v, events %v", i, revalidate, eventNames(events))
+		}
+	}
+
+	recoveredAt := start.Add(3 * time.Minute)
+	revalidate, events := r.observe(map[string]string{"default": "Available"}, recoveredAt)
+	if len(revalidate) != 0 {
+		t.Errorf("recovered location re-validated: %v", revalidate)
+	}
+	if len(events) != 1 {
+		t.Fatalf("events = %v, want one recovery", eventNames(events))
+	}
+	event := events[0]
+	if event.Event != notify.StorageLocationRecoveredEvent || event.Location != "default" || event.Attempts != 2 ||
+		!event.UnavailableSince.Equal(start.Add(time.Minute)) || event.RecoveredAt == nil || !event.RecoveredAt.Equal(recoveredAt) {
+		t.Errorf("recovery event = %+v", event)
+	}
+
+	state := r.locations["default"]
+	if state.UnavailableSince != nil || state.Attempts != 0 || state.RecoveredAt == nil || !state.RecoveredAt.Equal(recoveredAt) {
+		t.Errorf("state after recovery = %+v", state)
+	}
+
+	// Staying Available is not another recovery
+	if _, events := r.observe(map[string]string{"default": "Available"}, recoveredAt.Add(time.Minute)); len(events) != 0 {
+		t.Errorf("events while still available = %v", eventNames(events))
+	}
+}
+
+func TestObserveAlertsOnceAfterThreshold(t *testing.T) {
+	r := newTestReconciler(nil, nil)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	unavailable := map[string]string{"default": "Unavailable"}
+
+	var alerts []notify.StorageLocationEvent
+	for _, offset := range []time.Duration{0, 5 * time.Minute, 9 * time.Minute, 10 * time.Minute, 20 * time.Minute, time.Hour} {
+		_, events := r.observe(unavailable, start.Add(offset))
+		if offset < r.alertAfter && len(events) != 0 {
+			t.Errorf("alert %s after becoming Unavailable, before the %s threshold", offset, r.alertAfter)
+		}
+		alerts = append(alerts, events...)
+	}
+
+	if len(alerts) != 1 || alerts[0].Event != notify.StorageLocationUnavailableEvent || !alerts[0].UnavailableSince.Equal(start) {
+		t.Fatalf("alerts = %+v, want one unavailable alert", alerts)
+	}
+
+	// Re-validation stops after maxAttempts, independently of the alert
+	if state := r.locations["default"]; !state.GaveUp || state.Attempts != r.maxAttempts || !state.Alerted {
+		t.Errorf("state = %+v, want gave up after %d attempts and alerted", state, r.maxAttempts)
+	}
+
+	// Recovery resets the alert, so a later outage alerts again
+	if _, events := r.observe(map[string]string{"default": "Available"}, start.Add(2*time.Hour)); len(events) != 1 ||
+		events[0].Event != notify.StorageLocationRecoveredEvent {
+		t.Fatalf("recovery events = %v", eventNames(events))
+	}
+	r.observe(unavailable, start.Add(3*time.Hour))
+	if _, events := r.observe(unavailable, start.Add(3*time.Hour+r.alertAfter)); len(events) != 1 ||
+		events[0].Event != notify.StorageLocationUnavailableEvent {
+		t.Errorf("second outage events = %v", eventNames(events))
+	}
+}
+
+func TestObserveForgetsRemovedLocations(t *testing.T) {
+	r := newTestReconciler(nil, nil)
+	now := time.Now()
+
+	r.observe(map[string]string{"default": "Unavailable", "secondary": "Unavailable"}, now)
+	r.observe(map[string]string{"secondary": "Unavailable"}, now.Add(time.Minute))
+
+	if _, tracked := r.locations["default"]; tracked {
+		t.Error("removed location is still tracked")
+	}
+	if state := r.locations["secondary"]; state == nil || state.Attempts != 2 {
+		t.Errorf("secondary = %+v, want 2 attempts", state)
+	}
+}
+
+func TestReconcileRevalidatesAndNotifiesRecovery(t *testing.T) {
+	client := newTestClient(newTestStorageLocation("default", "Unavailable"))
+	notifier := &recordingNotifier{}
+	r := newTestReconciler(client, notifier)
+	r.alertAfter = 0
+
+	if err := r.reconcile(); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	locations := client.DynamicClient.Resource(k8s.BackupStorageLocationGVR).Namespace("velero")
+	location, err := locations.Get(context.Background(), "default", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location.GetAnnotations()[revalidateRequestedAnnotation] == "" {
+		t.Error("Unavailable location was not annotated for re-validation")
+	}
+
+	// Velero validates the location again and it is fine now
+	if err := unstructured.SetNestedField(location.Object, "Available", "status", "phase"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := locations.Update(context.Background(), location, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.reconcile(); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+
+	got := eventNames(notifier.storageLocations)
+	want := []string{
+		notify.StorageLocationUnavailableEvent + ":default",
+		notify.StorageLocationRecoveredEvent + ":default",
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("notifications = %v, want %v", got, want)
+	}
+}
+
+func TestReconcilePausesAndResumesSchedules(t *testing.T) {
+	defaultLocation := newTestStorageLocation("default", "Unavailable")
+	if err := unstructured.SetNestedField(defaultLocation.Object, true, "spec", "default"); err != nil {
+		t.Fatal(err)
+	}
+	scheduleTo := func(name, location string, paused bool) *unstructured.Unstructured {
+		spec := map[string]interface{}{"schedule": "0 2 * * *", "paused": paused, "template": map[string]interface{}{}}
+		if location != "" {
+			spec["template"] = map[string]interface{}{"storageLocation": location}
+		}
+		return newUnstructured("velero.io/v1", "Schedule", "velero", name, map[string]interface{}{"spec": spec})
+	}
+
+	client := newTestClient(
+		defaultLocation,
+		newTestStorageLocation("secondary", "Available"),
+		scheduleTo("nightly", "", false),
+		scheduleTo("weekly", "default", false),
+		scheduleTo("offsite", "secondary", false),
+		scheduleTo("paused-by-hand", "default", true),
+	)
+	notifier := &recordingNotifier{}
+	r := newTestReconciler(client, notifier)
+	r.enabled = false
+	r.autoPause = true
+	r.alertAfter = time.Hour
+
+	schedules := client.DynamicClient.Resource(k8s.ScheduleGVR).Namespace("velero")
+	assertPaused := func(name string, wantPaused, wantAnnotated bool) {
+		t.Helper()
+		schedule, err := schedules.Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		paused, _, _ := unstructured.NestedBool(schedule.Object, "spec", "paused")
+		_, annotated := schedule.GetAnnotations()[pausedForLocationAnnotation]
+		if paused != wantPaused || annotated != wantAnnotated {
+			t.Errorf("%s: paused = %v, annotated = %v; want %v, %v", name, paused, annotated, wantPaused, wantAnnotated)
+		}
+	}
+
+	// default goes Unavailable: the schedules targeting it are paused
+	if err := r.reconcile(); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	assertPaused("nightly", true, true)
+	assertPaused("weekly", true, true)
+	assertPaused("offsite", false, false)
+	assertPaused("paused-by-hand", true, false)
+
+	if state := r.locations["default"]; state == nil || len(state.PausedSchedules) != 2 ||
+		state.PausedSchedules[0] != "nightly" || state.PausedSchedules[1] != "weekly" {
+		t.Errorf("default state = %+v, want nightly and weekly paused", state)
+	}
+	if len(notifier.storageLocations) != 1 {
+		t.Fatalf("notifications = %v, want one for the paused schedules", eventNames(notifier.storageLocations))
+	}
+	if event := notifier.storageLocations[0]; event.Event != notify.SchedulesPausedEvent || event.Location != "default" ||
+		len(event.Schedules) != 2 || event.Schedules[0] != "nightly" || event.Schedules[1] != "weekly" {
+		t.Errorf("pause notification = %+v", event)
+	}
+
+	// Still Unavailable: nothing changes and nothing is notified again
+	if err := r.reconcile(); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if len(notifier.storageLocations) != 1 {
+		t.Errorf("notifications while still Unavailable = %v", eventNames(notifier.storageLocations))
+	}
+
+	// default recovers: only the schedules velero-manager paused are resumed
+	locations := client.DynamicClient.Resource(k8s.BackupStorageLocationGVR).Namespace("velero")
+	location, err := locations.Get(context.Background(), "default", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := unstructured.SetNestedField(location.Object, "Available", "status", "phase"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := locations.Update(context.Background(), location, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.reconcile(); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	assertPaused("nightly", false, false)
+	assertPaused("weekly", false, false)
+	assertPaused("paused-by-hand", true, false)
+
+	if state := r.locations["default"]; len(state.PausedSchedules) != 0 {
+		t.Errorf("default still lists paused schedules %v", state.PausedSchedules)
+	}
+	got := eventNames(notifier.storageLocations[1:])
+	want := []string{notify.StorageLocationRecoveredEvent + ":default", notify.SchedulesResumedEvent + ":default"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("notifications after recovery = %v, want %v", got, want)
+	}
+}
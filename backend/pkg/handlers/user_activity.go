@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+	"velero-manager/pkg/config"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	userActivityConfigMapName = "velero-manager-user-activity"
+	userActivityKey           = "activity"
+)
+
+// UserActivity is when a user was last seen making an authenticated request, and how
+type UserActivity struct {
+	Username   string    `json:"username"`
+	LastSeen   time.Time `json:"lastSeen"`
+	AuthMethod string    `json:"authMethod"`
+	SourceIP   string    `json:"sourceIP"`
+}
+
+// UserActivityTracker records the last authenticated request of every user in memory
+// and periodically flushes it to a ConfigMap, merged with what other replicas wrote
+type UserActivityTracker struct {
+	k8sClient *k8s.Client
+	interval  time.Duration
+	activity  map[string]UserActivity
+	dirty     bool
+	mutex     sync.Mutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+// NewUserActivityTracker creates a tracker flushing at the configured interval
+func NewUserActivityTracker(k8sClient *k8s.Client) *UserActivityTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &UserActivityTracker{
+		k8sClient: k8sClient,
+		interval:  config.GetServerConfig().ActivityFlushInterval,
+		activity:  make(map[string]UserActivity),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// RecordActivity notes a request by username; it only touches memory
+func (t *UserActivityTracker) RecordActivity(username, authMethod, sourceIP string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.activity[username] = UserActivity{
+		Username:   username,
+		LastSeen:   time.Now().UTC(),
+		AuthMethod: authMethod,
+		SourceIP:   sourceIP,
+	}
+	t.dirty = true
+}
+
+// Start runs the flush loop until Stop is called
+func (t *UserActivityTracker) Start() {
+	log.Printf("👣 Recording user activity (flushed every %s)", t.interval)
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := t.flush(t.ctx); err != nil {
+				log.Printf("⚠️ Failed to save user activity: %v", err)
+			}
+		case <-t.ctx.Done():
+			return
+		}
+	}
+}
+
+// Stop ends the flush loop and saves activity recorded since the last flush
+func (t *UserActivityTracker) Stop() {
+	t.cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := t.flush(ctx); err != nil {
+		log.Printf("⚠️ Failed to save user activity on shutdown: %v", err)
+	}
+}
+
+// flush merges the recorded activity into the ConfigMap, keeping the latest entry per user
+func (t *UserActivityTracker) flush(ctx context.Context) error {
+	t.mutex.Lock()
+	if !t.dirty {
+		t.mutex.Unlock()
+		return nil
+	}
+	recorded := make(map[string]UserActivity, len(t.activity))
+	for username, activity := range t.activity {
+		recorded[username] = activity
+	}
+	t.dirty = false
+	t.mutex.Unlock()
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap, stored, err := t.load(ctx)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(mergeActivity(stored, recorded))
+		if err != nil {
+			return err
+		}
+
+		configMaps := t.k8sClient.Clientset.CoreV1().ConfigMaps(namespace)
+		if configMap == nil {
+			_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      userActivityConfigMapName,
+					Namespace: namespace,
+					Labels:    map[string]string{"app": "velero-manager"},
+				},
+				Data: map[string]string{userActivityKey: string(data)},
+			}, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// Another replica created it first; retry as an update
+				return apierrors.NewConflict(corev1.Resource("configmaps"), userActivityConfigMapName, err)
+			}
+			return err
+		}
+
+		configMap = configMap.DeepCopy()
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[userActivityKey] = string(data)
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+
+	if err != nil {
+		// Keep the activity so the next flush tries again
+		t.mutex.Lock()
+		t.dirty = true
+		t.mutex.Unlock()
+	}
+	return err
+}
+
+// load reads the stored activity; the ConfigMap is nil if it doesn't exist yet
+func (t *UserActivityTracker) load(ctx context.Context) (*corev1.ConfigMap, map[string]UserActivity, error) {
+	configMap, err := t.k8sClient.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, userActivityConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, map[string]UserActivity{}, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user activity ConfigMap: %v", err)
+	}
+
+	stored := map[string]UserActivity{}
+	if value := configMap.Data[userActivityKey]; value != "" {
+		if err := json.Unmarshal([]byte(value), &stored); err != nil {
+			log.Printf("⚠️ Ignoring unreadable user activity in ConfigMap: %v", err)
+			stored = map[string]UserActivity{}
+		}
+	}
+	return configMap, stored, nil
+}
+
+// mergeActivity combines two activity maps, keeping the most recent entry per user
+func mergeActivity(a, b map[string]UserActivity) map[string]UserActivity {
+	merged := make(map[string]UserActivity, len(a)+len(b))
+	for username, activity := range a {
+		merged[username] = activity
+	}
+	for username, activity := range b {
+		if existing, ok := merged[username]; !ok || activity.LastSeen.After(existing.LastSeen) {
+			merged[username] = activity
+		}
+	}
+	return merged
+}
+
+// ListUserActivity returns users by most recently seen, including activity saved by
+// other replicas. ?since=24h limits the list to users seen within that duration.
+func (t *UserActivityTracker) ListUserActivity(c *gin.Context) {
+	ctx, cancel := t.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	var cutoff time.Time
+	if value := c.Query("since"); value != "" {
+		since, err := time.ParseDuration(value)
+		if err != nil || since <= 0 {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidQuery, fmt.Errorf("since must be a positive duration such as 24h"))
+			return
+		}
+		cutoff = time.Now().Add(-since)
+	}
+
+	_, stored, err := t.load(ctx)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeUserActivityGetFailed, err)
+		return
+	}
+
+	t.mutex.Lock()
+	merged := mergeActivity(stored, t.activity)
+	t.mutex.Unlock()
+
+	users := make([]UserActivity, 0, len(merged))
+	for _, activity := range merged {
+		if activity.LastSeen.After(cutoff) {
+			users = append(users, activity)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].LastSeen.Equal(users[j].LastSeen) {
+			return users[i].LastSeen.After(users[j].LastSeen)
+		}
+		return users[i].Username < users[j].Username
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": users,
+		"count": len(users),
+	})
+}
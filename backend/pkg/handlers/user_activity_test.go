@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"velero-manager/pkg/k8s"
+	"velero-manager/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestUserActivityTracker(objects ...corev1.ConfigMap) *UserActivityTracker {
+	clientset := fake.NewSimpleClientset()
+	for i := range objects {
+		clientset.CoreV1().ConfigMaps(namespace).Create(context.Background(), &objects[i], metav1.CreateOptions{})
+	}
+	return NewUserActivityTracker(&k8s.Client{Clientset: clientset, Context: context.Background()})
+}
+
+// storedActivity reads the activity the tracker flushed to its ConfigMap
+func storedActivity(t *testing.T, tracker *UserActivityTracker) map[string]UserActivity {
+	t.Helper()
+	configMap, err := tracker.k8sClient.Clientset.CoreV1().ConfigMaps(namespace).
+		Get(context.Background(), userActivityConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get activity ConfigMap: %v", err)
+	}
+	stored := map[string]UserActivity{}
+	if err := json.Unmarshal([]byte(configMap.Data[userActivityKey]), &stored); err != nil {
+		t.Fatalf("unmarshal stored activity: %v", err)
+	}
+	return stored
+}
+
+func TestTrackActivityUpdatesLastSeen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tracker := newTestUserActivityTracker()
+
+	router := gin.New()
+	// Stands in for the auth middleware, which sets the caller on the context
+	router.Use(func(c *gin.Context) {
+		if username := c.GetHeader("X-Test-User"); username != "" {
+			c.Set("username", username)
+			c.Set("auth_method", "legacy")
+		}
+	})
+	router.Use(middleware.TrackActivity(tracker))
+	router.GET("/backups", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(username string) {
+		req := httptest.NewRequest(http.MethodGet, "/backups", nil)
+		req.RemoteAddr = "192.0.2.10:41234"
+		if username != "" {
+			req.Header.Set("X-Test-User", username)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET /backups: status %d", w.Code)
+		}
+	}
+
+	request("")
+	if len(tracker.activity) != 0 {
+		t.Fatalf("anonymous request recorded activity: %v", tracker.activity)
+	}
+
+	stale := time.Now().Add(-time.Hour).UTC()
+	tracker.activity["alice"] = UserActivity{Username: "alice", LastSeen: stale}
+
+	request("alice")
+	activity, ok := tracker.activity["alice"]
+	if !ok {
+		t.Fatal("no activity recorded for alice")
+	}
+	if !activity.LastSeen.After(stale) {
+		t.Errorf("LastSeen = %s, want after %s", activity.LastSeen, stale)
+	}
+	if activity.AuthMethod != "legacy" {
+		t.Errorf("AuthMethod = %q, want legacy", activity.AuthMethod)
+	}
+	if activity.SourceIP != "192.0.2.10" {
+		t.Errorf("SourceIP = %q, want 192.0.2.10", activity.SourceIP)
+	}
+	if !tracker.dirty {
+		t.Error("tracker not marked dirty after a request")
+	}
+}
+
+func TestUserActivityFlush(t *testing.T) {
+	tracker := newTestUserActivityTracker()
+
+	tracker.RecordActivity("alice", "oidc", "192.0.2.10")
+	if err := tracker.flush(context.Background()); err != nil {
+		t.Fatalf("first flush: %v", err)
+	}
+	if tracker.dirty {
+		t.Error("tracker still dirty after flush")
+	}
+
+	stored := storedActivity(t, tracker)
+	if got := stored["alice"]; got.AuthMethod != "oidc" || got.SourceIP != "192.0.2.10" || got.LastSeen.IsZero() {
+		t.Errorf("stored alice = %+v", got)
+	}
+
+	// Nothing new was recorded, so there is nothing to write
+	if err := tracker.flush(context.Background()); err != nil {
+		t.Fatalf("idle flush: %v", err)
+	}
+
+	tracker.RecordActivity("alice", "oidc", "192.0.2.11")
+	if err := tracker.flush(context.Background()); err != nil {
+		t.Fatalf("second flush: %v", err)
+	}
+	if got := storedActivity(t, tracker)["alice"]; got.SourceIP != "192.0.2.11" || got.LastSeen.Before(stored["alice"].LastSeen) {
+		t.Errorf("alice not updated by second flush: %+v", got)
+	}
+}
+
+func TestUserActivityFlushKeepsNewestAcrossReplicas(t *testing.T) {
+	now := time.Now().UTC()
+	other, err := json.Marshal(map[string]UserActivity{
+		"alice": {Username: "alice", LastSeen: now.Add(time.Hour), AuthMethod: "oidc", SourceIP: "198.51.100.1"},
+		"bob":   {Username: "bob", LastSeen: now.Add(-time.Hour), AuthMethod: "legacy", SourceIP: "198.51.100.2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker := newTestUserActivityTracker(corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: userActivityConfigMapName, Namespace: namespace},
+		Data:       map[string]string{userActivityKey: string(other)},
+	})
+
+	tracker.RecordActivity("alice", "legacy", "192.0.2.10")
+	tracker.RecordActivity("bob", "legacy", "192.0.2.20")
+	if err := tracker.flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	stored := storedActivity(t, tracker)
+	if got := stored["alice"].SourceIP; got != "198.51.100.1" {
+		t.Errorf("alice SourceIP = %q, want the other replica's newer entry", got)
+	}
+	if got := stored["bob"].SourceIP; got != "192.0.2.20" {
+		t.Errorf("bob SourceIP = %q, want this replica's newer entry", got)
+	}
+}
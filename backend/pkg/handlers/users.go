@@ -1,16 +1,29 @@
 package handlers
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"sort"
+	"sync"
+	"time"
+	"velero-manager/pkg/config"
 	"velero-manager/pkg/k8s"
 	"velero-manager/pkg/middleware"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
 )
 
 type User struct {
@@ -19,10 +32,19 @@ type User struct {
 	Hash     string `json:"hash"`
 	Role     string `json:"role"`
 	Created  string `json:"created"`
+	// MustChangePassword is set on accounts whose password someone else chose; every
+	// request but changing the password is rejected until it's cleared
+	MustChangePassword bool `json:"mustChangePassword,omitempty"`
+	// LastLogin is when the user last logged in (RFC 3339), empty if they never have
+	LastLogin string `json:"lastLogin,omitempty"`
 }
 
 type UserHandler struct {
 	k8sClient *k8s.Client
+
+	// migrated is set once the legacy users secret has been migrated
+	migrateMu sync.Mutex
+	migrated  bool
 }
 
 func NewUserHandler(k8sClient *k8s.Client) *UserHandler {
@@ -31,106 +53,359 @@ func NewUserHandler(k8sClient *k8s.Client) *UserHandler {
 	}
 }
 
+// usersSecretName is the legacy secret holding every user in one "users" key. It is
+// migrated to one secret per user on first use, since a single secret is capped at 1MB.
 const usersSecretName = "velero-manager-users"
 const usersNamespace = "velero-manager"
 
-func (h *UserHandler) getUsers() (map[string]User, error) {
+// userSecretPrefix names the per-user secrets. The suffix is a hash of the username,
+// which need not be a valid object name.
+const userSecretPrefix = "velero-manager-user-"
+
+// userLabels mark the per-user secrets so they can be listed
+var userLabels = map[string]string{"app": "velero-manager", "type": "user"}
+
+// bootstrapSecretName optionally holds the initial admin password under "password"
+const bootstrapSecretName = "velero-manager-bootstrap"
+
+// Errors returned from user mutations, mapped to responses by the handlers
+var (
+	errUserExists         = errors.New("user already exists")
+	errUserNotFound       = errors.New("user not found")
+	errInvalidOldPassword = errors.New("invalid old password")
+	errPasswordUnchanged  = errors.New("new password must differ from the current one")
+)
+
+// userSecretName returns the name of the secret holding a user
+func userSecretName(username string) string {
+	sum := sha256.Sum256([]byte(username))
+	return userSecretPrefix + hex.EncodeToString(sum[:])[:20]
+}
+
+// newUserSecret returns the secret holding a user
+func newUserSecret(user User) (*corev1.Secret, error) {
+	data, err := json.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      userSecretName(user.Username),
+			Namespace: usersNamespace,
+			Labels:    userLabels,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{"user": data},
+	}, nil
+}
+
+// userFromSecret parses the user held by a per-user secret
+func userFromSecret(secret *corev1.Secret) (User, error) {
+	var user User
+	if err := json.Unmarshal(secret.Data["user"], &user); err != nil {
+		return User{}, fmt.Errorf("failed to parse user secret %s: %w", secret.Name, err)
+	}
+	return user, nil
+}
+
+// readUser returns a user and the secret holding it, or errUserNotFound
+func (h *UserHandler) readUser(username string) (User, *corev1.Secret, error) {
+	if err := h.ensureMigrated(); err != nil {
+		return User{}, nil, err
+	}
+
 	secret, err := h.k8sClient.Clientset.CoreV1().Secrets(usersNamespace).Get(
-		h.k8sClient.Context, usersSecretName, metav1.GetOptions{})
+		h.k8sClient.Context, userSecretName(username), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return User{}, nil, errUserNotFound
+	}
+	if err != nil {
+		return User{}, nil, fmt.Errorf("failed to read user secret: %w", err)
+	}
 
+	user, err := userFromSecret(secret)
+	return user, secret, err
+}
+
+// getUser returns a user, creating the admin user on first use
+func (h *UserHandler) getUser(username string) (User, error) {
+	if username == "admin" {
+		return h.ensureAdmin()
+	}
+	user, _, err := h.readUser(username)
+	return user, err
+}
+
+// listUsers returns every user sorted by username, creating the admin user on first use
+func (h *UserHandler) listUsers() ([]User, error) {
+	if _, err := h.ensureAdmin(); err != nil {
+		return nil, err
+	}
+
+	secrets, err := h.k8sClient.Clientset.CoreV1().Secrets(usersNamespace).List(
+		h.k8sClient.Context, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(userLabels).String()})
 	if err != nil {
-		// Secret doesn't exist, create default admin
-		adminHash, _ := bcrypt.GenerateFromPassword([]byte("admin"), bcrypt.DefaultCost)
-		users := map[string]User{
-			"admin": {
-				Username: "admin",
-				Hash:     string(adminHash),
-				Role:     "admin",
-				Created:  "default",
-			},
+		return nil, fmt.Errorf("failed to list user secrets: %w", err)
+	}
+
+	users := make([]User, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		user, err := userFromSecret(&secrets.Items[i])
+		if err != nil {
+			log.Printf("⚠️ Skipping user: %v", err)
+			continue
 		}
-		// Save the default admin user
-		if saveErr := h.saveUsers(users); saveErr != nil {
-			// If save fails, still return the users for login to work
-			fmt.Printf("Warning: Failed to save default admin user: %v\n", saveErr)
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+	return users, nil
+}
+
+// ensureAdmin returns the admin user, creating it if it doesn't exist. It must be
+// persisted, or a generated password would change on every call.
+func (h *UserHandler) ensureAdmin() (User, error) {
+	admin, _, err := h.readUser("admin")
+	if !errors.Is(err, errUserNotFound) {
+		return admin, err
+	}
+
+	password, source, err := h.adminInitialPassword()
+	if err != nil {
+		return User{}, err
+	}
+	admin, err = bootstrapAdmin(password)
+	if err != nil {
+		return User{}, err
+	}
+	if err := h.insertUser(admin); errors.Is(err, errUserExists) {
+		// Another replica created it first, so this password was never used
+		admin, _, err = h.readUser("admin")
+		return admin, err
+	} else if err != nil {
+		return User{}, err
+	}
+
+	if source == "" {
+		log.Printf("🔑 Created admin user with generated password: %s", password)
+		log.Printf("   Set ADMIN_INITIAL_PASSWORD or the %s secret to choose it instead", bootstrapSecretName)
+	} else {
+		log.Printf("🔑 Created admin user with password from %s", source)
+	}
+	return admin, nil
+}
+
+// createUser saves a new user, or fails with errUserExists
+func (h *UserHandler) createUser(user User) error {
+	if err := h.ensureMigrated(); err != nil {
+		return err
+	}
+	return h.insertUser(user)
+}
+
+// insertUser creates the secret for a new user, or fails with errUserExists
+func (h *UserHandler) insertUser(user User) error {
+	secret, err := newUserSecret(user)
+	if err != nil {
+		return err
+	}
+	_, err = h.k8sClient.Clientset.CoreV1().Secrets(usersNamespace).Create(
+		h.k8sClient.Context, secret, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return errUserExists
+	}
+	return err
+}
+
+// updateUser applies mutate to a user and saves it. The save is conditional on the
+// secret not having changed since it was read, and conflicts are retried on fresh
+// data, so concurrent changes from any replica are never lost.
+func (h *UserHandler) updateUser(username string, mutate func(user *User) error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		user, secret, err := h.readUser(username)
+		if err != nil {
+			return err
+		}
+		if err := mutate(&user); err != nil {
+			return err
 		}
-		return users, nil
+
+		updated, err := newUserSecret(user)
+		if err != nil {
+			return err
+		}
+		// The resourceVersion carried over from the read makes the update conditional
+		secret = secret.DeepCopy()
+		secret.Data = updated.Data
+		_, err = h.k8sClient.Clientset.CoreV1().Secrets(usersNamespace).Update(
+			h.k8sClient.Context, secret, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// deleteUser deletes a user, or fails with errUserNotFound
+func (h *UserHandler) deleteUser(username string) error {
+	if err := h.ensureMigrated(); err != nil {
+		return err
+	}
+	err := h.k8sClient.Clientset.CoreV1().Secrets(usersNamespace).Delete(
+		h.k8sClient.Context, userSecretName(username), metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return errUserNotFound
+	}
+	return err
+}
+
+// ensureMigrated moves users out of the legacy single secret before the first user
+// operation, retrying on later operations until it succeeds
+func (h *UserHandler) ensureMigrated() error {
+	h.migrateMu.Lock()
+	defer h.migrateMu.Unlock()
+
+	if h.migrated {
+		return nil
+	}
+	if err := h.migrateLegacyUsers(); err != nil {
+		return fmt.Errorf("failed to migrate users: %w", err)
+	}
+	h.migrated = true
+	return nil
+}
+
+// migrateLegacyUsers copies every user from the legacy secret into its own secret, then
+// deletes the legacy secret. Users that already have their own secret are kept as they
+// are, so an interrupted migration can simply run again.
+func (h *UserHandler) migrateLegacyUsers() error {
+	secrets := h.k8sClient.Clientset.CoreV1().Secrets(usersNamespace)
+
+	legacy, err := secrets.Get(h.k8sClient.Context, usersSecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read users secret: %w", err)
 	}
 
 	users := make(map[string]User)
-	if data, ok := secret.Data["users"]; ok {
-		json.Unmarshal(data, &users)
-	}
-
-	// Always ensure admin exists
-	if _, ok := users["admin"]; !ok {
-		// Generate proper hash for "admin" password
-		adminHash, _ := bcrypt.GenerateFromPassword([]byte("admin"), bcrypt.DefaultCost)
-		users["admin"] = User{
-			Username: "admin",
-			Hash:     string(adminHash),
-			Role:     "admin",
-			Created:  "fallback",
+	if data, ok := legacy.Data["users"]; ok {
+		if err := json.Unmarshal(data, &users); err != nil {
+			return fmt.Errorf("failed to parse users secret: %w", err)
 		}
 	}
 
-	return users, nil
+	for username, user := range users {
+		user.Username = username
+		if err := h.insertUser(user); err != nil && !errors.Is(err, errUserExists) {
+			return fmt.Errorf("failed to migrate user %s: %w", username, err)
+		}
+	}
+
+	// Only delete the secret as it was migrated, so a write from a replica still on the
+	// old format is picked up by the next attempt instead of lost
+	err = secrets.Delete(h.k8sClient.Context, usersSecretName, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{ResourceVersion: &legacy.ResourceVersion},
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete users secret: %w", err)
+	}
+
+	log.Printf("✅ Migrated %d users from the %s secret to one secret per user", len(users), usersSecretName)
+	return nil
 }
 
-// GetUsers returns users as interface{} to satisfy middleware.UserValidator interface
-func (h *UserHandler) GetUsers() (map[string]interface{}, error) {
-	users, err := h.getUsers()
+// adminInitialPassword returns the initial admin password and where it came from:
+// ADMIN_INITIAL_PASSWORD, then the "password" key of the bootstrap secret. If neither is
+// set a random password is generated and source is empty.
+func (h *UserHandler) adminInitialPassword() (password, source string, err error) {
+	if password := config.GetServerConfig().AdminInitialPassword; password != "" {
+		return password, "ADMIN_INITIAL_PASSWORD", nil
+	}
+
+	secret, err := h.k8sClient.Clientset.CoreV1().Secrets(usersNamespace).Get(
+		h.k8sClient.Context, bootstrapSecretName, metav1.GetOptions{})
+	switch {
+	case err == nil && len(secret.Data["password"]) > 0:
+		return string(secret.Data["password"]), bootstrapSecretName + " secret", nil
+	case err != nil && !apierrors.IsNotFound(err):
+		return "", "", fmt.Errorf("failed to read bootstrap secret: %w", err)
+	}
+
+	password, err = generatePassword()
 	if err != nil {
-		return nil, err
+		return "", "", fmt.Errorf("failed to generate admin password: %w", err)
 	}
+	return password, "", nil
+}
 
-	result := make(map[string]interface{})
-	for k, v := range users {
-		result[k] = map[string]interface{}{
-			"username": v.Username,
-			"role":     v.Role,
-			"created":  v.Created,
-		}
+// bootstrapAdmin returns the initial admin user, who must change password on first login
+func bootstrapAdmin(password string) (User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return User{}, fmt.Errorf("failed to hash admin password: %w", err)
 	}
 
-	return result, nil
+	return User{
+		Username: "admin",
+		Hash:     string(hash),
+		Role:     "admin",
+		Created:  metav1.Now().Format("2006-01-02"),
+
+		MustChangePassword: true,
+	}, nil
 }
 
-func (h *UserHandler) saveUsers(users map[string]User) error {
-	data, _ := json.Marshal(users)
+// generatePassword returns a random URL-safe password
+func generatePassword() (string, error) {
+	buf := make([]byte, 18)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
 
-	secret := map[string]interface{}{
-		"apiVersion": "v1",
-		"kind":       "Secret",
-		"metadata": map[string]interface{}{
-			"name":      usersSecretName,
-			"namespace": usersNamespace,
-		},
-		"type": "Opaque",
-		"stringData": map[string]interface{}{
-			"users": string(data),
-		},
+// recordLogin stores the time of a successful login. Only users velero-manager manages
+// are tracked, and a failure is logged rather than failing the login.
+func (h *UserHandler) recordLogin(username string) {
+	err := h.updateUser(username, func(user *User) error {
+		user.LastLogin = time.Now().UTC().Format(time.RFC3339)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errUserNotFound) {
+		log.Printf("⚠️ Failed to record login of %s: %v", username, err)
 	}
+}
 
-	existing, err := h.k8sClient.Clientset.CoreV1().Secrets(usersNamespace).Get(
-		h.k8sClient.Context, usersSecretName, metav1.GetOptions{})
+// userDetails returns the fields of a user that are safe to show
+func userDetails(user User) gin.H {
+	return gin.H{
+		"username":  user.Username,
+		"role":      user.Role,
+		"created":   user.Created,
+		"lastLogin": user.LastLogin,
 
+		"mustChangePassword": user.MustChangePassword,
+	}
+}
+
+// GetUser returns a user as a map to satisfy the middleware.UserValidator interface, or
+// nil if velero-manager doesn't manage the user
+func (h *UserHandler) GetUser(username string) (map[string]interface{}, error) {
+	user, err := h.getUser(username)
+	if errors.Is(err, errUserNotFound) {
+		return nil, nil
+	}
 	if err != nil {
-		// Create new secret
-		_, err = h.k8sClient.DynamicClient.
-			Resource(k8s.SecretGVR).
-			Namespace(usersNamespace).
-			Create(h.k8sClient.Context, &unstructured.Unstructured{Object: secret}, metav1.CreateOptions{})
-	} else {
-		// Update existing
-		existing.Data = map[string][]byte{
-			"users": data,
-		}
-		_, err = h.k8sClient.Clientset.CoreV1().Secrets(usersNamespace).Update(
-			h.k8sClient.Context, existing, metav1.UpdateOptions{})
+		return nil, err
 	}
 
-	return err
+	return map[string]interface{}{
+		"username": user.Username,
+		"role":     user.Role,
+		"created":  user.Created,
+
+		"mustChangePassword": user.MustChangePassword,
+	}, nil
 }
 
 func (h *UserHandler) Login(c *gin.Context) {
@@ -144,22 +419,26 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	users, _ := h.getUsers()
-	user, exists := users[request.Username]
-
-	if !exists {
+	user, err := h.getUser(request.Username)
+	if errors.Is(err, errUserNotFound) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get users"})
+		return
+	}
 
-	err := bcrypt.CompareHashAndPassword([]byte(user.Hash), []byte(request.Password))
+	err = bcrypt.CompareHashAndPassword([]byte(user.Hash), []byte(request.Password))
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	// Create JWT token
-	jwtToken, err := middleware.CreateJWTToken(user.Username, user.Role)
+	h.recordLogin(user.Username)
+
+	// Create JWT token; a user who must change their password can do only that with it
+	jwtToken, err := middleware.CreateUserJWTToken(user.Username, user.Role, user.MustChangePassword)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create authentication token"})
 		return
@@ -167,7 +446,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 	// Also create session token as fallback
 	sessionToken := fmt.Sprintf("session_%s_%d", user.Username, metav1.Now().Unix())
-	middleware.StoreSession(user.Username, user.Role, sessionToken)
+	middleware.StoreUserSession(user.Username, user.Role, sessionToken, user.MustChangePassword)
 
 	c.JSON(http.StatusOK, gin.H{
 		"username":     user.Username,
@@ -175,26 +454,52 @@ func (h *UserHandler) Login(c *gin.Context) {
 		"token":        jwtToken,
 		"sessionToken": sessionToken,
 		"tokenType":    "Bearer",
+
+		"mustChangePassword": user.MustChangePassword,
 	})
 }
 
+// ListUsers returns users sorted by username, paginated with ?limit=&offset=
 func (h *UserHandler) ListUsers(c *gin.Context) {
-	users, err := h.getUsers()
+	page, err := parsePageQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	users, err := h.listUsers()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get users"})
 		return
 	}
 
+	start, end := page.bounds(len(users))
 	userList := []gin.H{}
-	for _, user := range users {
-		userList = append(userList, gin.H{
-			"username": user.Username,
-			"role":     user.Role,
-			"created":  user.Created,
-		})
+	for _, user := range users[start:end] {
+		userList = append(userList, userDetails(user))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":  userList,
+		"total":  len(users),
+		"limit":  page.limit,
+		"offset": page.offset,
+	})
+}
+
+// GetUserDetails returns one user, including when they last logged in
+func (h *UserHandler) GetUserDetails(c *gin.Context) {
+	user, err := h.getUser(c.Param("username"))
+	if errors.Is(err, errUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get user"})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"users": userList})
+	c.JSON(http.StatusOK, userDetails(user))
 }
 
 func (h *UserHandler) CreateUser(c *gin.Context) {
@@ -220,23 +525,21 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		request.Role = "user"
 	}
 
-	users, _ := h.getUsers()
-
-	if _, exists := users[request.Username]; exists {
-		c.JSON(http.StatusConflict, gin.H{"error": "User already exists"})
-		return
-	}
-
 	hash, _ := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 
-	users[request.Username] = User{
+	err := h.createUser(User{
 		Username: request.Username,
 		Hash:     string(hash),
 		Role:     request.Role,
 		Created:  metav1.Now().Format("2006-01-02"),
-	}
 
-	if err := h.saveUsers(users); err != nil {
+		MustChangePassword: true,
+	})
+	if errors.Is(err, errUserExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": "User already exists"})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save user"})
 		return
 	}
@@ -255,16 +558,12 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	users, _ := h.getUsers()
-
-	if _, exists := users[username]; !exists {
+	err := h.deleteUser(username)
+	if errors.Is(err, errUserNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
-
-	delete(users, username)
-
-	if err := h.saveUsers(users); err != nil {
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
 		return
 	}
@@ -272,6 +571,20 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "User deleted"})
 }
 
+// RevokeUserSessions logs a user out everywhere by revoking all their active sessions.
+// OIDC users have no stored user, so the username isn't checked against the user store
+func (h *UserHandler) RevokeUserSessions(c *gin.Context) {
+	username := c.Param("username")
+
+	revoked := middleware.RevokeUserSessions(username)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Sessions revoked",
+		"username": username,
+		"revoked":  revoked,
+	})
+}
+
 func (h *UserHandler) ChangePassword(c *gin.Context) {
 	username := c.Param("username")
 
@@ -295,32 +608,57 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	users, _ := h.getUsers()
-	user, exists := users[username]
+	hash, _ := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+
+	err := h.updateUser(username, func(user *User) error {
+		// For non-admin users changing their own password, verify old password
+		// TODO: Add proper auth context to check current user
+		if request.OldPassword != "" {
+			if err := bcrypt.CompareHashAndPassword([]byte(user.Hash), []byte(request.OldPassword)); err != nil {
+				return errInvalidOldPassword
+			}
+		}
+
+		if user.MustChangePassword && request.NewPassword == request.OldPassword {
+			return errPasswordUnchanged
+		}
 
-	if !exists {
+		user.Hash = string(hash)
+		user.MustChangePassword = false
+		return nil
+	})
+	switch {
+	case errors.Is(err, errUserNotFound):
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
+	case errors.Is(err, errInvalidOldPassword):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid old password"})
+		return
+	case errors.Is(err, errPasswordUnchanged):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "New password must differ from the current one"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
+		return
 	}
 
-	// For non-admin users changing their own password, verify old password
-	// TODO: Add proper auth context to check current user
-	if request.OldPassword != "" {
-		err := bcrypt.CompareHashAndPassword([]byte(user.Hash), []byte(request.OldPassword))
+	response := gin.H{"message": "Password updated"}
+
+	// The caller's token may still require a password change, so issue a fresh one
+	if username == c.GetString("username") {
+		role := c.GetString("role")
+		jwtToken, err := middleware.CreateUserJWTToken(username, role, false)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid old password"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create authentication token"})
 			return
 		}
-	}
-
-	hash, _ := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
-	user.Hash = string(hash)
-	users[username] = user
+		sessionToken := fmt.Sprintf("session_%s_%d", username, metav1.Now().Unix())
+		middleware.StoreSession(username, role, sessionToken)
 
-	if err := h.saveUsers(users); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update password"})
-		return
+		response["token"] = jwtToken
+		response["sessionToken"] = sessionToken
+		response["tokenType"] = "Bearer"
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Password updated"})
+	c.JSON(http.StatusOK, response)
 }
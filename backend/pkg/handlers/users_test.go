@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"velero-manager/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newTestUser returns a user with the given password, hashed at the lowest cost
+func newTestUser(t *testing.T, username, role, password string) User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return User{Username: username, Hash: string(hash), Role: role, Created: "2026-01-01"}
+}
+
+// tokenClaims validates the token in a login or password change response
+func tokenClaims(t *testing.T, w *httptest.ResponseRecorder) *middleware.Claims {
+	t.Helper()
+	token, _ := decodeBody(t, w)["token"].(string)
+	claims, err := middleware.ValidateJWTToken(token)
+	if err != nil {
+		t.Fatalf("response token: %v\n%s", err, w.Body.String())
+	}
+	return claims
+}
+
+func TestPasswordChangeReissuesToken(t *testing.T) {
+	handler := NewUserHandler(newTestClient())
+	alice := newTestUser(t, "alice", "user", "initial-password")
+	alice.MustChangePassword = true
+	if err := handler.createUser(alice); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	w := serve(handler.Login, http.MethodPost, "/api/v1/auth/login",
+		map[string]string{"username": "alice", "password": "initial-password"}, nil, "")
+	assertStatus(t, w, http.StatusOK)
+	if claims := tokenClaims(t, w); !claims.MustChangePassword {
+		t.Errorf("login token claims = %+v, want the password change requirement", claims)
+	}
+
+	body, _ := json.Marshal(map[string]string{"oldPassword": "initial-password", "newPassword": "chosen-password"})
+	w = httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/users/alice/password", bytes.NewReader(body))
+	c.Params = gin.Params{{Key: "username", Value: "alice"}}
+	c.Set("username", "alice")
+	c.Set("role", "user")
+	handler.ChangePassword(c)
+
+	assertStatus(t, w, http.StatusOK)
+	if claims := tokenClaims(t, w); claims.MustChangePassword || claims.Username != "alice" || claims.Role != "user" {
+		t.Errorf("reissued token claims = %+v, want alice as user without the requirement", claims)
+	}
+
+	w = serve(handler.Login, http.MethodPost, "/api/v1/auth/login",
+		map[string]string{"username": "alice", "password": "chosen-password"}, nil, "")
+	assertStatus(t, w, http.StatusOK)
+	if claims := tokenClaims(t, w); claims.MustChangePassword {
+		t.Error("login token still requires a password change after it was changed")
+	}
+}
+
+func TestConcurrentUserWrites(t *testing.T) {
+	handler := NewUserHandler(newTestClient())
+	usernames := []string{"alice", "bob"}
+
+	run := func(fn func(username string) error) {
+		var wg sync.WaitGroup
+		errs := make([]error, len(usernames))
+		for i, username := range usernames {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = fn(username)
+			}()
+		}
+		wg.Wait()
+		for i, err := range errs {
+			if err != nil {
+				t.Fatalf("%s: %v", usernames[i], err)
+			}
+		}
+	}
+
+	users := map[string]User{}
+	for _, username := range usernames {
+		users[username] = newTestUser(t, username, "user", "password-"+username)
+	}
+	run(func(username string) error {
+		return handler.createUser(users[username])
+	})
+	run(func(username string) error {
+		return handler.updateUser(username, func(user *User) error {
+			user.Role = "admin"
+			return nil
+		})
+	})
+
+	secrets := handler.k8sClient.Clientset.CoreV1().Secrets(usersNamespace)
+	for _, username := range usernames {
+		if _, err := secrets.Get(context.Background(), userSecretName(username), metav1.GetOptions{}); err != nil {
+			t.Errorf("%s: secret: %v", username, err)
+		}
+		user, err := handler.getUser(username)
+		if err != nil {
+			t.Errorf("%s: %v", username, err)
+			continue
+		}
+		if user.Role != "admin" {
+			t.Errorf("%s: role = %q, want the concurrent update to admin", username, user.Role)
+		}
+	}
+}
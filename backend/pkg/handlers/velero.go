@@ -1,23 +1,40 @@
 package handlers
 
 import (
+	"compress/gzip"
+	"context"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
+	"velero-manager/pkg/config"
 	"velero-manager/pkg/k8s"
 	"velero-manager/pkg/metrics"
 
 	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 type VeleroHandler struct {
 	k8sClient           *k8s.Client
 	metrics             *metrics.VeleroMetrics
+	events              *eventHub
+	restoreChains       *restoreChainStore
 	clusterDescriptions map[string]string
 	mutex               sync.RWMutex
 }
@@ -26,108 +43,151 @@ func NewVeleroHandler(k8sClient *k8s.Client, veleroMetrics *metrics.VeleroMetric
 	return &VeleroHandler{
 		k8sClient:           k8sClient,
 		metrics:             veleroMetrics,
+		events:              newEventHub(k8sClient),
+		restoreChains:       newRestoreChainStore(),
 		clusterDescriptions: make(map[string]string),
 	}
 }
 
+// Stop ends the background watches behind the event stream
+func (h *VeleroHandler) Stop() {
+	h.events.stop()
+}
+
 func (h *VeleroHandler) ListBackups(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	selector, err := parseLabelSelector(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidQuery, err)
+		return
+	}
+
 	// Check if Velero CRDs exist first
-	_, err := h.k8sClient.Clientset.Discovery().ServerResourcesForGroupVersion("velero.io/v1")
+	_, err = h.k8sClient.Clientset.Discovery().ServerResourcesForGroupVersion("velero.io/v1")
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error":   "Velero not installed or CRDs not found",
-			"details": err.Error(),
-			"help":    "Install Velero: https://velero.io/docs/v1.12/basic-install/",
-		})
+		respondError(c, http.StatusServiceUnavailable, ErrCodeVeleroNotInstalled, err)
 		return
 	}
 
 	// Get backups from Velero namespace
-	backupList, err := h.k8sClient.DynamicClient.
-		Resource(k8s.BackupGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
+	backupList, err := h.k8sClient.ListCache.ListSelected(ctx, k8s.BackupGVR, "velero", selector)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":     "Failed to list backups",
-			"details":   err.Error(),
-			"namespace": "velero",
-		})
+		respondError(c, http.StatusInternalServerError, ErrCodeBackupListFailed, err)
 		return
 	}
 
-	// Convert to simpler format
-	var backups []map[string]interface{}
+	backups := make([]VeleroResource, 0, len(backupList.Items))
 	for _, backup := range backupList.Items {
-		backupName := backup.GetName()
-		clusterName := extractClusterFromBackupName(backupName)
+		backups = append(backups, backupListEntry(backup))
+	}
 
-		backupData := map[string]interface{}{
-			"name":              backupName,
-			"cluster":           clusterName,
-			"namespace":         backup.GetNamespace(),
-			"creationTimestamp": backup.GetCreationTimestamp(),
-			"labels":            backup.GetLabels(),
-		}
+	response := BackupList{Backups: backups, Count: len(backups)}
+	respondJSONWithETag(c, response, response)
+}
 
-		// Extract status if available
-		if status, found := backup.Object["status"]; found {
-			backupData["status"] = status
-		}
+// backupListEntry converts a backup into the entry returned by backup lists
+func backupListEntry(backup unstructured.Unstructured) VeleroResource {
+	return newVeleroResource(backup, k8s.BackupCluster(&backup))
+}
 
-		// Extract spec if available
-		if spec, found := backup.Object["spec"]; found {
-			backupData["spec"] = spec
+// DeleteBackup asks Velero to delete a backup through a DeleteBackupRequest so the data in
+// object storage and any volume snapshots are removed too. ?force=true deletes the Backup
+// object directly, which is only meant for backups stuck in a state Velero can't clean up.
+func (h *VeleroHandler) DeleteBackup(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	backupName := c.Param("name")
+	if backupName == "" {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidName, nil)
+		return
+	}
+
+	if c.Query("force") == "true" {
+		// Delete the backup from Velero namespace
+		err := k8s.RetryDelete(func() error {
+			return h.k8sClient.DynamicClient.
+				Resource(k8s.BackupGVR).
+				Namespace("velero").
+				Delete(ctx, backupName, metav1.DeleteOptions{})
+		})
+		h.k8sClient.ListCache.Invalidate(k8s.BackupGVR)
+
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, ErrCodeBackupDeleteFailed, err)
+			return
 		}
 
-		backups = append(backups, backupData)
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Backup object deleted; data in object storage was not removed",
+			"backup":  backupName,
+			"forced":  true,
+		})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"backups": backups,
-		"count":   len(backups),
+	backup, err := k8s.Retry(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.
+			Resource(k8s.BackupGVR).
+			Namespace("velero").
+			Get(ctx, backupName, metav1.GetOptions{})
 	})
-}
 
-func (h *VeleroHandler) DeleteBackup(c *gin.Context) {
-	backupName := c.Param("name")
-	if backupName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "backup name is required",
-		})
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeBackupNotFound, err)
 		return
 	}
 
-	// Delete the backup from Velero namespace
-	err := h.k8sClient.DynamicClient.
-		Resource(k8s.BackupGVR).
+	// Same shape the velero CLI uses for "velero backup delete"
+	deleteRequest := map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       "DeleteBackupRequest",
+		"metadata": map[string]interface{}{
+			"generateName": backupName + "-",
+			"namespace":    "velero",
+			"labels": map[string]interface{}{
+				"velero.io/backup-name": backupName,
+				"velero.io/backup-uid":  string(backup.GetUID()),
+			},
+		},
+		"spec": map[string]interface{}{
+			"backupName": backupName,
+		},
+	}
+
+	result, err := h.k8sClient.DynamicClient.
+		Resource(k8s.DeleteBackupRequestGVR).
 		Namespace("velero").
-		Delete(h.k8sClient.Context, backupName, metav1.DeleteOptions{})
+		Create(ctx, &unstructured.Unstructured{Object: deleteRequest}, metav1.CreateOptions{})
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to delete backup",
-			"details": err.Error(),
-			"backup":  backupName,
-		})
+		respondError(c, http.StatusInternalServerError, ErrCodeBackupDeleteFailed, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Backup deleted successfully",
-		"backup":  backupName,
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":       "Backup deletion requested",
+		"backup":        backupName,
+		"deleteRequest": result.GetName(),
 	})
 }
 
 // GetBackupDetails retrieves detailed information about a backup
 func (h *VeleroHandler) GetBackupDetails(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	backupName := c.Param("name")
 
 	// Get detailed backup information
-	backup, err := h.k8sClient.DynamicClient.Resource(k8s.BackupGVR).Namespace("velero").Get(h.k8sClient.Context, backupName, metav1.GetOptions{})
+	backup, err := k8s.Retry(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.Resource(k8s.BackupGVR).Namespace("velero").Get(ctx, backupName, metav1.GetOptions{})
+	})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Backup not found: %v", err)})
+		respondError(c, http.StatusNotFound, ErrCodeBackupNotFound, err)
 		return
 	}
 
@@ -186,42 +246,128 @@ func (h *VeleroHandler) GetBackupDetails(c *gin.Context) {
 	c.JSON(http.StatusOK, details)
 }
 
-// GetBackupLogs retrieves logs for a backup
+// GetBackupLogs streams a backup's log from object storage as plain text. Logs can be
+// hundreds of MB, so they're decompressed straight into the response, never buffered.
 func (h *VeleroHandler) GetBackupLogs(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	backupName := c.Param("name")
 
-	// In a real implementation, you'd get the logs from Velero
-	logs := fmt.Sprintf(`time="2024-09-04T20:30:15Z" level=info msg="Starting backup %s"
-time="2024-09-04T20:30:16Z" level=info msg="Getting backup item actions"
-time="2024-09-04T20:30:17Z" level=info msg="Backing up resource configmaps"
-time="2024-09-04T20:30:18Z" level=info msg="Backed up 5 configmaps"
-time="2024-09-04T20:30:19Z" level=info msg="Backing up resource secrets"
-time="2024-09-04T20:30:20Z" level=info msg="Backed up 12 secrets"
-time="2024-09-04T20:30:25Z" level=info msg="Backup completed successfully"
-time="2024-09-04T20:30:26Z" level=info msg="Backup %s completed with 0 errors and 0 warnings"`, backupName, backupName)
+	_, err := k8s.Retry(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.Resource(k8s.BackupGVR).Namespace("velero").Get(ctx, backupName, metav1.GetOptions{})
+	})
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusNotFound, ErrCodeBackupNotFound, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeBackupGetFailed, err)
+		return
+	}
+
+	downloadURL, err := h.getDownloadURL(ctx, "BackupLog", backupName)
+	if err != nil {
+		if err == errDownloadRequestTimeout {
+			respondError(c, http.StatusRequestTimeout, ErrCodeDownloadTimeout, nil)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, ErrCodeDownloadFailed, err)
+		return
+	}
+
+	h.streamGzippedLog(c, downloadURL, ErrCodeBackupLogNotFound)
+}
+
+// logStreamTimeout bounds how long a single log download may take
+const logStreamTimeout = 10 * time.Minute
+
+// streamGzippedLog decompresses the gzipped log at downloadURL into the response as it
+// arrives; without a Content-Length gin sends it with chunked transfer encoding
+func (h *VeleroHandler) streamGzippedLog(c *gin.Context, downloadURL, notFoundCode string) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), logStreamTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeDownloadFailed, err)
+		return
+	}
+
+	resp, err := http.DefaultClient.Do(request)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeDownloadFailed, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		respondError(c, http.StatusNotFound, notFoundCode, nil)
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		respondError(c, http.StatusInternalServerError, ErrCodeDownloadFailed, fmt.Errorf("download URL returned HTTP %d", resp.StatusCode))
+		return
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeDownloadFailed, fmt.Errorf("failed to decompress log: %v", err))
+		return
+	}
+	defer reader.Close()
+
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.Status(http.StatusOK)
 
-	c.JSON(http.StatusOK, gin.H{"logs": logs})
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		// Headers are already sent, so the client just sees a truncated log
+		logRequestError(c, "Failed to stream log", err)
+	}
 }
 
 // DownloadBackup handles backup download requests using Velero's DownloadRequest CRD
 func (h *VeleroHandler) DownloadBackup(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	backupName := c.Param("name")
 
 	// Check if backup exists and is completed
-	backup, err := h.k8sClient.DynamicClient.Resource(k8s.BackupGVR).Namespace("velero").Get(h.k8sClient.Context, backupName, metav1.GetOptions{})
+	backup, err := k8s.Retry(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.Resource(k8s.BackupGVR).Namespace("velero").Get(ctx, backupName, metav1.GetOptions{})
+	})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Backup not found: %v", err)})
+		respondError(c, http.StatusNotFound, ErrCodeBackupNotFound, err)
 		return
 	}
 
 	phase, found, err := unstructured.NestedString(backup.Object, "status", "phase")
 	if err != nil || !found || phase != "Completed" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Backup is not completed or ready for download"})
+		respondError(c, http.StatusBadRequest, ErrCodeBackupNotReady, nil)
+		return
+	}
+
+	downloadURL, err := h.getDownloadURL(ctx, "BackupContents", backupName)
+	if err != nil {
+		if err == errDownloadRequestTimeout {
+			respondError(c, http.StatusRequestTimeout, ErrCodeDownloadTimeout, nil)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, ErrCodeDownloadFailed, err)
 		return
 	}
 
-	// Create a DownloadRequest CRD to get the backup file
-	downloadRequestName := fmt.Sprintf("backup-download-%s-%d", backupName, time.Now().Unix())
+	// Stream the file from the download URL
+	h.streamBackupFile(c, downloadURL, backupName)
+}
+
+var errDownloadRequestTimeout = errors.New("download request timed out")
+
+// getDownloadURL creates a Velero DownloadRequest for the given target and waits
+// for Velero to process it, returning the signed URL of the requested file
+func (h *VeleroHandler) getDownloadURL(ctx context.Context, targetKind, targetName string) (string, error) {
+	downloadRequestName := fmt.Sprintf("%s-download-%s-%d", strings.ToLower(targetKind), targetName, time.Now().Unix())
 	downloadRequest := &unstructured.Unstructured{
 		Object: map[string]interface{}{
 			"apiVersion": "velero.io/v1",
@@ -232,20 +378,27 @@ func (h *VeleroHandler) DownloadBackup(c *gin.Context) {
 			},
 			"spec": map[string]interface{}{
 				"target": map[string]interface{}{
-					"kind": "BackupContents",
-					"name": backupName,
+					"kind": targetKind,
+					"name": targetName,
 				},
 			},
 		},
 	}
 
 	// Create the download request
-	_, err = h.k8sClient.DynamicClient.Resource(k8s.DownloadRequestGVR).Namespace("velero").Create(h.k8sClient.Context, downloadRequest, metav1.CreateOptions{})
+	_, err := h.k8sClient.DynamicClient.Resource(k8s.DownloadRequestGVR).Namespace("velero").Create(ctx, downloadRequest, metav1.CreateOptions{})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create download request: %v", err)})
-		return
+		return "", fmt.Errorf("Failed to create download request: %v", err)
 	}
 
+	// The download request is only needed until we have the URL. It's deleted even when
+	// the client has gone away, so the cleanup only keeps the request's values.
+	defer func() {
+		cleanupCtx, cancel := h.k8sClient.RequestContext(context.WithoutCancel(ctx))
+		defer cancel()
+		h.k8sClient.DynamicClient.Resource(k8s.DownloadRequestGVR).Namespace("velero").Delete(cleanupCtx, downloadRequestName, metav1.DeleteOptions{})
+	}()
+
 	// Wait for the download request to be processed (with timeout)
 	timeout := time.After(30 * time.Second)
 	ticker := time.NewTicker(1 * time.Second)
@@ -254,34 +407,26 @@ func (h *VeleroHandler) DownloadBackup(c *gin.Context) {
 	for {
 		select {
 		case <-timeout:
-			// Clean up the download request on timeout
-			h.k8sClient.DynamicClient.Resource(k8s.DownloadRequestGVR).Namespace("velero").Delete(h.k8sClient.Context, downloadRequestName, metav1.DeleteOptions{})
-			c.JSON(http.StatusRequestTimeout, gin.H{"error": "Download request timed out"})
-			return
+			return "", errDownloadRequestTimeout
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return "", errDownloadRequestTimeout
+			}
+			return "", ctx.Err()
 		case <-ticker.C:
 			// Check if download request is processed
-			dr, err := h.k8sClient.DynamicClient.Resource(k8s.DownloadRequestGVR).Namespace("velero").Get(h.k8sClient.Context, downloadRequestName, metav1.GetOptions{})
+			dr, err := h.k8sClient.DynamicClient.Resource(k8s.DownloadRequestGVR).Namespace("velero").Get(ctx, downloadRequestName, metav1.GetOptions{})
 			if err != nil {
 				continue
 			}
 
 			phase, found, _ := unstructured.NestedString(dr.Object, "status", "phase")
 			if found && phase == "Processed" {
-				// Get the download URL
 				downloadURL, found, _ := unstructured.NestedString(dr.Object, "status", "downloadURL")
 				if !found || downloadURL == "" {
-					// Clean up the download request
-					h.k8sClient.DynamicClient.Resource(k8s.DownloadRequestGVR).Namespace("velero").Delete(h.k8sClient.Context, downloadRequestName, metav1.DeleteOptions{})
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Download URL not available"})
-					return
+					return "", fmt.Errorf("Download URL not available")
 				}
-
-				// Clean up the download request
-				h.k8sClient.DynamicClient.Resource(k8s.DownloadRequestGVR).Namespace("velero").Delete(h.k8sClient.Context, downloadRequestName, metav1.DeleteOptions{})
-
-				// Stream the file from the download URL
-				h.streamBackupFile(c, downloadURL, backupName)
-				return
+				return downloadURL, nil
 			}
 		}
 	}
@@ -296,13 +441,13 @@ func (h *VeleroHandler) streamBackupFile(c *gin.Context, downloadURL, backupName
 
 	resp, err := client.Get(downloadURL)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to download backup file: %v", err)})
+		respondError(c, http.StatusInternalServerError, ErrCodeDownloadFailed, err)
 		return
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to download backup file: HTTP %d", resp.StatusCode)})
+		respondError(c, http.StatusInternalServerError, ErrCodeDownloadFailed, fmt.Errorf("download URL returned HTTP %d", resp.StatusCode))
 		return
 	}
 
@@ -318,21 +463,201 @@ func (h *VeleroHandler) streamBackupFile(c *gin.Context, downloadURL, backupName
 	c.DataFromReader(http.StatusOK, resp.ContentLength, "application/octet-stream", resp.Body, map[string]string{})
 }
 
+// GetBackupVolumes returns the per-volume snapshot information Velero recorded for a backup
+func (h *VeleroHandler) GetBackupVolumes(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	backupName := c.Param("name")
+
+	backup, err := k8s.Retry(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.Resource(k8s.BackupGVR).Namespace("velero").Get(ctx, backupName, metav1.GetOptions{})
+	})
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeBackupNotFound, err)
+		return
+	}
+
+	// Summary counters Velero keeps on the backup status
+	attempted, _, _ := unstructured.NestedInt64(backup.Object, "status", "volumeSnapshotsAttempted")
+	completed, _, _ := unstructured.NestedInt64(backup.Object, "status", "volumeSnapshotsCompleted")
+	csiAttempted, _, _ := unstructured.NestedInt64(backup.Object, "status", "csiVolumeSnapshotsAttempted")
+	csiCompleted, _, _ := unstructured.NestedInt64(backup.Object, "status", "csiVolumeSnapshotsCompleted")
+
+	response := gin.H{
+		"backup": backupName,
+		"summary": gin.H{
+			"volumeSnapshotsAttempted":    attempted,
+			"volumeSnapshotsCompleted":    completed,
+			"csiVolumeSnapshotsAttempted": csiAttempted,
+			"csiVolumeSnapshotsCompleted": csiCompleted,
+		},
+		"volumes": []map[string]interface{}{},
+		"count":   0,
+	}
+
+	// Volume snapshot details are only uploaded once the backup has finished
+	phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase")
+	if phase != "Completed" && phase != "PartiallyFailed" {
+		response["message"] = "Volume snapshot details are available once the backup has finished"
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	volumes, err := h.fetchBackupVolumeSnapshots(ctx, backupName)
+	if err != nil {
+		response["message"] = fmt.Sprintf("Volume snapshot details not available: %v", err)
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	response["volumes"] = volumes
+	response["count"] = len(volumes)
+	c.JSON(http.StatusOK, response)
+}
+
+// fetchBackupVolumeSnapshots downloads and decodes the BackupVolumeSnapshots file for a backup
+func (h *VeleroHandler) fetchBackupVolumeSnapshots(ctx context.Context, backupName string) ([]map[string]interface{}, error) {
+	var snapshots []struct {
+		Spec struct {
+			PersistentVolumeName string `json:"persistentVolumeName"`
+			ProviderVolumeID     string `json:"providerVolumeID"`
+			Location             string `json:"location"`
+			VolumeType           string `json:"volumeType"`
+			VolumeAZ             string `json:"volumeAZ"`
+		} `json:"spec"`
+		Status struct {
+			ProviderSnapshotID string `json:"providerSnapshotID"`
+			Phase              string `json:"phase"`
+		} `json:"status"`
+	}
+	if err := h.downloadGzippedJSON(ctx, "BackupVolumeSnapshots", backupName, &snapshots); err != nil {
+		if err == errDownloadNotFound {
+			// Backups without any volume snapshots don't have the file
+			return []map[string]interface{}{}, nil
+		}
+		return nil, err
+	}
+
+	volumes := make([]map[string]interface{}, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		volumes = append(volumes, map[string]interface{}{
+			"persistentVolume":   snapshot.Spec.PersistentVolumeName,
+			"providerVolumeID":   snapshot.Spec.ProviderVolumeID,
+			"providerSnapshotID": snapshot.Status.ProviderSnapshotID,
+			"phase":              snapshot.Status.Phase,
+			"location":           snapshot.Spec.Location,
+			"volumeType":         snapshot.Spec.VolumeType,
+			"availabilityZone":   snapshot.Spec.VolumeAZ,
+		})
+	}
+
+	return volumes, nil
+}
+
+// GetBackupResourceList returns the resources included in a backup, grouped by API group/version/kind
+func (h *VeleroHandler) GetBackupResourceList(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	backupName := c.Param("name")
+
+	backup, err := k8s.Retry(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.Resource(k8s.BackupGVR).Namespace("velero").Get(ctx, backupName, metav1.GetOptions{})
+	})
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeBackupNotFound, err)
+		return
+	}
+
+	phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase")
+	if phase != "Completed" && phase != "PartiallyFailed" {
+		respondError(c, http.StatusBadRequest, ErrCodeBackupNotReady, nil)
+		return
+	}
+
+	// Velero stores the list as {"apps/v1/Deployment": ["ns/name", ...], ...}
+	var resources map[string][]string
+	if err := h.downloadGzippedJSON(ctx, "BackupResourceList", backupName, &resources); err != nil {
+		switch err {
+		case errDownloadRequestTimeout:
+			respondError(c, http.StatusRequestTimeout, ErrCodeDownloadTimeout, nil)
+		case errDownloadNotFound:
+			respondError(c, http.StatusNotFound, ErrCodeResourceListNotFound, nil)
+		default:
+			respondError(c, http.StatusInternalServerError, ErrCodeDownloadFailed, err)
+		}
+		return
+	}
+
+	totalItems := 0
+	for _, items := range resources {
+		totalItems += len(items)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"backup":        backupName,
+		"resources":     resources,
+		"resourceTypes": len(resources),
+		"totalItems":    totalItems,
+	})
+}
+
+var errDownloadNotFound = errors.New("requested file not found in object storage")
+
+// downloadGzippedJSON fetches a gzipped JSON file for a backup or restore through a DownloadRequest and decodes it into out
+func (h *VeleroHandler) downloadGzippedJSON(ctx context.Context, targetKind, targetName string, out interface{}) error {
+	downloadURL, err := h.getDownloadURL(ctx, targetKind, targetName)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{
+		Timeout: 1 * time.Minute,
+	}
+
+	resp, err := client.Get(downloadURL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %v", targetKind, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return errDownloadNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: HTTP %d", targetKind, resp.StatusCode)
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decompress %s: %v", targetKind, err)
+	}
+	defer reader.Close()
+
+	if err := json.NewDecoder(reader).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", targetKind, err)
+	}
+
+	return nil
+}
+
 // DescribeBackup returns detailed information about a backup (equivalent to velero backup describe --details)
 func (h *VeleroHandler) DescribeBackup(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	backupName := c.Param("name")
 
-	backup, err := h.k8sClient.DynamicClient.
-		Resource(k8s.BackupGVR).
-		Namespace("velero").
-		Get(h.k8sClient.Context, backupName, metav1.GetOptions{})
+	backup, err := k8s.Retry(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.
+			Resource(k8s.BackupGVR).
+			Namespace("velero").
+			Get(ctx, backupName, metav1.GetOptions{})
+	})
 
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Backup not found",
-			"details": err.Error(),
-			"backup":  backupName,
-		})
+		respondError(c, http.StatusNotFound, ErrCodeBackupNotFound, err)
 		return
 	}
 
@@ -360,90 +685,375 @@ func (h *VeleroHandler) DescribeBackup(c *gin.Context) {
 	})
 }
 
+// CreateBackupRequest is the body of POST /backups
+type CreateBackupRequest struct {
+	Name               string       `json:"name" binding:"required"`
+	IncludedNamespaces []string     `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string     `json:"excludedNamespaces,omitempty"`
+	StorageLocation    string       `json:"storageLocation,omitempty"`
+	TTL                string       `json:"ttl,omitempty"`
+	Hooks              *BackupHooks `json:"hooks,omitempty"`
+	// Resource type -> comma-separated names backed up in that order
+	OrderedResources        map[string]string `json:"orderedResources,omitempty"`
+	IncludeClusterResources *bool             `json:"includeClusterResources,omitempty"`
+	ResourcePolicy          *ResourcePolicy   `json:"resourcePolicy,omitempty"`
+	ScopedResourceFilters
+	ObjectMetadata
+}
+
 func (h *VeleroHandler) CreateBackup(c *gin.Context) {
-	var request struct {
-		Name               string   `json:"name" binding:"required"`
-		IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
-		ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
-		StorageLocation    string   `json:"storageLocation,omitempty"`
-		TTL                string   `json:"ttl,omitempty"`
-	}
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	var request CreateBackupRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, err)
 		return
 	}
 
-	// Set defaults
-	if request.StorageLocation == "" {
-		request.StorageLocation = "default"
-	}
-	if request.TTL == "" {
-		request.TTL = "720h0m0s"
+	if errs := validation.IsDNS1123Subdomain(request.Name); len(errs) > 0 {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidName, fmt.Errorf("%s", strings.Join(errs, "; ")))
+		return
 	}
 
-	// Create backup object
-	backup := map[string]interface{}{
-		"apiVersion": "velero.io/v1",
-		"kind":       "Backup",
-		"metadata": map[string]interface{}{
-			"name":      request.Name,
-			"namespace": "velero",
-		},
-		"spec": map[string]interface{}{
-			"storageLocation": request.StorageLocation,
-			"ttl":             request.TTL,
-		},
+	if err := request.ObjectMetadata.validate(); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidMetadata, err)
+		return
 	}
 
-	// Add namespaces if specified
-	if len(request.IncludedNamespaces) > 0 {
-		backup["spec"].(map[string]interface{})["includedNamespaces"] = request.IncludedNamespaces
+	if request.Hooks != nil {
+		if err := request.Hooks.validate(); err != nil {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidHooks, err)
+			return
+		}
+	}
+
+	if err := validateOrderedResources(request.OrderedResources); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidOrderedResources, err)
+		return
+	}
+
+	if request.ResourcePolicy != nil {
+		if err := request.ResourcePolicy.validate(ctx, h); errors.Is(err, errInvalidResourcePolicy) {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidResourcePolicy, err)
+			return
+		} else if err != nil {
+			respondError(c, http.StatusInternalServerError, ErrCodeResourcePolicyGetFailed, err)
+			return
+		}
+	}
+
+	// Backups are taken from this cluster, so a typo in includedNamespaces would silently
+	// back up nothing. ?validateNamespaces=false skips the check.
+	if c.Query("validateNamespaces") != "false" {
+		missing, err := h.missingNamespaces(ctx, request.IncludedNamespaces)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, ErrCodeNamespaceGetFailed, err)
+			return
+		}
+		if len(missing) > 0 {
+			respondError(c, http.StatusBadRequest, ErrCodeNamespaceNotFound, fmt.Errorf("%s", strings.Join(missing, ", ")))
+			return
+		}
+	}
+
+	// Optional synchronous mode: ?wait=true&timeout=10m
+	wait := c.Query("wait") == "true"
+	waitTimeout := defaultWaitTimeout
+	if wait && c.Query("timeout") != "" {
+		parsed, err := time.ParseDuration(c.Query("timeout"))
+		if err != nil || parsed <= 0 || parsed > maxWaitTimeout {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidTimeout, fmt.Errorf("timeout must be a duration between 0 and %s", maxWaitTimeout))
+			return
+		}
+		waitTimeout = parsed
+	}
+
+	// Set defaults
+	if request.StorageLocation == "" {
+		request.StorageLocation = "default"
+	}
+
+	// Compare against Velero's own default so expiry isn't a surprise. If the
+	// deployment can't be read we keep the previous hardcoded default.
+	var ttlWarning string
+	if info, err := h.getVeleroServerInfo(ctx); err == nil {
+		if request.TTL == "" {
+			request.TTL = info.DefaultBackupTTL.String()
+		} else if requested, err := time.ParseDuration(request.TTL); err == nil {
+			ttlWarning = ttlMismatchWarning(requested, info.DefaultBackupTTL)
+		}
+	}
+	if request.TTL == "" {
+		request.TTL = "720h0m0s"
+	}
+
+	// Create backup object
+	backup := map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       "Backup",
+		"metadata": map[string]interface{}{
+			"name":      request.Name,
+			"namespace": "velero",
+		},
+		"spec": map[string]interface{}{
+			"storageLocation": request.StorageLocation,
+			"ttl":             request.TTL,
+		},
+	}
+
+	// Add namespaces if specified
+	if len(request.IncludedNamespaces) > 0 {
+		backup["spec"].(map[string]interface{})["includedNamespaces"] = request.IncludedNamespaces
 	}
 	if len(request.ExcludedNamespaces) > 0 {
 		backup["spec"].(map[string]interface{})["excludedNamespaces"] = request.ExcludedNamespaces
 	}
+	if request.Hooks != nil && len(request.Hooks.Resources) > 0 {
+		backup["spec"].(map[string]interface{})["hooks"] = request.Hooks.toSpec()
+	}
+	if len(request.OrderedResources) > 0 {
+		backup["spec"].(map[string]interface{})["orderedResources"] = request.OrderedResources
+	}
+	if request.IncludeClusterResources != nil {
+		backup["spec"].(map[string]interface{})["includeClusterResources"] = *request.IncludeClusterResources
+	}
+	if request.ResourcePolicy != nil {
+		backup["spec"].(map[string]interface{})["resourcePolicy"] = request.ResourcePolicy.toSpec()
+	}
+	request.ScopedResourceFilters.applyTo(backup["spec"].(map[string]interface{}))
+	request.ObjectMetadata.applyTo(backup["metadata"].(map[string]interface{}))
+
+	if err := validateResourceFilters(backup["spec"].(map[string]interface{})); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidResourceFilters, err)
+		return
+	}
 
 	// Create the backup in Kubernetes
-	result, err := h.k8sClient.DynamicClient.
-		Resource(k8s.BackupGVR).
-		Namespace("velero").
-		Create(h.k8sClient.Context, &unstructured.Unstructured{Object: backup}, metav1.CreateOptions{})
+	createOptions, dryRun := createOptionsFor(c)
+	result, err := k8s.RetryCreate(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.
+			Resource(k8s.BackupGVR).
+			Namespace("velero").
+			Create(ctx, &unstructured.Unstructured{Object: backup}, createOptions)
+	})
+	if !dryRun {
+		h.k8sClient.ListCache.Invalidate(k8s.BackupGVR)
+	}
 
+	if apierrors.IsAlreadyExists(err) {
+		respondError(c, http.StatusConflict, ErrCodeBackupExists, err)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create backup",
-			"details": err.Error(),
-			"backup":  request.Name,
+		respondError(c, http.StatusInternalServerError, ErrCodeBackupCreateFailed, err)
+		return
+	}
+
+	if dryRun {
+		c.JSON(http.StatusOK, gin.H{
+			"message":  "Dry run: the backup was validated but not created",
+			"dryRun":   true,
+			"manifest": result.Object,
 		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Backup created successfully",
-		"backup":  result.GetName(),
-		"status":  "created",
-	})
+	if !wait {
+		response := gin.H{
+			"message": "Backup created successfully",
+			"backup":  result.GetName(),
+			"status":  "created",
+		}
+		if ttlWarning != "" {
+			response["warning"] = ttlWarning
+		}
+		c.JSON(http.StatusCreated, response)
+		return
+	}
+
+	final, err := h.waitForPhase(k8s.BackupGVR, result, waitTimeout, backupTerminalPhases)
+	phase, _, _ := unstructured.NestedString(final.Object, "status", "phase")
+	if err == errWaitTimeout {
+		// Still running; the client can keep polling the backup by name
+		response := gin.H{
+			"message": "Backup still running after timeout",
+			"backup":  result.GetName(),
+			"phase":   phase,
+			"timeout": waitTimeout.String(),
+		}
+		if ttlWarning != "" {
+			response["warning"] = ttlWarning
+		}
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeWaitFailed, err)
+		return
+	}
+
+	totalItems, _, _ := unstructured.NestedInt64(final.Object, "status", "progress", "totalItems")
+	itemsBackedUp, _, _ := unstructured.NestedInt64(final.Object, "status", "progress", "itemsBackedUp")
+	errorCount, _, _ := unstructured.NestedInt64(final.Object, "status", "errors")
+	warningCount, _, _ := unstructured.NestedInt64(final.Object, "status", "warnings")
+
+	response := gin.H{
+		"message":       fmt.Sprintf("Backup finished with phase %s", phase),
+		"backup":        final.GetName(),
+		"phase":         phase,
+		"totalItems":    totalItems,
+		"itemsBackedUp": itemsBackedUp,
+		"errors":        errorCount,
+		"warnings":      warningCount,
+		"status":        final.Object["status"],
+	}
+	if ttlWarning != "" {
+		response["warning"] = ttlWarning
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+var (
+	// Resource types like "pods" or "deployments.apps"
+	resourceTypePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	// Resource names, optionally namespaced: "name" or "namespace/name"
+	resourceNamePattern = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?/)?[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+)
+
+// validateOrderedResources checks a Velero orderedResources map: keys are resource types
+// and values are comma-separated resource names
+func validateOrderedResources(orderedResources map[string]string) error {
+	for resourceType, names := range orderedResources {
+		if !resourceTypePattern.MatchString(resourceType) {
+			return fmt.Errorf("%q is not a valid resource type", resourceType)
+		}
+		for _, name := range strings.Split(names, ",") {
+			if !resourceNamePattern.MatchString(strings.TrimSpace(name)) {
+				return fmt.Errorf("%q is not a valid resource name for %s", name, resourceType)
+			}
+		}
+	}
+	return nil
+}
+
+// missingNamespaces returns the namespaces that don't exist in this cluster. Wildcards and
+// glob patterns are skipped since they match whatever exists.
+func (h *VeleroHandler) missingNamespaces(ctx context.Context, namespaces []string) ([]string, error) {
+	missing := []string{}
+	for _, namespace := range namespaces {
+		if strings.ContainsAny(namespace, "*?[") {
+			continue
+		}
+		_, err := h.k8sClient.Clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			missing = append(missing, namespace)
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get namespace %q: %w", namespace, err)
+		}
+	}
+	return missing, nil
+}
+
+const (
+	defaultWaitTimeout = 10 * time.Minute
+	maxWaitTimeout     = 1 * time.Hour
+)
+
+var (
+	errWaitTimeout = errors.New("timed out waiting for a terminal phase")
+
+	backupTerminalPhases  = []string{"Completed", "Failed", "PartiallyFailed", "FailedValidation"}
+	restoreTerminalPhases = []string{"Completed", "Failed", "PartiallyFailed", "FailedValidation"}
+)
+
+// waitForPhase watches a Velero object until its status.phase is one of the given phases
+// or the timeout elapses. The last observed version of the object is always returned.
+func (h *VeleroHandler) waitForPhase(gvr schema.GroupVersionResource, obj *unstructured.Unstructured, timeout time.Duration, phases []string) (*unstructured.Unstructured, error) {
+	ctx, cancel := context.WithTimeout(h.k8sClient.Context, timeout)
+	defer cancel()
+
+	isTerminal := func(o *unstructured.Unstructured) bool {
+		phase, _, _ := unstructured.NestedString(o.Object, "status", "phase")
+		for _, p := range phases {
+			if phase == p {
+				return true
+			}
+		}
+		return false
+	}
+
+	last := obj
+	if isTerminal(last) {
+		return last, nil
+	}
+
+	resource := h.k8sClient.DynamicClient.Resource(gvr).Namespace(obj.GetNamespace())
+	for {
+		watcher, err := resource.Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", obj.GetName()).String(),
+			ResourceVersion: last.GetResourceVersion(),
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return last, errWaitTimeout
+			}
+			return last, fmt.Errorf("failed to watch %s: %v", obj.GetName(), err)
+		}
+
+		for event := range watcher.ResultChan() {
+			switch event.Type {
+			case watch.Error:
+				// Usually an expired resource version; refresh the object and watch again
+				if current, err := resource.Get(ctx, obj.GetName(), metav1.GetOptions{}); err == nil {
+					last = current
+					if isTerminal(last) {
+						watcher.Stop()
+						return last, nil
+					}
+				}
+				watcher.Stop()
+			case watch.Deleted:
+				watcher.Stop()
+				return last, fmt.Errorf("%s was deleted while waiting", obj.GetName())
+			case watch.Added, watch.Modified:
+				if updated, ok := event.Object.(*unstructured.Unstructured); ok {
+					last = updated
+					if isTerminal(last) {
+						watcher.Stop()
+						return last, nil
+					}
+				}
+			}
+		}
+		watcher.Stop()
+
+		// The watch channel closes on timeout or when the server ends the watch; re-establish it
+		// unless we ran out of time
+		if ctx.Err() != nil {
+			return last, errWaitTimeout
+		}
+	}
 }
 
 // DeleteRestore deletes a restore
 func (h *VeleroHandler) DeleteRestore(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	name := c.Param("name")
 
-	err := h.k8sClient.DynamicClient.
-		Resource(k8s.RestoreGVR).
-		Namespace("velero").
-		Delete(h.k8sClient.Context, name, metav1.DeleteOptions{})
+	err := k8s.RetryDelete(func() error {
+		return h.k8sClient.DynamicClient.
+			Resource(k8s.RestoreGVR).
+			Namespace("velero").
+			Delete(ctx, name, metav1.DeleteOptions{})
+	})
+	h.k8sClient.ListCache.Invalidate(k8s.RestoreGVR)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to delete restore",
-			"details": err.Error(),
-			"restore": name,
-		})
+		respondError(c, http.StatusInternalServerError, ErrCodeRestoreDeleteFailed, err)
 		return
 	}
 
@@ -467,19 +1077,20 @@ func (h *VeleroHandler) GetRestoreLogs(c *gin.Context) {
 
 // DescribeRestore returns detailed information about a restore
 func (h *VeleroHandler) DescribeRestore(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	name := c.Param("name")
 
-	restore, err := h.k8sClient.DynamicClient.
-		Resource(k8s.RestoreGVR).
-		Namespace("velero").
-		Get(h.k8sClient.Context, name, metav1.GetOptions{})
+	restore, err := k8s.Retry(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.
+			Resource(k8s.RestoreGVR).
+			Namespace("velero").
+			Get(ctx, name, metav1.GetOptions{})
+	})
 
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Restore not found",
-			"details": err.Error(),
-			"restore": name,
-		})
+		respondError(c, http.StatusNotFound, ErrCodeRestoreNotFound, err)
 		return
 	}
 
@@ -489,97 +1100,505 @@ func (h *VeleroHandler) DescribeRestore(c *gin.Context) {
 		"metadata":  restore.Object["metadata"],
 		"spec":      restore.Object["spec"],
 		"status":    restore.Object["status"],
+		"progress":  restoreProgress(restore),
 	})
 }
-func (h *VeleroHandler) CreateRestore(c *gin.Context) {
-	var request struct {
-		Name                    string            `json:"name" binding:"required"`
-		BackupName              string            `json:"backupName" binding:"required"`
-		TargetCluster           string            `json:"targetCluster,omitempty"`
-		IncludedNamespaces      []string          `json:"includedNamespaces,omitempty"`
-		ExcludedNamespaces      []string          `json:"excludedNamespaces,omitempty"`
-		NamespaceMapping        map[string]string `json:"namespaceMapping,omitempty"`
-		RestorePVs              *bool             `json:"restorePVs,omitempty"`
-		IncludeClusterResources *bool             `json:"includeClusterResources,omitempty"`
+
+// RestoreProgress is the parsed progress of a restore, including async item operations
+// such as CSI snapshot restores and data movement. Counts the Velero version doesn't
+// report are nil.
+type RestoreProgress struct {
+	ItemsRestored       *int64   `json:"itemsRestored"`
+	TotalItems          *int64   `json:"totalItems"`
+	OperationsAttempted *int64   `json:"operationsAttempted"`
+	OperationsCompleted *int64   `json:"operationsCompleted"`
+	OperationsFailed    *int64   `json:"operationsFailed"`
+	PercentComplete     *float64 `json:"percentComplete"`
+}
+
+// restoreProgress parses a restore's progress. The percentage counts restored items and
+// finished item operations against their totals, and is 100 once the restore is done.
+func restoreProgress(restore *unstructured.Unstructured) RestoreProgress {
+	count := func(fields ...string) *int64 {
+		value, found, err := unstructured.NestedInt64(restore.Object, fields...)
+		if !found || err != nil {
+			return nil
+		}
+		return &value
+	}
+	valueOf := func(value *int64) int64 {
+		if value == nil {
+			return 0
+		}
+		return *value
 	}
 
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+	progress := RestoreProgress{
+		ItemsRestored:       count("status", "progress", "itemsRestored"),
+		TotalItems:          count("status", "progress", "totalItems"),
+		OperationsAttempted: count("status", "restoreItemOperationsAttempted"),
+		OperationsCompleted: count("status", "restoreItemOperationsCompleted"),
+		OperationsFailed:    count("status", "restoreItemOperationsFailed"),
+	}
+
+	phase, _, _ := unstructured.NestedString(restore.Object, "status", "phase")
+	done := valueOf(progress.ItemsRestored) + valueOf(progress.OperationsCompleted) + valueOf(progress.OperationsFailed)
+	total := valueOf(progress.TotalItems) + valueOf(progress.OperationsAttempted)
+
+	switch {
+	case slices.Contains(restoreTerminalPhases, phase):
+		percent := 100.0
+		progress.PercentComplete = &percent
+	case total > 0:
+		percent := math.Min(100, math.Round(float64(done)/float64(total)*1000)/10)
+		progress.PercentComplete = &percent
+	}
+
+	return progress
+}
+
+// veleroRestoreResult is the set of messages Velero records for a restore, as stored in
+// the RestoreResults file: messages from Velero itself, for cluster-scoped resources, and
+// per namespace
+type veleroRestoreResult struct {
+	Velero     []string            `json:"velero"`
+	Cluster    []string            `json:"cluster"`
+	Namespaces map[string][]string `json:"namespaces"`
+}
+
+// RestoreResult is a restore's messages with the cluster-scoped and namespaced messages
+// grouped by the resource they are about
+type RestoreResult struct {
+	Velero     []string                       `json:"velero"`
+	Cluster    map[string][]string            `json:"cluster"`
+	Namespaces map[string]map[string][]string `json:"namespaces"`
+}
+
+// otherResource groups restore messages that don't name a resource
+const otherResource = "other"
+
+// restoreMessageResources match the resource in Velero's restore messages, such as
+// "error restoring deployments.apps/shop/web: ..." or
+// "could not restore, ConfigMap "settings" already exists. ..."
+var restoreMessageResources = []*regexp.Regexp{
+	regexp.MustCompile(`^error restoring ([^/\s]+)/`),
+	regexp.MustCompile(`^could not restore, (\S+) "`),
+}
+
+// restoreMessageResource returns the resource a restore message is about
+func restoreMessageResource(message string) string {
+	for _, pattern := range restoreMessageResources {
+		if match := pattern.FindStringSubmatch(message); match != nil {
+			return match[1]
+		}
+	}
+	return otherResource
+}
+
+func groupByResource(messages []string) map[string][]string {
+	grouped := make(map[string][]string)
+	for _, message := range messages {
+		resource := restoreMessageResource(message)
+		grouped[resource] = append(grouped[resource], message)
+	}
+	return grouped
+}
+
+func newRestoreResult(raw veleroRestoreResult) RestoreResult {
+	result := RestoreResult{
+		Velero:     raw.Velero,
+		Cluster:    groupByResource(raw.Cluster),
+		Namespaces: make(map[string]map[string][]string, len(raw.Namespaces)),
+	}
+	if result.Velero == nil {
+		result.Velero = []string{}
+	}
+	for namespace, messages := range raw.Namespaces {
+		result.Namespaces[namespace] = groupByResource(messages)
+	}
+	return result
+}
+
+func (r *RestoreResult) count() int {
+	total := len(r.Velero)
+	for _, messages := range r.Cluster {
+		total += len(messages)
+	}
+	for _, resources := range r.Namespaces {
+		for _, messages := range resources {
+			total += len(messages)
+		}
+	}
+	return total
+}
+
+// GetRestoreResults returns the detailed errors and warnings of a finished restore, which
+// Velero only keeps in object storage, grouped by namespace and resource
+func (h *VeleroHandler) GetRestoreResults(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	name := c.Param("name")
+
+	restore, err := k8s.Retry(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.
+			Resource(k8s.RestoreGVR).
+			Namespace("velero").
+			Get(ctx, name, metav1.GetOptions{})
+	})
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusNotFound, ErrCodeRestoreNotFound, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeRestoreGetFailed, err)
+		return
+	}
+
+	phase, _, _ := unstructured.NestedString(restore.Object, "status", "phase")
+	if phase != "Completed" && phase != "PartiallyFailed" && phase != "Failed" {
+		respondError(c, http.StatusConflict, ErrCodeRestoreNotReady, fmt.Errorf("restore %s is in phase %q", name, phase))
+		return
+	}
+
+	var raw struct {
+		Errors   veleroRestoreResult `json:"errors"`
+		Warnings veleroRestoreResult `json:"warnings"`
+	}
+	if err := h.downloadGzippedJSON(ctx, "RestoreResults", name, &raw); err != nil {
+		switch err {
+		case errDownloadRequestTimeout:
+			respondError(c, http.StatusRequestTimeout, ErrCodeDownloadTimeout, nil)
+		case errDownloadNotFound:
+			respondError(c, http.StatusNotFound, ErrCodeRestoreResultsNotFound, nil)
+		default:
+			respondError(c, http.StatusInternalServerError, ErrCodeDownloadFailed, err)
+		}
 		return
 	}
+	errs, warnings := newRestoreResult(raw.Errors), newRestoreResult(raw.Warnings)
+
+	c.JSON(http.StatusOK, gin.H{
+		"restore":      name,
+		"phase":        phase,
+		"errors":       errs,
+		"warnings":     warnings,
+		"errorCount":   errs.count(),
+		"warningCount": warnings.count(),
+	})
+}
+
+// WaitForRestore blocks until the restore reaches a terminal phase: ?timeout=10m
+func (h *VeleroHandler) WaitForRestore(c *gin.Context) {
+	name := c.Param("name")
+
+	waitTimeout := defaultWaitTimeout
+	if c.Query("timeout") != "" {
+		parsed, err := time.ParseDuration(c.Query("timeout"))
+		if err != nil || parsed <= 0 || parsed > maxWaitTimeout {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidTimeout, fmt.Errorf("timeout must be a duration between 0 and %s", maxWaitTimeout))
+			return
+		}
+		waitTimeout = parsed
+	}
+
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	restore, err := k8s.Retry(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.
+			Resource(k8s.RestoreGVR).
+			Namespace("velero").
+			Get(ctx, name, metav1.GetOptions{})
+	})
+
+	if err != nil {
+		respondError(c, http.StatusNotFound, ErrCodeRestoreNotFound, err)
+		return
+	}
+
+	final, err := h.waitForPhase(k8s.RestoreGVR, restore, waitTimeout, restoreTerminalPhases)
+	phase, _, _ := unstructured.NestedString(final.Object, "status", "phase")
+	if err == errWaitTimeout {
+		respondError(c, http.StatusGatewayTimeout, ErrCodeWaitTimeout, fmt.Errorf("restore %s still in phase %q", name, phase))
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeWaitFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Restore finished with phase %s", phase),
+		"restore": final.GetName(),
+		"phase":   phase,
+		"status":  final.Object["status"],
+	})
+}
+
+// createOptionsFor returns the create options for a request. With ?dryRun=true the API
+// server validates and defaults the object without persisting it.
+func createOptionsFor(c *gin.Context) (metav1.CreateOptions, bool) {
+	if c.Query("dryRun") != "true" {
+		return metav1.CreateOptions{}, false
+	}
+	return metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}, true
+}
 
-	// Create restore object
+// RestoreOptions are the optional restore settings shared by the restore endpoints
+type RestoreOptions struct {
+	TargetCluster           string            `json:"targetCluster,omitempty"`
+	IncludedNamespaces      []string          `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces      []string          `json:"excludedNamespaces,omitempty"`
+	NamespaceMapping        map[string]string `json:"namespaceMapping,omitempty"`
+	RestorePVs              *bool             `json:"restorePVs,omitempty"`
+	IncludeClusterResources *bool             `json:"includeClusterResources,omitempty"`
+	Hooks                   *RestoreHooks     `json:"hooks,omitempty"`
+}
+
+// validateNamespaceMapping checks that every namespace in a mapping is a valid namespace
+// name and that no two namespaces map to the same target. Velero only notices a bad
+// target while restoring into it, leaving the restore partially applied.
+func validateNamespaceMapping(mapping map[string]string) error {
+	sources := make([]string, 0, len(mapping))
+	for source := range mapping {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	mappedFrom := make(map[string]string, len(mapping))
+	for _, source := range sources {
+		target := mapping[source]
+		if errs := validation.IsDNS1123Label(source); len(errs) > 0 {
+			return fmt.Errorf("namespaceMapping source %q: %s", source, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsDNS1123Label(target); len(errs) > 0 {
+			return fmt.Errorf("namespaceMapping %q -> %q: %s", source, target, strings.Join(errs, "; "))
+		}
+		if other, exists := mappedFrom[target]; exists {
+			return fmt.Errorf("namespaceMapping maps both %q and %q to %q", other, source, target)
+		}
+		mappedFrom[target] = source
+	}
+	return nil
+}
+
+// restoreObject builds a Restore of backupName with the options applied
+func (o *RestoreOptions) restoreObject(name, backupName string) map[string]interface{} {
 	labels := make(map[string]interface{})
-	if request.TargetCluster != "" {
-		labels["velero.io/target-cluster"] = request.TargetCluster
+	if o.TargetCluster != "" {
+		labels[k8s.TargetClusterLabel] = o.TargetCluster
 	}
 
 	metadata := map[string]interface{}{
-		"name":      request.Name,
+		"name":      name,
 		"namespace": "velero",
 	}
 	if len(labels) > 0 {
 		metadata["labels"] = labels
 	}
 
-	restore := map[string]interface{}{
+	spec := map[string]interface{}{
+		"backupName": backupName,
+	}
+
+	// Add optional fields
+	if len(o.IncludedNamespaces) > 0 {
+		spec["includedNamespaces"] = o.IncludedNamespaces
+	}
+	if len(o.ExcludedNamespaces) > 0 {
+		spec["excludedNamespaces"] = o.ExcludedNamespaces
+	}
+	if len(o.NamespaceMapping) > 0 {
+		spec["namespaceMapping"] = o.NamespaceMapping
+	}
+	if o.RestorePVs != nil {
+		spec["restorePVs"] = *o.RestorePVs
+	}
+	if o.IncludeClusterResources != nil {
+		spec["includeClusterResources"] = *o.IncludeClusterResources
+	}
+	if o.Hooks != nil && len(o.Hooks.Resources) > 0 {
+		spec["hooks"] = o.Hooks.toSpec()
+	}
+
+	return map[string]interface{}{
 		"apiVersion": "velero.io/v1",
 		"kind":       "Restore",
 		"metadata":   metadata,
-		"spec": map[string]interface{}{
-			"backupName": request.BackupName,
-		},
+		"spec":       spec,
 	}
+}
 
-	spec := restore["spec"].(map[string]interface{})
+// CreateRestoreRequest is the body of POST /restores
+type CreateRestoreRequest struct {
+	Name       string `json:"name" binding:"required"`
+	BackupName string `json:"backupName" binding:"required"`
+	RestoreOptions
+}
 
-	// Add optional fields
-	if len(request.IncludedNamespaces) > 0 {
-		spec["includedNamespaces"] = request.IncludedNamespaces
-	}
-	if len(request.ExcludedNamespaces) > 0 {
-		spec["excludedNamespaces"] = request.ExcludedNamespaces
-	}
-	if len(request.NamespaceMapping) > 0 {
-		spec["namespaceMapping"] = request.NamespaceMapping
+// CreateRestore creates a restore from a named backup. The backup must exist; one that
+// isn't Completed only produces a warning unless ?requireCompleted=true rejects it.
+func (h *VeleroHandler) CreateRestore(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	var request CreateRestoreRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidRequest, err)
+		return
 	}
-	if request.RestorePVs != nil {
-		spec["restorePVs"] = *request.RestorePVs
+
+	if errs := validation.IsDNS1123Subdomain(request.Name); len(errs) > 0 {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidName, fmt.Errorf("%s", strings.Join(errs, "; ")))
+		return
 	}
-	if request.IncludeClusterResources != nil {
-		spec["includeClusterResources"] = *request.IncludeClusterResources
+
+	if request.Hooks != nil {
+		if err := request.Hooks.validate(); err != nil {
+			respondError(c, http.StatusBadRequest, ErrCodeInvalidHooks, err)
+			return
+		}
 	}
 
-	// Create the restore in Kubernetes
-	result, err := h.k8sClient.DynamicClient.
-		Resource(k8s.RestoreGVR).
-		Namespace("velero").
-		Create(h.k8sClient.Context, &unstructured.Unstructured{Object: restore}, metav1.CreateOptions{})
+	if err := validateNamespaceMapping(request.NamespaceMapping); err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidNamespaceMapping, err)
+		return
+	}
 
+	backup, err := k8s.Retry(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.Resource(k8s.BackupGVR).Namespace("velero").Get(ctx, request.BackupName, metav1.GetOptions{})
+	})
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusBadRequest, ErrCodeBackupNotFound, err)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create restore",
-			"details": err.Error(),
-			"restore": request.Name,
-			"backup":  request.BackupName,
-		})
+		respondError(c, http.StatusInternalServerError, ErrCodeBackupGetFailed, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	response := gin.H{
 		"message": "Restore created successfully",
-		"restore": result.GetName(),
 		"backup":  request.BackupName,
 		"status":  "created",
+	}
+
+	phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase")
+	if phase != "Completed" {
+		if phase == "" {
+			phase = "New"
+		}
+		notCompleted := fmt.Errorf("backup %s is in phase %s", request.BackupName, phase)
+		if c.Query("requireCompleted") == "true" {
+			respondError(c, http.StatusBadRequest, ErrCodeBackupNotCompleted, notCompleted)
+			return
+		}
+		response["warning"] = notCompleted.Error() + ", the restore may fail or be incomplete"
+		response["backupPhase"] = phase
+	}
+
+	restore := request.RestoreOptions.restoreObject(request.Name, request.BackupName)
+	h.submitRestore(c, restore, response)
+}
+
+// submitRestore creates a restore, honouring ?dryRun=true, and responds with the given
+// fields plus the name of the created restore
+func (h *VeleroHandler) submitRestore(c *gin.Context, restore map[string]interface{}, response gin.H) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	createOptions, dryRun := createOptionsFor(c)
+	result, err := k8s.RetryCreate(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.
+			Resource(k8s.RestoreGVR).
+			Namespace("velero").
+			Create(ctx, &unstructured.Unstructured{Object: restore}, createOptions)
 	})
+	if !dryRun {
+		h.k8sClient.ListCache.Invalidate(k8s.RestoreGVR)
+	}
+
+	if apierrors.IsAlreadyExists(err) {
+		respondError(c, http.StatusConflict, ErrCodeRestoreExists, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeRestoreCreateFailed, err)
+		return
+	}
+
+	if dryRun {
+		c.JSON(http.StatusOK, gin.H{
+			"message":  "Dry run: the restore was validated but not created",
+			"dryRun":   true,
+			"manifest": result.Object,
+		})
+		return
+	}
+
+	response["restore"] = result.GetName()
+	c.JSON(http.StatusCreated, response)
 }
+
 func (h *VeleroHandler) ListRestores(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	query, err := parseListQuery(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidQuery, err)
+		return
+	}
+	selector, err := parseLabelSelector(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, ErrCodeInvalidQuery, err)
+		return
+	}
+
+	// Check if Velero CRDs exist first
+	_, err = h.k8sClient.Clientset.Discovery().ServerResourcesForGroupVersion("velero.io/v1")
+	if err != nil {
+		respondError(c, http.StatusServiceUnavailable, ErrCodeVeleroNotInstalled, err)
+		return
+	}
+
+	// Get restores from Velero namespace
+	restoreList, err := h.k8sClient.ListCache.ListSelected(ctx, k8s.RestoreGVR, "velero", selector)
+
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeRestoreListFailed, err)
+		return
+	}
+
+	restores := make([]VeleroResource, 0, len(restoreList.Items))
+	for _, restore := range restoreList.Items {
+		restores = append(restores, newVeleroResource(restore, k8s.RestoreCluster(&restore)))
+	}
+	restores = query.applyResources(restores)
+
+	response := RestoreList{Restores: restores, Count: len(restores)}
+	respondJSONWithETag(c, response, response)
+}
+
+func (h *VeleroHandler) ListSchedules(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	query, err := parseListQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	// Check if Velero CRDs exist first
-	_, err := h.k8sClient.Clientset.Discovery().ServerResourcesForGroupVersion("velero.io/v1")
+	_, err = h.k8sClient.Clientset.Discovery().ServerResourcesForGroupVersion("velero.io/v1")
 	if err != nil {
+		logRequestError(c, "Velero not installed or CRDs not found", err)
 		c.JSON(http.StatusServiceUnavailable, gin.H{
 			"error":   "Velero not installed or CRDs not found",
 			"details": err.Error(),
@@ -587,127 +1606,358 @@ func (h *VeleroHandler) ListRestores(c *gin.Context) {
 		return
 	}
 
-	// Get restores from Velero namespace
-	restoreList, err := h.k8sClient.DynamicClient.
-		Resource(k8s.RestoreGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
+	// Get schedules from Velero namespace
+	scheduleList, err := h.k8sClient.ListCache.List(ctx, k8s.ScheduleGVR, "velero")
 
 	if err != nil {
+		logRequestError(c, "Failed to list schedules", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":     "Failed to list restores",
+			"error":     "Failed to list schedules",
 			"details":   err.Error(),
 			"namespace": "velero",
 		})
 		return
 	}
 
-	// Convert to simpler format
-	var restores []map[string]interface{}
-	for _, restore := range restoreList.Items {
-		restoreName := restore.GetName()
-		clusterName := extractClusterFromRestoreName(restoreName, restore.Object)
+	schedules := make([]VeleroResource, 0, len(scheduleList.Items))
+	for _, schedule := range scheduleList.Items {
+		schedules = append(schedules, newVeleroResource(schedule, ""))
+	}
+	schedules = query.applyResources(schedules)
 
-		restoreData := map[string]interface{}{
-			"name":              restoreName,
-			"cluster":           clusterName,
-			"namespace":         restore.GetNamespace(),
-			"creationTimestamp": restore.GetCreationTimestamp(),
-			"labels":            restore.GetLabels(),
+	c.JSON(http.StatusOK, ScheduleList{Schedules: schedules, Count: len(schedules)})
+}
+
+// DescribeSchedule returns a schedule with its full backup template, status and next run
+func (h *VeleroHandler) DescribeSchedule(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	name := c.Param("name")
+
+	schedule, err := h.k8sClient.DynamicClient.
+		Resource(k8s.ScheduleGVR).
+		Namespace("velero").
+		Get(ctx, name, metav1.GetOptions{})
+
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusNotFound, ErrCodeScheduleNotFound, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeScheduleGetFailed, err)
+		return
+	}
+
+	expression, _, _ := unstructured.NestedString(schedule.Object, "spec", "schedule")
+	paused, _, _ := unstructured.NestedBool(schedule.Object, "spec", "paused")
+	phase, _, _ := unstructured.NestedString(schedule.Object, "status", "phase")
+	lastBackup, _, _ := unstructured.NestedString(schedule.Object, "status", "lastBackup")
+	validationErrors, _, _ := unstructured.NestedStringSlice(schedule.Object, "status", "validationErrors")
+
+	response := gin.H{
+		"name":             schedule.GetName(),
+		"namespace":        schedule.GetNamespace(),
+		"metadata":         schedule.Object["metadata"],
+		"spec":             schedule.Object["spec"],
+		"status":           schedule.Object["status"],
+		"phase":            phase,
+		"lastBackup":       lastBackup,
+		"validationErrors": validationErrors,
+		"paused":           paused,
+		"nextRun":          nil,
+	}
+
+	// A paused schedule has no next run
+	if !paused {
+		parsed, err := k8s.ParseSchedule(expression)
+		if err != nil {
+			response["nextRunError"] = fmt.Sprintf("Invalid schedule %q: %v", expression, err)
+		} else if next := parsed.Next(time.Now()); !next.IsZero() {
+			response["nextRun"] = next
 		}
+	}
 
-		// Extract status if available
-		if status, found := restore.Object["status"]; found {
-			restoreData["status"] = status
+	c.JSON(http.StatusOK, response)
+}
+
+// ListScheduleBackups lists the backups a schedule created, newest first
+func (h *VeleroHandler) ListScheduleBackups(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	name := c.Param("name")
+
+	_, err := h.k8sClient.DynamicClient.
+		Resource(k8s.ScheduleGVR).
+		Namespace("velero").
+		Get(ctx, name, metav1.GetOptions{})
+
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusNotFound, ErrCodeScheduleNotFound, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeScheduleGetFailed, err)
+		return
+	}
+
+	backupList, err := h.k8sClient.DynamicClient.
+		Resource(k8s.BackupGVR).
+		Namespace("velero").
+		List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("velero.io/schedule-name=%s", name),
+		})
+
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeBackupListFailed, err)
+		return
+	}
+
+	items := backupList.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].GetCreationTimestamp().Time.After(items[j].GetCreationTimestamp().Time)
+	})
+
+	backups := make([]VeleroResource, 0, len(items))
+	for _, backup := range items {
+		backups = append(backups, backupListEntry(backup))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"schedule": name,
+		"backups":  backups,
+		"count":    len(backups),
+	})
+}
+
+// brokenScheduleGracePeriod is how long a new schedule gets to produce its first backup
+// before it's considered past due. Long enough to cover a daily schedule.
+const brokenScheduleGracePeriod = 25 * time.Hour
+
+// ListBrokenSchedules lists active schedules that are past due but have never produced
+// a successful backup, along with the most likely reason
+func (h *VeleroHandler) ListBrokenSchedules(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	scheduleList, err := h.k8sClient.ListCache.List(ctx, k8s.ScheduleGVR, "velero")
+
+	if err != nil {
+		logRequestError(c, "Failed to list schedules", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list schedules",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	backupList, err := h.k8sClient.DynamicClient.
+		Resource(k8s.BackupGVR).
+		Namespace("velero").
+		List(ctx, metav1.ListOptions{LabelSelector: "velero.io/schedule-name"})
+
+	if err != nil {
+		logRequestError(c, "Failed to list backups", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list backups",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Group produced backups by schedule
+	backupsBySchedule := make(map[string][]unstructured.Unstructured)
+	for _, backup := range backupList.Items {
+		scheduleName := backup.GetLabels()["velero.io/schedule-name"]
+		backupsBySchedule[scheduleName] = append(backupsBySchedule[scheduleName], backup)
+	}
+
+	now := time.Now()
+	broken := []map[string]interface{}{}
+	for _, schedule := range scheduleList.Items {
+		if paused, _, _ := unstructured.NestedBool(schedule.Object, "spec", "paused"); paused {
+			continue
 		}
 
-		// Extract spec if available
-		if spec, found := restore.Object["spec"]; found {
-			restoreData["spec"] = spec
+		lastBackup, _, _ := unstructured.NestedString(schedule.Object, "status", "lastBackup")
+		if lastBackup == "" && now.Sub(schedule.GetCreationTimestamp().Time) < brokenScheduleGracePeriod {
+			continue // Not due yet
+		}
+
+		backups := backupsBySchedule[schedule.GetName()]
+		failedPhases := make(map[string]int)
+		succeeded := false
+		for _, backup := range backups {
+			phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase")
+			switch phase {
+			case "Completed":
+				succeeded = true
+			case "Failed", "PartiallyFailed", "FailedValidation":
+				failedPhases[phase]++
+			}
+		}
+
+		// In-progress backups may still succeed, so only flag schedules whose backups all failed
+		if succeeded || (len(backups) > 0 && len(backups) != sumCounts(failedPhases)) {
+			continue
 		}
 
-		restores = append(restores, restoreData)
+		schedulePhase, _, _ := unstructured.NestedString(schedule.Object, "status", "phase")
+		cronSpec, _, _ := unstructured.NestedString(schedule.Object, "spec", "schedule")
+		validationErrors, _, _ := unstructured.NestedStringSlice(schedule.Object, "status", "validationErrors")
+
+		broken = append(broken, map[string]interface{}{
+			"name":              schedule.GetName(),
+			"schedule":          cronSpec,
+			"phase":             schedulePhase,
+			"creationTimestamp": schedule.GetCreationTimestamp(),
+			"lastBackup":        lastBackup,
+			"backupCount":       len(backups),
+			"failedPhases":      failedPhases,
+			"validationErrors":  validationErrors,
+			"reason":            brokenScheduleReason(validationErrors, backups),
+		})
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"restores": restores,
-		"count":    len(restores),
+		"schedules": broken,
+		"count":     len(broken),
 	})
 }
 
-func (h *VeleroHandler) ListSchedules(c *gin.Context) {
-	// Check if Velero CRDs exist first
-	_, err := h.k8sClient.Clientset.Discovery().ServerResourcesForGroupVersion("velero.io/v1")
-	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error":   "Velero not installed or CRDs not found",
+// brokenScheduleReason explains why a schedule never produced a successful backup
+func brokenScheduleReason(validationErrors []string, backups []unstructured.Unstructured) string {
+	if len(validationErrors) > 0 {
+		return "Schedule failed validation: " + strings.Join(validationErrors, "; ")
+	}
+
+	if len(backups) == 0 {
+		return "Schedule has not produced any backups"
+	}
+
+	// Report the most recent backup's failure
+	latest := backups[0]
+	for _, backup := range backups[1:] {
+		if backup.GetCreationTimestamp().After(latest.GetCreationTimestamp().Time) {
+			latest = backup
+		}
+	}
+
+	if backupErrors, _, _ := unstructured.NestedStringSlice(latest.Object, "status", "validationErrors"); len(backupErrors) > 0 {
+		return fmt.Sprintf("Latest backup %s failed validation: %s", latest.GetName(), strings.Join(backupErrors, "; "))
+	}
+	if failureReason, _, _ := unstructured.NestedString(latest.Object, "status", "failureReason"); failureReason != "" {
+		return fmt.Sprintf("Latest backup %s failed: %s", latest.GetName(), failureReason)
+	}
+
+	phase, _, _ := unstructured.NestedString(latest.Object, "status", "phase")
+	return fmt.Sprintf("All %d backups failed (latest %s is %s)", len(backups), latest.GetName(), phase)
+}
+
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
+
+// ScheduleOptions are schedule spec fields added in newer Velero versions. Older Velero
+// servers drop fields they don't know, so check GET /velero/version before relying on them.
+type ScheduleOptions struct {
+	// Don't run a backup as soon as the schedule is created or unpaused
+	SkipImmediately *bool `json:"skipImmediately,omitempty"`
+	// Make the schedule the owner of its backups, so they're deleted with it
+	UseOwnerReferencesInBackup *bool `json:"useOwnerReferencesInBackup,omitempty"`
+}
+
+// applyTo writes the options that are set into a schedule spec
+func (o *ScheduleOptions) applyTo(spec map[string]interface{}) {
+	if o.SkipImmediately != nil {
+		spec["skipImmediately"] = *o.SkipImmediately
+	}
+	if o.UseOwnerReferencesInBackup != nil {
+		spec["useOwnerReferencesInBackup"] = *o.UseOwnerReferencesInBackup
+	}
+}
+
+// CreateScheduleRequest is the body of POST /schedules
+type CreateScheduleRequest struct {
+	Name               string       `json:"name" binding:"required"`
+	Schedule           string       `json:"schedule" binding:"required"`
+	IncludedNamespaces []string     `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string     `json:"excludedNamespaces,omitempty"`
+	StorageLocation    string       `json:"storageLocation,omitempty"`
+	TTL                string       `json:"ttl,omitempty"`
+	Paused             *bool        `json:"paused,omitempty"`
+	Hooks              *BackupHooks `json:"hooks,omitempty"`
+	// Resource type -> comma-separated names backed up in that order
+	OrderedResources        map[string]string `json:"orderedResources,omitempty"`
+	IncludeClusterResources *bool             `json:"includeClusterResources,omitempty"`
+	ResourcePolicy          *ResourcePolicy   `json:"resourcePolicy,omitempty"`
+	ScheduleOptions
+	ScopedResourceFilters
+	ObjectMetadata
+}
+
+func (h *VeleroHandler) CreateSchedule(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	var request CreateScheduleRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	// Get schedules from Velero namespace
-	scheduleList, err := h.k8sClient.DynamicClient.
-		Resource(k8s.ScheduleGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":     "Failed to list schedules",
-			"details":   err.Error(),
-			"namespace": "velero",
+	if err := request.ObjectMetadata.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid labels or annotations",
+			"details": err.Error(),
 		})
 		return
 	}
 
-	// Convert to simpler format
-	var schedules []map[string]interface{}
-	for _, schedule := range scheduleList.Items {
-		scheduleData := map[string]interface{}{
-			"name":              schedule.GetName(),
-			"namespace":         schedule.GetNamespace(),
-			"creationTimestamp": schedule.GetCreationTimestamp(),
-			"labels":            schedule.GetLabels(),
-		}
-
-		// Extract status if available
-		if status, found := schedule.Object["status"]; found {
-			scheduleData["status"] = status
-		}
-
-		// Extract spec if available
-		if spec, found := schedule.Object["spec"]; found {
-			scheduleData["spec"] = spec
+	if request.Hooks != nil {
+		if err := request.Hooks.validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid backup hooks",
+				"details": err.Error(),
+			})
+			return
 		}
-
-		schedules = append(schedules, scheduleData)
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"schedules": schedules,
-		"count":     len(schedules),
-	})
-}
-func (h *VeleroHandler) CreateSchedule(c *gin.Context) {
-	var request struct {
-		Name               string   `json:"name" binding:"required"`
-		Schedule           string   `json:"schedule" binding:"required"`
-		IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
-		ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
-		StorageLocation    string   `json:"storageLocation,omitempty"`
-		TTL                string   `json:"ttl,omitempty"`
-		Paused             *bool    `json:"paused,omitempty"`
 	}
 
-	if err := c.ShouldBindJSON(&request); err != nil {
+	if err := validateOrderedResources(request.OrderedResources); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
+			"error":   "Invalid orderedResources",
 			"details": err.Error(),
 		})
 		return
 	}
 
+	if request.ResourcePolicy != nil {
+		if err := request.ResourcePolicy.validate(ctx, h); errors.Is(err, errInvalidResourcePolicy) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid resource policy",
+				"details": err.Error(),
+			})
+			return
+		} else if err != nil {
+			logRequestError(c, "Failed to check resource policy", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to check resource policy",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
 	// Set defaults
 	if request.StorageLocation == "" {
 		request.StorageLocation = "default"
@@ -742,19 +1992,45 @@ func (h *VeleroHandler) CreateSchedule(c *gin.Context) {
 	if len(request.ExcludedNamespaces) > 0 {
 		template["excludedNamespaces"] = request.ExcludedNamespaces
 	}
+	if request.Hooks != nil && len(request.Hooks.Resources) > 0 {
+		template["hooks"] = request.Hooks.toSpec()
+	}
+	if len(request.OrderedResources) > 0 {
+		template["orderedResources"] = request.OrderedResources
+	}
+	if request.IncludeClusterResources != nil {
+		template["includeClusterResources"] = *request.IncludeClusterResources
+	}
+	if request.ResourcePolicy != nil {
+		template["resourcePolicy"] = request.ResourcePolicy.toSpec()
+	}
+	request.ScopedResourceFilters.applyTo(template)
+	request.ObjectMetadata.applyTo(schedule["metadata"].(map[string]interface{}))
+	request.ObjectMetadata.applyToTemplate(template)
+
+	if err := validateResourceFilters(template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid resource filters",
+			"details": err.Error(),
+		})
+		return
+	}
 
 	// Add paused status
 	if request.Paused != nil && *request.Paused {
 		schedule["spec"].(map[string]interface{})["paused"] = true
 	}
+	request.ScheduleOptions.applyTo(schedule["spec"].(map[string]interface{}))
 
 	// Create the schedule in Kubernetes
 	result, err := h.k8sClient.DynamicClient.
 		Resource(k8s.ScheduleGVR).
 		Namespace("velero").
-		Create(h.k8sClient.Context, &unstructured.Unstructured{Object: schedule}, metav1.CreateOptions{})
+		Create(ctx, &unstructured.Unstructured{Object: schedule}, metav1.CreateOptions{})
+	h.k8sClient.ListCache.Invalidate(k8s.ScheduleGVR)
 
 	if err != nil {
+		logRequestError(c, "Failed to create schedule", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":    "Failed to create schedule",
 			"details":  err.Error(),
@@ -771,6 +2047,9 @@ func (h *VeleroHandler) CreateSchedule(c *gin.Context) {
 }
 
 func (h *VeleroHandler) DeleteSchedule(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	scheduleName := c.Param("name")
 	if scheduleName == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -783,9 +2062,11 @@ func (h *VeleroHandler) DeleteSchedule(c *gin.Context) {
 	err := h.k8sClient.DynamicClient.
 		Resource(k8s.ScheduleGVR).
 		Namespace("velero").
-		Delete(h.k8sClient.Context, scheduleName, metav1.DeleteOptions{})
+		Delete(ctx, scheduleName, metav1.DeleteOptions{})
+	h.k8sClient.ListCache.Invalidate(k8s.ScheduleGVR)
 
 	if err != nil {
+		logRequestError(c, "Failed to delete schedule", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":    "Failed to delete schedule",
 			"details":  err.Error(),
@@ -800,7 +2081,29 @@ func (h *VeleroHandler) DeleteSchedule(c *gin.Context) {
 	})
 }
 
+// UpdateScheduleRequest is the body of PUT /schedules/:name. Omitted fields are unchanged.
+type UpdateScheduleRequest struct {
+	Name               string       `json:"name,omitempty"`
+	Schedule           string       `json:"schedule,omitempty"`
+	IncludedNamespaces []string     `json:"includedNamespaces,omitempty"`
+	ExcludedNamespaces []string     `json:"excludedNamespaces,omitempty"`
+	StorageLocation    string       `json:"storageLocation,omitempty"`
+	TTL                string       `json:"ttl,omitempty"`
+	Paused             *bool        `json:"paused,omitempty"`
+	Hooks              *BackupHooks `json:"hooks,omitempty"`
+	// Resource type -> comma-separated names backed up in that order
+	OrderedResources map[string]string `json:"orderedResources,omitempty"`
+	// An empty name removes the resource policy
+	ResourcePolicy *ResourcePolicy `json:"resourcePolicy,omitempty"`
+	ScheduleOptions
+	// Omitted filters are unchanged; an empty list removes one
+	ScopedResourceFilters
+}
+
 func (h *VeleroHandler) UpdateSchedule(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	scheduleName := c.Param("name")
 	if scheduleName == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -809,15 +2112,7 @@ func (h *VeleroHandler) UpdateSchedule(c *gin.Context) {
 		return
 	}
 
-	var request struct {
-		Name               string   `json:"name,omitempty"`
-		Schedule           string   `json:"schedule,omitempty"`
-		IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
-		ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
-		StorageLocation    string   `json:"storageLocation,omitempty"`
-		TTL                string   `json:"ttl,omitempty"`
-		Paused             *bool    `json:"paused,omitempty"`
-	}
+	var request UpdateScheduleRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -827,11 +2122,46 @@ func (h *VeleroHandler) UpdateSchedule(c *gin.Context) {
 		return
 	}
 
+	if request.Hooks != nil {
+		if err := request.Hooks.validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid backup hooks",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	if err := validateOrderedResources(request.OrderedResources); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid orderedResources",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if request.ResourcePolicy != nil && request.ResourcePolicy.Name != "" {
+		if err := request.ResourcePolicy.validate(ctx, h); errors.Is(err, errInvalidResourcePolicy) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid resource policy",
+				"details": err.Error(),
+			})
+			return
+		} else if err != nil {
+			logRequestError(c, "Failed to check resource policy", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to check resource policy",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
 	// Get the existing schedule
 	existing, err := h.k8sClient.DynamicClient.
 		Resource(k8s.ScheduleGVR).
 		Namespace("velero").
-		Get(h.k8sClient.Context, scheduleName, metav1.GetOptions{})
+		Get(ctx, scheduleName, metav1.GetOptions{})
 
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
@@ -865,6 +2195,7 @@ func (h *VeleroHandler) UpdateSchedule(c *gin.Context) {
 			delete(spec, "paused")
 		}
 	}
+	request.ScheduleOptions.applyTo(spec)
 
 	// Get or create template object
 	template, ok := spec["template"].(map[string]interface{})
@@ -901,13 +2232,54 @@ func (h *VeleroHandler) UpdateSchedule(c *gin.Context) {
 		}
 	}
 
+	// Update hooks; an empty resource list removes them
+	if request.Hooks != nil {
+		if len(request.Hooks.Resources) > 0 {
+			template["hooks"] = request.Hooks.toSpec()
+		} else {
+			delete(template, "hooks")
+		}
+	}
+
+	// Update ordered resources; an empty map removes them
+	if request.OrderedResources != nil {
+		if len(request.OrderedResources) > 0 {
+			template["orderedResources"] = request.OrderedResources
+		} else {
+			delete(template, "orderedResources")
+		}
+	}
+
+	// Update the resource policy; an empty name removes it
+	if request.ResourcePolicy != nil {
+		if request.ResourcePolicy.Name != "" {
+			template["resourcePolicy"] = request.ResourcePolicy.toSpec()
+		} else {
+			delete(template, "resourcePolicy")
+		}
+	}
+
+	// Update scoped resource filters
+	request.ScopedResourceFilters.applyTo(template)
+
+	if err := validateResourceFilters(template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":    "Invalid resource filters",
+			"details":  err.Error(),
+			"schedule": scheduleName,
+		})
+		return
+	}
+
 	// Update the schedule
 	result, err := h.k8sClient.DynamicClient.
 		Resource(k8s.ScheduleGVR).
 		Namespace("velero").
-		Update(h.k8sClient.Context, existing, metav1.UpdateOptions{})
+		Update(ctx, existing, metav1.UpdateOptions{})
+	h.k8sClient.ListCache.Invalidate(k8s.ScheduleGVR)
 
 	if err != nil {
+		logRequestError(c, "Failed to update schedule", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":    "Failed to update schedule",
 			"details":  err.Error(),
@@ -921,7 +2293,99 @@ func (h *VeleroHandler) UpdateSchedule(c *gin.Context) {
 		"schedule": result.GetName(),
 	})
 }
+
+// PauseSchedule stops a schedule from creating new backups
+func (h *VeleroHandler) PauseSchedule(c *gin.Context) {
+	h.setSchedulePaused(c, true)
+}
+
+// ResumeSchedule lets a paused schedule create backups again
+func (h *VeleroHandler) ResumeSchedule(c *gin.Context) {
+	h.setSchedulePaused(c, false)
+}
+
+// setSchedulePaused sets or clears spec.paused the same way UpdateSchedule does. It is
+// idempotent, and takes over from automatic pausing so the storage location reconciler
+// won't undo the change.
+func (h *VeleroHandler) setSchedulePaused(c *gin.Context, paused bool) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	scheduleName := c.Param("name")
+
+	existing, err := h.k8sClient.DynamicClient.
+		Resource(k8s.ScheduleGVR).
+		Namespace("velero").
+		Get(ctx, scheduleName, metav1.GetOptions{})
+
+	if apierrors.IsNotFound(err) {
+		respondError(c, http.StatusNotFound, ErrCodeScheduleNotFound, err)
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeScheduleGetFailed, err)
+		return
+	}
+
+	wasPaused, _, _ := unstructured.NestedBool(existing.Object, "spec", "paused")
+	annotations := existing.GetAnnotations()
+	_, autoPaused := annotations[pausedForLocationAnnotation]
+
+	if wasPaused != paused || autoPaused {
+		if paused {
+			if err := unstructured.SetNestedField(existing.Object, true, "spec", "paused"); err != nil {
+				respondError(c, http.StatusInternalServerError, ErrCodeScheduleUpdateFailed, err)
+				return
+			}
+		} else {
+			unstructured.RemoveNestedField(existing.Object, "spec", "paused")
+		}
+		delete(annotations, pausedForLocationAnnotation)
+		existing.SetAnnotations(annotations)
+
+		_, err = h.k8sClient.DynamicClient.
+			Resource(k8s.ScheduleGVR).
+			Namespace("velero").
+			Update(ctx, existing, metav1.UpdateOptions{})
+		h.k8sClient.ListCache.Invalidate(k8s.ScheduleGVR)
+
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, ErrCodeScheduleUpdateFailed, err)
+			return
+		}
+	}
+
+	message := "Schedule resumed"
+	if paused {
+		message = "Schedule paused"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  message,
+		"schedule": scheduleName,
+		"paused":   paused,
+		"changed":  wasPaused != paused,
+	})
+}
+
+// scheduleTemplate returns the Backup spec template of a schedule
+func scheduleTemplate(schedule *unstructured.Unstructured) (map[string]interface{}, error) {
+	scheduleSpec, found := schedule.Object["spec"].(map[string]interface{})
+	if !found {
+		return nil, errors.New("schedule has no spec")
+	}
+
+	template, found := scheduleSpec["template"].(map[string]interface{})
+	if !found {
+		return nil, errors.New("schedule template not found")
+	}
+	return template, nil
+}
+
 func (h *VeleroHandler) CreateBackupFromSchedule(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	scheduleName := c.Param("name")
 	if scheduleName == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -934,7 +2398,7 @@ func (h *VeleroHandler) CreateBackupFromSchedule(c *gin.Context) {
 	schedule, err := h.k8sClient.DynamicClient.
 		Resource(k8s.ScheduleGVR).
 		Namespace("velero").
-		Get(h.k8sClient.Context, scheduleName, metav1.GetOptions{})
+		Get(ctx, scheduleName, metav1.GetOptions{})
 
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
@@ -945,20 +2409,11 @@ func (h *VeleroHandler) CreateBackupFromSchedule(c *gin.Context) {
 		return
 	}
 
-	// Extract template from schedule spec
-	scheduleSpec, found := schedule.Object["spec"].(map[string]interface{})
-	if !found {
+	template, err := scheduleTemplate(schedule)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":    "Invalid schedule specification",
-			"schedule": scheduleName,
-		})
-		return
-	}
-
-	template, found := scheduleSpec["template"].(map[string]interface{})
-	if !found {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":    "Schedule template not found",
+			"details":  err.Error(),
 			"schedule": scheduleName,
 		})
 		return
@@ -984,12 +2439,16 @@ func (h *VeleroHandler) CreateBackupFromSchedule(c *gin.Context) {
 	}
 
 	// Create the backup in Kubernetes
-	result, err := h.k8sClient.DynamicClient.
-		Resource(k8s.BackupGVR).
-		Namespace("velero").
-		Create(h.k8sClient.Context, &unstructured.Unstructured{Object: backup}, metav1.CreateOptions{})
+	result, err := k8s.RetryCreate(func() (*unstructured.Unstructured, error) {
+		return h.k8sClient.DynamicClient.
+			Resource(k8s.BackupGVR).
+			Namespace("velero").
+			Create(ctx, &unstructured.Unstructured{Object: backup}, metav1.CreateOptions{})
+	})
+	h.k8sClient.ListCache.Invalidate(k8s.BackupGVR)
 
 	if err != nil {
+		logRequestError(c, "Failed to create backup from schedule", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":    "Failed to create backup from schedule",
 			"details":  err.Error(),
@@ -1009,6 +2468,9 @@ func (h *VeleroHandler) CreateBackupFromSchedule(c *gin.Context) {
 }
 
 func (h *VeleroHandler) CreateCronJob(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	var request struct {
 		Name               string   `json:"name" binding:"required"`
 		Cluster            string   `json:"cluster" binding:"required"`
@@ -1100,9 +2562,11 @@ func (h *VeleroHandler) CreateCronJob(c *gin.Context) {
 	result, err := h.k8sClient.DynamicClient.
 		Resource(k8s.CronJobGVR).
 		Namespace("velero").
-		Create(h.k8sClient.Context, &unstructured.Unstructured{Object: cronJob}, metav1.CreateOptions{})
+		Create(ctx, &unstructured.Unstructured{Object: cronJob}, metav1.CreateOptions{})
+	h.k8sClient.ListCache.Invalidate(k8s.CronJobGVR)
 
 	if err != nil {
+		logRequestError(c, "Failed to create CronJob", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create CronJob",
 			"details": err.Error(),
@@ -1118,13 +2582,23 @@ func (h *VeleroHandler) CreateCronJob(c *gin.Context) {
 }
 
 func (h *VeleroHandler) ListCronJobs(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	query, err := parseListQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
 	// Get cronjobs from Velero namespace
-	cronJobList, err := h.k8sClient.DynamicClient.
-		Resource(k8s.CronJobGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
+	cronJobList, err := h.k8sClient.ListCache.List(ctx, k8s.CronJobGVR, "velero")
 
 	if err != nil {
+		logRequestError(c, "Failed to list cronjobs", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":     "Failed to list cronjobs",
 			"details":   err.Error(),
@@ -1157,9 +2631,17 @@ func (h *VeleroHandler) ListCronJobs(c *gin.Context) {
 			cronJobData["status"] = status
 		}
 
+		// CronJobs have no phase of their own; expose whether they are suspended
+		cronJobData["phase"] = "Active"
+		if suspended, _, _ := unstructured.NestedBool(cronJob.Object, "spec", "suspend"); suspended {
+			cronJobData["phase"] = "Suspended"
+		}
+
 		cronJobs = append(cronJobs, cronJobData)
 	}
 
+	cronJobs = query.apply(cronJobs)
+
 	c.JSON(http.StatusOK, gin.H{
 		"cronjobs": cronJobs,
 		"count":    len(cronJobs),
@@ -1167,6 +2649,9 @@ func (h *VeleroHandler) ListCronJobs(c *gin.Context) {
 }
 
 func (h *VeleroHandler) DeleteCronJob(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	cronJobName := c.Param("name")
 	if cronJobName == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -1178,9 +2663,11 @@ func (h *VeleroHandler) DeleteCronJob(c *gin.Context) {
 	err := h.k8sClient.DynamicClient.
 		Resource(k8s.CronJobGVR).
 		Namespace("velero").
-		Delete(h.k8sClient.Context, cronJobName, metav1.DeleteOptions{})
+		Delete(ctx, cronJobName, metav1.DeleteOptions{})
+	h.k8sClient.ListCache.Invalidate(k8s.CronJobGVR)
 
 	if err != nil {
+		logRequestError(c, "Failed to delete CronJob", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to delete CronJob",
 			"details": err.Error(),
@@ -1195,6 +2682,9 @@ func (h *VeleroHandler) DeleteCronJob(c *gin.Context) {
 }
 
 func (h *VeleroHandler) UpdateCronJob(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	cronJobName := c.Param("name")
 	if cronJobName == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -1219,7 +2709,7 @@ func (h *VeleroHandler) UpdateCronJob(c *gin.Context) {
 	existing, err := h.k8sClient.DynamicClient.
 		Resource(k8s.CronJobGVR).
 		Namespace("velero").
-		Get(h.k8sClient.Context, cronJobName, metav1.GetOptions{})
+		Get(ctx, cronJobName, metav1.GetOptions{})
 
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
@@ -1240,9 +2730,11 @@ func (h *VeleroHandler) UpdateCronJob(c *gin.Context) {
 	result, err := h.k8sClient.DynamicClient.
 		Resource(k8s.CronJobGVR).
 		Namespace("velero").
-		Update(h.k8sClient.Context, existing, metav1.UpdateOptions{})
+		Update(ctx, existing, metav1.UpdateOptions{})
+	h.k8sClient.ListCache.Invalidate(k8s.CronJobGVR)
 
 	if err != nil {
+		logRequestError(c, "Failed to update CronJob", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to update CronJob",
 			"details": err.Error(),
@@ -1257,6 +2749,9 @@ func (h *VeleroHandler) UpdateCronJob(c *gin.Context) {
 }
 
 func (h *VeleroHandler) TriggerCronJob(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	cronJobName := c.Param("name")
 	if cronJobName == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -1269,7 +2764,7 @@ func (h *VeleroHandler) TriggerCronJob(c *gin.Context) {
 	cronJob, err := h.k8sClient.DynamicClient.
 		Resource(k8s.CronJobGVR).
 		Namespace("velero").
-		Get(h.k8sClient.Context, cronJobName, metav1.GetOptions{})
+		Get(ctx, cronJobName, metav1.GetOptions{})
 
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
@@ -1285,22 +2780,45 @@ func (h *VeleroHandler) TriggerCronJob(c *gin.Context) {
 	// Create a Job from the CronJob template
 	jobName := fmt.Sprintf("%s-manual-%d", cronJobName, time.Now().Unix())
 
-	// Get job template from CronJob spec
-	spec, _ := cronJob.Object["spec"].(map[string]interface{})
-	jobTemplate, _ := spec["jobTemplate"].(map[string]interface{})
-	jobSpec, _ := jobTemplate["spec"].(map[string]interface{})
+	// Get job template from CronJob spec; without one the Job would have no pods
+	jobSpec, found, _ := unstructured.NestedMap(cronJob.Object, "spec", "jobTemplate", "spec")
+	if !found {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "CronJob has no job template",
+			"cronJob": cronJobName,
+		})
+		return
+	}
+
+	// Keep the template's labels so the Job looks like a scheduled run
+	labels := map[string]interface{}{}
+	templateLabels, _, _ := unstructured.NestedStringMap(cronJob.Object, "spec", "jobTemplate", "metadata", "labels")
+	for key, value := range templateLabels {
+		labels[key] = value
+	}
+	labels["velero.io/cluster"] = clusterName
+	labels["velero.io/triggered"] = "manual"
+	labels["cronjob-name"] = cronJobName
 
-	// Create Job manifest
+	// Create Job manifest, owned by the CronJob like `kubectl create job --from=cronjob/...`
 	job := map[string]interface{}{
 		"apiVersion": "batch/v1",
 		"kind":       "Job",
 		"metadata": map[string]interface{}{
 			"name":      jobName,
 			"namespace": "velero",
-			"labels": map[string]interface{}{
-				"velero.io/cluster":   clusterName,
-				"velero.io/triggered": "manual",
-				"cronjob-name":        cronJobName,
+			"labels":    labels,
+			"annotations": map[string]interface{}{
+				"cronjob.kubernetes.io/instantiate": "manual",
+			},
+			"ownerReferences": []interface{}{
+				map[string]interface{}{
+					"apiVersion": "batch/v1",
+					"kind":       "CronJob",
+					"name":       cronJob.GetName(),
+					"uid":        string(cronJob.GetUID()),
+					"controller": true,
+				},
 			},
 		},
 		"spec": jobSpec,
@@ -1310,9 +2828,10 @@ func (h *VeleroHandler) TriggerCronJob(c *gin.Context) {
 	result, err := h.k8sClient.DynamicClient.
 		Resource(k8s.JobGVR).
 		Namespace("velero").
-		Create(h.k8sClient.Context, &unstructured.Unstructured{Object: job}, metav1.CreateOptions{})
+		Create(ctx, &unstructured.Unstructured{Object: job}, metav1.CreateOptions{})
 
 	if err != nil {
+		logRequestError(c, "Failed to trigger CronJob", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to trigger CronJob",
 			"details": err.Error(),
@@ -1341,8 +2860,6 @@ func extractClusterFromCronJobName(cronJobName string) string {
 	return "unknown"
 }
 
-// extractClusterFromBackupName parses cluster name from backup naming convention
-// Example: "core-cl1-daily-backup-20250821020001" -> "core-cl1"
 func extractResourceCounts(status map[string]interface{}) map[string]interface{} {
 	resourceCounts := make(map[string]interface{})
 
@@ -1384,40 +2901,6 @@ func extractResourceCounts(status map[string]interface{}) map[string]interface{}
 	return resourceCounts
 }
 
-func extractClusterFromBackupName(backupName string) string {
-	parts := strings.Split(backupName, "-daily-backup-")
-	if len(parts) >= 2 {
-		return parts[0]
-	}
-
-	// Fallback for other naming patterns
-	if strings.Contains(backupName, "-centralized-") {
-		parts = strings.Split(backupName, "-centralized-")
-		if len(parts) >= 2 {
-			return parts[0]
-		}
-	}
-
-	return "unknown"
-}
-
-// extractClusterFromRestoreName parses cluster name from restore name or backup reference
-func extractClusterFromRestoreName(restoreName string, restoreObj map[string]interface{}) string {
-	// Try parsing from restore name first
-	if cluster := extractClusterFromBackupName(restoreName); cluster != "management" && cluster != "unknown" {
-		return cluster
-	}
-
-	// Try extracting from backup name in spec
-	if spec, found := restoreObj["spec"].(map[string]interface{}); found {
-		if backupName, found := spec["backupName"].(string); found {
-			return extractClusterFromBackupName(backupName)
-		}
-	}
-
-	return "management"
-}
-
 // isOnlySMBStorageFailure checks if all errors are related to missing SMB storage class
 func (h *VeleroHandler) isOnlySMBStorageFailure(backupObj map[string]interface{}) bool {
 	// Extract errors from backup status
@@ -1446,165 +2929,98 @@ func (h *VeleroHandler) isOnlySMBStorageFailure(backupObj map[string]interface{}
 func (h *VeleroHandler) GetClusterDetails(c *gin.Context) {
 	clusterName := c.Param("cluster")
 
-	// Get CronJob for this cluster to extract configuration
-	cronJobList, err := h.k8sClient.DynamicClient.
-		Resource(k8s.CronJobGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
-
+	cluster, err := h.clusterDetails(clusterName)
 	if err != nil {
+		logRequestError(c, "Failed to get cluster details", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to get cluster details",
 			"details": err.Error(),
 		})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{
+		"cluster":     cluster.Name,
+		"description": cluster.Description,
+		"secretName":  cluster.SecretName,
+		"backupCount": cluster.BackupCount,
+		"lastBackup":  cluster.LastBackup,
+		"cronJob":     cluster.CronJob != "",
+	})
+}
 
-	// Find the CronJob for this cluster
-	var clusterCronJob map[string]interface{}
-	for _, cronJob := range cronJobList.Items {
-		if extractClusterFromCronJobName(cronJob.GetName()) == clusterName {
-			clusterCronJob = cronJob.Object
-			break
-		}
-	}
-
-	// Extract secret name from CronJob spec if available
-	secretName := fmt.Sprintf("%s-credentials", clusterName) // Default pattern
-	if clusterCronJob != nil {
-		if spec, ok := clusterCronJob["spec"].(map[string]interface{}); ok {
-			if jobTemplate, ok := spec["jobTemplate"].(map[string]interface{}); ok {
-				if jobSpec, ok := jobTemplate["spec"].(map[string]interface{}); ok {
-					if template, ok := jobSpec["template"].(map[string]interface{}); ok {
-						if podSpec, ok := template["spec"].(map[string]interface{}); ok {
-							if volumes, ok := podSpec["volumes"].([]interface{}); ok && len(volumes) > 0 {
-								if volume, ok := volumes[0].(map[string]interface{}); ok {
-									if secret, ok := volume["secret"].(map[string]interface{}); ok {
-										if name, ok := secret["secretName"].(string); ok {
-											secretName = name
-										}
-									}
-								}
-							}
-						}
-					}
-				}
-			}
-		}
+func (h *VeleroHandler) ListClusters(c *gin.Context) {
+	clusters, err := h.discoverClusters()
+	if err != nil {
+		logRequestError(c, "Failed to list cronjobs", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list cronjobs",
+			"details": err.Error(),
+		})
+		return
 	}
 
-	// Get recent backups for this cluster
-	backupList, _ := h.k8sClient.DynamicClient.
-		Resource(k8s.BackupGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
-
-	var lastBackup interface{}
-	backupCount := 0
+	c.JSON(http.StatusOK, ClusterList{Clusters: clusters, Count: len(clusters)})
+}
 
-	for _, backup := range backupList.Items {
-		if extractClusterFromBackupName(backup.GetName()) == clusterName {
-			backupCount++
-			if lastBackup == nil {
-				lastBackup = backup.GetCreationTimestamp()
-			}
-		}
-	}
+func (h *VeleroHandler) ListBackupsByCluster(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
 
-	c.JSON(http.StatusOK, gin.H{
-		"cluster":     clusterName,
-		"secretName":  secretName,
-		"backupCount": backupCount,
-		"lastBackup":  lastBackup,
-		"cronJob":     clusterCronJob != nil,
-	})
-}
+	clusterName := c.Param("cluster")
+	if clusterName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "cluster name is required",
+		})
+		return
+	}
 
-func (h *VeleroHandler) ListClusters(c *gin.Context) {
-	// Get all CronJobs to identify clusters
-	cronJobList, err := h.k8sClient.DynamicClient.
-		Resource(k8s.CronJobGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
+	clusterBackups, err := h.backupsForCluster(ctx, clusterName)
 
 	if err != nil {
+		logRequestError(c, "Failed to list backups", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to list cronjobs",
+			"error":   "Failed to list backups",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	// Build cluster map from CronJobs first
-	clusterMap := make(map[string]map[string]interface{})
-
-	for _, cronJob := range cronJobList.Items {
-		clusterName := extractClusterFromCronJobName(cronJob.GetName())
-		if clusterName != "unknown" && clusterName != "" {
-			// Get description from our in-memory store
-			h.mutex.RLock()
-			description := h.clusterDescriptions[clusterName]
-			h.mutex.RUnlock()
-
-			clusterMap[clusterName] = map[string]interface{}{
-				"name":        clusterName,
-				"backupCount": 0,
-				"lastBackup":  nil,
-				"description": description,
-			}
+	var backups []map[string]interface{}
+	for _, backup := range clusterBackups {
+		backupData := map[string]interface{}{
+			"name":              backup.GetName(),
+			"cluster":           clusterName,
+			"namespace":         backup.GetNamespace(),
+			"creationTimestamp": backup.GetCreationTimestamp(),
+			"labels":            backup.GetLabels(),
 		}
-	}
-
-	// Try to get backups (but don't fail if they don't exist)
-	backupList, err := h.k8sClient.DynamicClient.
-		Resource(k8s.BackupGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
-
-	if err == nil {
-
-		// Add backup counts and last backup times
-		for _, backup := range backupList.Items {
-			clusterName := extractClusterFromBackupName(backup.GetName())
-			if cluster, exists := clusterMap[clusterName]; exists {
-				cluster["backupCount"] = cluster["backupCount"].(int) + 1
 
-				backupTime := backup.GetCreationTimestamp()
-				if cluster["lastBackup"] == nil || backupTime.After(cluster["lastBackup"].(metav1.Time).Time) {
-					cluster["lastBackup"] = backupTime
-				}
-			}
+		if status, found := backup.Object["status"]; found {
+			backupData["status"] = status
+		}
+		if spec, found := backup.Object["spec"]; found {
+			backupData["spec"] = spec
 		}
-	}
 
-	// Convert map to slice
-	clusters := make([]map[string]interface{}, 0, len(clusterMap))
-	for _, cluster := range clusterMap {
-		clusters = append(clusters, cluster)
+		backups = append(backups, backupData)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"clusters": clusters,
-		"count":    len(clusters),
+		"cluster": clusterName,
+		"backups": backups,
+		"count":   len(backups),
 	})
 }
 
-func (h *VeleroHandler) ListBackupsByCluster(c *gin.Context) {
-	clusterName := c.Param("cluster")
-	if clusterName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "cluster name is required",
-		})
-		return
-	}
+// GetBackupsSummary returns per-cluster backup counts broken down by phase, computed in one pass
+func (h *VeleroHandler) GetBackupsSummary(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
 
-	// Get all backups
-	backupList, err := h.k8sClient.DynamicClient.
-		Resource(k8s.BackupGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
+	backupList, err := h.k8sClient.ListCache.List(ctx, k8s.BackupGVR, "velero")
 
 	if err != nil {
+		logRequestError(c, "Failed to list backups", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to list backups",
 			"details": err.Error(),
@@ -1612,44 +3028,69 @@ func (h *VeleroHandler) ListBackupsByCluster(c *gin.Context) {
 		return
 	}
 
-	// Filter by cluster
-	var backups []map[string]interface{}
+	type clusterSummary struct {
+		Cluster        string       `json:"cluster"`
+		Total          int          `json:"total"`
+		Completed      int          `json:"completed"`
+		Failed         int          `json:"failed"`
+		InProgress     int          `json:"inProgress"`
+		LastBackupTime *metav1.Time `json:"lastBackupTime"`
+	}
+
+	summaries := make(map[string]*clusterSummary)
 	for _, backup := range backupList.Items {
-		if extractClusterFromBackupName(backup.GetName()) == clusterName {
-			backupData := map[string]interface{}{
-				"name":              backup.GetName(),
-				"cluster":           clusterName,
-				"namespace":         backup.GetNamespace(),
-				"creationTimestamp": backup.GetCreationTimestamp(),
-				"labels":            backup.GetLabels(),
-			}
+		clusterName := k8s.BackupCluster(&backup)
 
-			if status, found := backup.Object["status"]; found {
-				backupData["status"] = status
-			}
-			if spec, found := backup.Object["spec"]; found {
-				backupData["spec"] = spec
-			}
+		summary, exists := summaries[clusterName]
+		if !exists {
+			summary = &clusterSummary{Cluster: clusterName}
+			summaries[clusterName] = summary
+		}
+		summary.Total++
+
+		phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase")
+		switch phase {
+		case "Completed":
+			summary.Completed++
+		case "Failed", "PartiallyFailed", "FailedValidation":
+			summary.Failed++
+		case "", "New", "InProgress", "WaitingForPluginOperations", "WaitingForPluginOperationsPartiallyFailed",
+			"Finalizing", "FinalizingPartiallyFailed":
+			summary.InProgress++
+		}
 
-			backups = append(backups, backupData)
+		creationTime := backup.GetCreationTimestamp()
+		if summary.LastBackupTime == nil || creationTime.After(summary.LastBackupTime.Time) {
+			summary.LastBackupTime = &creationTime
 		}
 	}
 
+	result := make([]*clusterSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Cluster < result[j].Cluster
+	})
+
 	c.JSON(http.StatusOK, gin.H{
-		"cluster": clusterName,
-		"backups": backups,
-		"count":   len(backups),
+		"clusters": result,
+		"count":    len(result),
 	})
 }
 
 func (h *VeleroHandler) ListStorageLocations(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	// Get storage locations from Velero namespace
 	storageList, err := h.k8sClient.DynamicClient.
 		Resource(k8s.BackupStorageLocationGVR).
 		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
+		List(ctx, metav1.ListOptions{})
 
 	if err != nil {
+		logRequestError(c, "Failed to list storage locations", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to list storage locations",
 			"details": err.Error(),
@@ -1674,14 +3115,43 @@ func (h *VeleroHandler) ListStorageLocations(c *gin.Context) {
 	})
 }
 
+// StorageCredential selects a key of a secret in the velero namespace holding the cloud
+// credentials of one storage location, so locations sharing a provider can use
+// different accounts
+type StorageCredential struct {
+	Name string `json:"name" binding:"required"`
+	Key  string `json:"key" binding:"required"`
+}
+
+// validate checks that the referenced secret and key exist
+func (cred *StorageCredential) validate(ctx context.Context, h *VeleroHandler) (int, error) {
+	secret, err := h.k8sClient.Clientset.CoreV1().
+		Secrets("velero").
+		Get(ctx, cred.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return http.StatusBadRequest, fmt.Errorf("credential secret %s not found in the velero namespace", cred.Name)
+	}
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to get credential secret %s: %w", cred.Name, err)
+	}
+	if _, ok := secret.Data[cred.Key]; !ok {
+		return http.StatusBadRequest, fmt.Errorf("credential secret %s has no key %s", cred.Name, cred.Key)
+	}
+	return http.StatusOK, nil
+}
+
 func (h *VeleroHandler) CreateStorageLocation(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	var request struct {
-		Name     string            `json:"name" binding:"required"`
-		Provider string            `json:"provider" binding:"required"`
-		Bucket   string            `json:"bucket" binding:"required"`
-		Region   string            `json:"region,omitempty"`
-		Prefix   string            `json:"prefix,omitempty"`
-		Config   map[string]string `json:"config,omitempty"`
+		Name       string             `json:"name" binding:"required"`
+		Provider   string             `json:"provider" binding:"required"`
+		Bucket     string             `json:"bucket" binding:"required"`
+		Region     string             `json:"region,omitempty"`
+		Prefix     string             `json:"prefix,omitempty"`
+		Config     map[string]string  `json:"config,omitempty"`
+		Credential *StorageCredential `json:"credential,omitempty"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -1692,6 +3162,16 @@ func (h *VeleroHandler) CreateStorageLocation(c *gin.Context) {
 		return
 	}
 
+	if request.Credential != nil {
+		if status, err := request.Credential.validate(ctx, h); err != nil {
+			c.JSON(status, gin.H{
+				"error":   "Invalid credential",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
 	// Create BackupStorageLocation object
 	storageLocation := map[string]interface{}{
 		"apiVersion": "velero.io/v1",
@@ -1714,13 +3194,22 @@ func (h *VeleroHandler) CreateStorageLocation(c *gin.Context) {
 		storageLocation["spec"].(map[string]interface{})["config"] = request.Config
 	}
 
+	// Without a credential Velero falls back to the server's default credentials
+	if request.Credential != nil {
+		storageLocation["spec"].(map[string]interface{})["credential"] = map[string]interface{}{
+			"name": request.Credential.Name,
+			"key":  request.Credential.Key,
+		}
+	}
+
 	// Create the storage location in Kubernetes
 	result, err := h.k8sClient.DynamicClient.
 		Resource(k8s.BackupStorageLocationGVR).
 		Namespace("velero").
-		Create(h.k8sClient.Context, &unstructured.Unstructured{Object: storageLocation}, metav1.CreateOptions{})
+		Create(ctx, &unstructured.Unstructured{Object: storageLocation}, metav1.CreateOptions{})
 
 	if err != nil {
+		logRequestError(c, "Failed to create storage location", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create storage location",
 			"details": err.Error(),
@@ -1735,6 +3224,9 @@ func (h *VeleroHandler) CreateStorageLocation(c *gin.Context) {
 }
 
 func (h *VeleroHandler) DeleteStorageLocation(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	locationName := c.Param("name")
 	if locationName == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -1754,9 +3246,10 @@ func (h *VeleroHandler) DeleteStorageLocation(c *gin.Context) {
 	err := h.k8sClient.DynamicClient.
 		Resource(k8s.BackupStorageLocationGVR).
 		Namespace("velero").
-		Delete(h.k8sClient.Context, locationName, metav1.DeleteOptions{})
+		Delete(ctx, locationName, metav1.DeleteOptions{})
 
 	if err != nil {
+		logRequestError(c, "Failed to delete storage location", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to delete storage location",
 			"details": err.Error(),
@@ -1770,7 +3263,112 @@ func (h *VeleroHandler) DeleteStorageLocation(c *gin.Context) {
 	})
 }
 
+// RecountStorageLocationBackups counts the backups known for a storage location, waits
+// a while and counts again. Velero pulls backups from object storage on its own backup
+// sync period, so this reports what that sync picked up rather than triggering one.
+func (h *VeleroHandler) RecountStorageLocationBackups(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	locationName := c.Param("name")
+	if locationName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Storage location name is required",
+		})
+		return
+	}
+
+	if _, err := h.k8sClient.DynamicClient.
+		Resource(k8s.BackupStorageLocationGVR).
+		Namespace("velero").
+		Get(ctx, locationName, metav1.GetOptions{}); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Storage location not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	before, err := h.countBackupsForLocation(ctx, locationName)
+	if err != nil {
+		logRequestError(c, "Failed to list backups", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list backups",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Wait before recounting, but stop as soon as the client goes away
+	timer := time.NewTimer(storageLocationRecountWait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-c.Request.Context().Done():
+		return
+	}
+
+	// The wait used up part of the request timeout, so the recount gets a fresh one
+	recountCtx, recountCancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer recountCancel()
+
+	location, err := h.k8sClient.DynamicClient.
+		Resource(k8s.BackupStorageLocationGVR).
+		Namespace("velero").
+		Get(recountCtx, locationName, metav1.GetOptions{})
+	if err != nil {
+		logRequestError(c, "Failed to get storage location", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get storage location",
+			"details": err.Error(),
+		})
+		return
+	}
+	lastSynced, _, _ := unstructured.NestedString(location.Object, "status", "lastSyncedTime")
+
+	after, err := h.countBackupsForLocation(recountCtx, locationName)
+	if err != nil {
+		logRequestError(c, "Failed to list backups", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list backups",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Storage location backups recounted",
+		"location":       locationName,
+		"lastSyncedTime": lastSynced,
+		"backupsBefore":  before,
+		"backupsAfter":   after,
+		"newBackups":     after - before,
+	})
+}
+
+// storageLocationRecountWait is how long RecountStorageLocationBackups waits before recounting
+var storageLocationRecountWait = 10 * time.Second
+
+// countBackupsForLocation counts the backups Velero has attributed to a storage location
+func (h *VeleroHandler) countBackupsForLocation(ctx context.Context, locationName string) (int, error) {
+	backupList, err := h.k8sClient.DynamicClient.
+		Resource(k8s.BackupGVR).
+		Namespace("velero").
+		List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("velero.io/storage-location=%s", locationName),
+		})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return len(backupList.Items), nil
+}
+
 func (h *VeleroHandler) AddCluster(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	var request struct {
 		Name            string `json:"name" binding:"required"`
 		APIEndpoint     string `json:"apiEndpoint" binding:"required"`
@@ -1779,6 +3377,11 @@ func (h *VeleroHandler) AddCluster(c *gin.Context) {
 		TTL             string `json:"ttl"`
 		Token           string `json:"token" binding:"required"`
 		CACert          string `json:"caCert" binding:"required"`
+		// Namespaces to leave out of the backup; nil uses the configured defaults, which
+		// include velero-manager's own namespace, and [] backs up every namespace
+		ExcludedNamespaces []string `json:"excludedNamespaces"`
+		// Optional environment used to group clusters (prod, staging, dev, ...)
+		Environment string `json:"environment"`
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -1796,6 +3399,16 @@ func (h *VeleroHandler) AddCluster(c *gin.Context) {
 	if request.TTL == "" {
 		request.TTL = "720h"
 	}
+	if request.ExcludedNamespaces == nil {
+		request.ExcludedNamespaces = config.GetBackupConfig().DefaultExcludedNamespaces
+	}
+	if err := validateExcludedNamespaces(request.ExcludedNamespaces); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid excluded namespaces",
+			"details": err.Error(),
+		})
+		return
+	}
 
 	// Create Secret for cluster credentials
 	secretName := fmt.Sprintf("%s-sa-token", request.Name)
@@ -1835,13 +3448,20 @@ func (h *VeleroHandler) AddCluster(c *gin.Context) {
 		},
 	}
 
+	if request.Environment != "" {
+		secret["metadata"].(map[string]interface{})["annotations"] = map[string]interface{}{
+			k8s.ClusterEnvironmentAnnotation: request.Environment,
+		}
+	}
+
 	// Create the Secret
 	_, err := h.k8sClient.DynamicClient.
 		Resource(k8s.SecretGVR).
 		Namespace("velero").
-		Create(h.k8sClient.Context, &unstructured.Unstructured{Object: secret}, metav1.CreateOptions{})
+		Create(ctx, &unstructured.Unstructured{Object: secret}, metav1.CreateOptions{})
 
 	if err != nil {
+		logRequestError(c, "Failed to create secret", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create secret",
 			"details": err.Error(),
@@ -1876,19 +3496,7 @@ func (h *VeleroHandler) AddCluster(c *gin.Context) {
 									"command": []string{
 										"/bin/sh",
 										"-c",
-										fmt.Sprintf(`kubectl --server=$SERVER --token=$TOKEN --certificate-authority=/var/run/secrets/kubernetes.io/serviceaccount/ca.crt \
-create -f - <<EOF
-apiVersion: velero.io/v1
-kind: Backup
-metadata:
-  name: %s-$(date +%%Y%%m%%d%%H%%M%%S)
-  namespace: velero
-spec:
-  ttl: %s
-  storageLocation: %s
-  includedNamespaces:
-  - "*"
-EOF`, request.Name, request.TTL, request.StorageLocation),
+										buildClusterBackupCommand(request.Name, request.TTL, request.StorageLocation, request.ExcludedNamespaces),
 									},
 									"env": []map[string]interface{}{
 										{
@@ -1945,15 +3553,17 @@ EOF`, request.Name, request.TTL, request.StorageLocation),
 	_, err = h.k8sClient.DynamicClient.
 		Resource(k8s.CronJobGVR).
 		Namespace("velero").
-		Create(h.k8sClient.Context, &unstructured.Unstructured{Object: cronJob}, metav1.CreateOptions{})
+		Create(ctx, &unstructured.Unstructured{Object: cronJob}, metav1.CreateOptions{})
+	h.k8sClient.ListCache.Invalidate(k8s.CronJobGVR)
 
 	if err != nil {
 		// Try to clean up the secret if CronJob creation failed
 		h.k8sClient.DynamicClient.
 			Resource(k8s.SecretGVR).
 			Namespace("velero").
-			Delete(h.k8sClient.Context, secretName, metav1.DeleteOptions{})
+			Delete(ctx, secretName, metav1.DeleteOptions{})
 
+		logRequestError(c, "Failed to create CronJob", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create CronJob",
 			"details": err.Error(),
@@ -1962,14 +3572,61 @@ EOF`, request.Name, request.TTL, request.StorageLocation),
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "Cluster added successfully",
-		"cluster": request.Name,
-		"secret":  secretName,
-		"cronJob": cronJobName,
+		"message":            "Cluster added successfully",
+		"cluster":            request.Name,
+		"secret":             secretName,
+		"cronJob":            cronJobName,
+		"excludedNamespaces": request.ExcludedNamespaces,
+		"environment":        request.Environment,
 	})
 }
 
+// validateExcludedNamespaces checks that each entry is a namespace name, optionally with
+// Velero's "*" wildcards, since the entries end up in the CronJob's shell command
+func validateExcludedNamespaces(namespaces []string) error {
+	for _, ns := range namespaces {
+		if ns == "*" {
+			continue
+		}
+		if errs := validation.IsDNS1123Label(strings.ReplaceAll(ns, "*", "x")); len(errs) > 0 {
+			return fmt.Errorf("excludedNamespaces entry %q: %s", ns, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// buildClusterBackupCommand renders the shell command the cluster CronJob runs to create a Velero backup.
+// The manifest heredoc is quoted so the shell never expands anything in it; only the
+// timestamp placeholder is filled in, by sed.
+func buildClusterBackupCommand(clusterName, ttl, storageLocation string, excludedNamespaces []string) string {
+	var excluded strings.Builder
+	if len(excludedNamespaces) > 0 {
+		excluded.WriteString("\n  excludedNamespaces:")
+		for _, ns := range excludedNamespaces {
+			excluded.WriteString(fmt.Sprintf("\n  - %q", ns))
+		}
+	}
+
+	return fmt.Sprintf(`sed "s/@TIMESTAMP@/$(date +%%Y%%m%%d%%H%%M%%S)/" <<'EOF' | \
+kubectl --server=$SERVER --token=$TOKEN --certificate-authority=/var/run/secrets/kubernetes.io/serviceaccount/ca.crt \
+create -f -
+apiVersion: velero.io/v1
+kind: Backup
+metadata:
+  name: %s-@TIMESTAMP@
+  namespace: velero
+spec:
+  ttl: %s
+  storageLocation: %s
+  includedNamespaces:
+  - "*"%s
+EOF`, clusterName, ttl, storageLocation, excluded.String())
+}
+
 func (h *VeleroHandler) GetClusterHealth(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	clusterName := c.Param("cluster")
 	if clusterName == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -1979,8 +3636,9 @@ func (h *VeleroHandler) GetClusterHealth(c *gin.Context) {
 	}
 
 	// Get detailed cluster health metrics
-	health, err := h.calculateClusterHealth(clusterName)
+	health, err := h.calculateClusterHealth(ctx, clusterName)
 	if err != nil {
+		logRequestError(c, "Failed to check cluster health", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to check cluster health",
 			"details": err.Error(),
@@ -1991,35 +3649,28 @@ func (h *VeleroHandler) GetClusterHealth(c *gin.Context) {
 	c.JSON(http.StatusOK, health)
 }
 
-func (h *VeleroHandler) calculateClusterHealth(clusterName string) (map[string]interface{}, error) {
-	// Get all backups for this cluster
-	backupList, err := h.k8sClient.DynamicClient.
-		Resource(k8s.BackupGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
+func (h *VeleroHandler) calculateClusterHealth(ctx context.Context, clusterName string) (map[string]interface{}, error) {
+	backups, err := h.backupsForCluster(ctx, clusterName)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to list backups: %w", err)
 	}
 
 	var (
-		totalBackups      int
-		successfulBackups int
-		failedBackups     int
-		lastSuccessful    interface{}
-		lastFailed        interface{}
-		recentBackups     []map[string]interface{}
-		lastBackup        interface{}
+		totalBackups           int
+		successfulBackups      int
+		failedBackups          int
+		partiallyFailedBackups int
+		lastSuccessful         interface{}
+		lastFailed             interface{}
+		recentBackups          []map[string]interface{}
+		lastBackup             interface{}
 	)
 
 	now := time.Now()
 	lastWeek := now.Add(-7 * 24 * time.Hour)
 
-	for _, backup := range backupList.Items {
-		if extractClusterFromBackupName(backup.GetName()) != clusterName {
-			continue
-		}
-
+	for _, backup := range backups {
 		totalBackups++
 
 		// Get backup status
@@ -2048,7 +3699,7 @@ func (h *VeleroHandler) calculateClusterHealth(clusterName string) (map[string]i
 					lastSuccessful = creationTime
 				}
 			} else {
-				failedBackups++
+				partiallyFailedBackups++
 				if lastFailed == nil || creationTime.After(lastFailed.(metav1.Time).Time) {
 					lastFailed = creationTime
 				}
@@ -2071,23 +3722,14 @@ func (h *VeleroHandler) calculateClusterHealth(clusterName string) (map[string]i
 	}
 
 	// Get restore information for this cluster
-	restoreList, err := h.k8sClient.DynamicClient.
-		Resource(k8s.RestoreGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
+	restores, err := h.restoresForCluster(ctx, clusterName)
 
 	totalRestores := 0
 	successfulRestores := 0
 	failedRestores := 0
 
 	if err == nil {
-		for _, restore := range restoreList.Items {
-			// Check if restore is from a backup of this cluster
-			backupName, found, _ := unstructured.NestedString(restore.Object, "spec", "backupName")
-			if !found || extractClusterFromBackupName(backupName) != clusterName {
-				continue
-			}
-
+		for _, restore := range restores {
 			totalRestores++
 			status, found, _ := unstructured.NestedString(restore.Object, "status", "phase")
 			if found {
@@ -2105,9 +3747,9 @@ func (h *VeleroHandler) calculateClusterHealth(clusterName string) (map[string]i
 	status := "healthy"
 	if totalBackups == 0 {
 		status = "no-backups"
-	} else if failedBackups > 0 && successfulBackups == 0 {
+	} else if failedBackups > 0 && successfulBackups == 0 && partiallyFailedBackups == 0 {
 		status = "critical"
-	} else if float64(failedBackups)/float64(totalBackups) > 0.3 {
+	} else if float64(failedBackups+partiallyFailedBackups)/float64(totalBackups) > 0.3 {
 		status = "warning"
 	}
 
@@ -2126,13 +3768,14 @@ func (h *VeleroHandler) calculateClusterHealth(clusterName string) (map[string]i
 		"cluster": clusterName,
 		"status":  status,
 		"backups": map[string]interface{}{
-			"total":          totalBackups,
-			"successful":     successfulBackups,
-			"failed":         failedBackups,
-			"successRate":    backupSuccessRate,
-			"lastSuccessful": lastSuccessful,
-			"lastFailed":     lastFailed,
-			"last":           lastBackup,
+			"total":           totalBackups,
+			"successful":      successfulBackups,
+			"failed":          failedBackups,
+			"partiallyFailed": partiallyFailedBackups,
+			"successRate":     backupSuccessRate,
+			"lastSuccessful":  lastSuccessful,
+			"lastFailed":      lastFailed,
+			"last":            lastBackup,
 		},
 		"restores": map[string]interface{}{
 			"total":       totalRestores,
@@ -2145,47 +3788,150 @@ func (h *VeleroHandler) calculateClusterHealth(clusterName string) (map[string]i
 	}, nil
 }
 
-// getClusterList returns list of clusters based on CronJobs and backups
-func (h *VeleroHandler) getClusterList() ([]map[string]interface{}, error) {
-	// Get all CronJobs to identify clusters
-	cronJobList, err := h.k8sClient.DynamicClient.
-		Resource(k8s.CronJobGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
+// GetClusterDurations returns p50/p95/p99 durations of completed backups and restores for a cluster
+func (h *VeleroHandler) GetClusterDurations(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
+	clusterName := c.Param("cluster")
+	if clusterName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "cluster name is required",
+		})
+		return
+	}
+
+	// Only operations started within the window are considered (default 30 days)
+	window := 30 * 24 * time.Hour
+	if w := c.Query("window"); w != "" {
+		parsed, err := time.ParseDuration(w)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid window",
+				"details": "window must be a positive duration such as 168h",
+			})
+			return
+		}
+		window = parsed
+	}
+	since := time.Now().Add(-window)
+
+	backups, err := h.backupsForCluster(ctx, clusterName)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+		logRequestError(c, "Failed to list backups", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list backups",
+			"details": err.Error(),
+		})
+		return
 	}
 
-	// Build cluster map from CronJobs first
-	clusterMap := make(map[string]map[string]interface{})
+	var backupDurations []float64
+	for _, backup := range backups {
+		if duration, ok := completedDuration(backup.Object, since); ok {
+			backupDurations = append(backupDurations, duration)
+		}
+	}
 
-	for _, cronJob := range cronJobList.Items {
-		clusterName := extractClusterFromCronJobName(cronJob.GetName())
-		if clusterName != "unknown" && clusterName != "" {
-			clusterMap[clusterName] = map[string]interface{}{
-				"Name":        clusterName,
-				"name":        clusterName,
-				"backupCount": 0,
-				"lastBackup":  nil,
+	var restoreDurations []float64
+	restores, err := h.restoresForCluster(ctx, clusterName)
+
+	if err == nil {
+		for _, restore := range restores {
+			if duration, ok := completedDuration(restore.Object, since); ok {
+				restoreDurations = append(restoreDurations, duration)
 			}
 		}
 	}
 
-	// Convert map to slice
-	clusters := make([]map[string]interface{}, 0, len(clusterMap))
-	for _, cluster := range clusterMap {
-		clusters = append(clusters, cluster)
+	c.JSON(http.StatusOK, gin.H{
+		"cluster":  clusterName,
+		"window":   window.String(),
+		"backups":  durationPercentiles(backupDurations),
+		"restores": durationPercentiles(restoreDurations),
+	})
+}
+
+// completedDuration returns the run time in seconds of a Completed backup or restore started after since
+func completedDuration(obj map[string]interface{}, since time.Time) (float64, bool) {
+	phase, _, _ := unstructured.NestedString(obj, "status", "phase")
+	if phase != "Completed" {
+		return 0, false
+	}
+
+	startStr, found, _ := unstructured.NestedString(obj, "status", "startTimestamp")
+	if !found {
+		return 0, false
+	}
+	endStr, found, _ := unstructured.NestedString(obj, "status", "completionTimestamp")
+	if !found {
+		return 0, false
+	}
+
+	start, err := time.Parse(time.RFC3339, startStr)
+	if err != nil || start.Before(since) {
+		return 0, false
+	}
+	end, err := time.Parse(time.RFC3339, endStr)
+	if err != nil || end.Before(start) {
+		return 0, false
+	}
+
+	return end.Sub(start).Seconds(), true
+}
+
+// durationPercentiles summarizes duration samples (in seconds). Percentiles are nil when there are no samples.
+func durationPercentiles(samples []float64) gin.H {
+	result := gin.H{
+		"samples": len(samples),
+		"p50":     nil,
+		"p95":     nil,
+		"p99":     nil,
+		"min":     nil,
+		"max":     nil,
+	}
+	if len(samples) == 0 {
+		return result
+	}
+
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	result["p50"] = percentile(sorted, 50)
+	result["p95"] = percentile(sorted, 95)
+	result["p99"] = percentile(sorted, 99)
+	result["min"] = sorted[0]
+	result["max"] = sorted[len(sorted)-1]
+	return result
+}
+
+// percentile returns the p-th percentile of sorted values using linear interpolation between closest ranks
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
 	}
 
-	return clusters, nil
+	weight := rank - float64(lower)
+	return sorted[lower]*(1-weight) + sorted[upper]*weight
 }
 
 // GetDashboardMetrics provides comprehensive dashboard statistics
 func (h *VeleroHandler) GetDashboardMetrics(c *gin.Context) {
+	ctx, cancel := h.k8sClient.RequestContext(c.Request.Context())
+	defer cancel()
+
 	// Get all clusters
-	clusters, err := h.getClusterList()
+	clusters, err := h.discoverClusters()
 	if err != nil {
+		logRequestError(c, "Failed to fetch clusters", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to fetch clusters",
 			"details": err.Error(),
@@ -2198,8 +3944,8 @@ func (h *VeleroHandler) GetDashboardMetrics(c *gin.Context) {
 	var totalClusters, healthyClusters, criticalClusters int
 
 	for _, cluster := range clusters {
-		clusterName := cluster["name"].(string)
-		health, err := h.calculateClusterHealth(clusterName)
+		clusterName := cluster.Name
+		health, err := h.calculateClusterHealth(ctx, clusterName)
 		if err != nil {
 			continue
 		}
@@ -2215,20 +3961,26 @@ func (h *VeleroHandler) GetDashboardMetrics(c *gin.Context) {
 	}
 
 	// Get overall backup/restore statistics
-	backupList, _ := h.k8sClient.DynamicClient.
-		Resource(k8s.BackupGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
+	backupList, _ := h.k8sClient.ListCache.List(ctx, k8s.BackupGVR, "velero")
 
-	restoreList, _ := h.k8sClient.DynamicClient.
-		Resource(k8s.RestoreGVR).
-		Namespace("velero").
-		List(h.k8sClient.Context, metav1.ListOptions{})
+	restoreList, _ := h.k8sClient.ListCache.List(ctx, k8s.RestoreGVR, "velero")
 
-	cronJobList, _ := h.k8sClient.DynamicClient.
-		Resource(k8s.CronJobGVR).
-		Namespace("velero-manager").
-		List(h.k8sClient.Context, metav1.ListOptions{})
+	scheduleList, _ := h.k8sClient.ListCache.List(ctx, k8s.ScheduleGVR, "velero")
+
+	// Count Velero schedules and the clusters' backup CronJobs, de-duplicated by name.
+	// Other CronJobs in the namespace, like token rotation, aren't backup schedules.
+	scheduleNames := make(map[string]bool)
+	var veleroSchedules int
+	if scheduleList != nil {
+		veleroSchedules = len(scheduleList.Items)
+		for _, schedule := range scheduleList.Items {
+			scheduleNames[schedule.GetName()] = true
+		}
+	}
+	backupCronJobs := len(clusters)
+	for _, cluster := range clusters {
+		scheduleNames[cluster.CronJob] = true
+	}
 
 	// Calculate overall metrics
 	now := time.Now()
@@ -2236,6 +3988,7 @@ func (h *VeleroHandler) GetDashboardMetrics(c *gin.Context) {
 
 	var (
 		totalBackups, successfulBackups, failedBackups    int
+		partiallyFailedBackups                            int
 		totalRestores, successfulRestores, failedRestores int
 		recentBackups, recentRestores                     []map[string]interface{}
 	)
@@ -2251,6 +4004,8 @@ func (h *VeleroHandler) GetDashboardMetrics(c *gin.Context) {
 			switch status {
 			case "Completed":
 				successfulBackups++
+			case "PartiallyFailed":
+				partiallyFailedBackups++
 			case "Failed", "FailedValidation":
 				failedBackups++
 			}
@@ -2260,7 +4015,7 @@ func (h *VeleroHandler) GetDashboardMetrics(c *gin.Context) {
 					"name":    backup.GetName(),
 					"status":  status,
 					"time":    creationTime,
-					"cluster": extractClusterFromBackupName(backup.GetName()),
+					"cluster": k8s.BackupCluster(&backup),
 				})
 			}
 		}
@@ -2288,7 +4043,7 @@ func (h *VeleroHandler) GetDashboardMetrics(c *gin.Context) {
 					"status":     status,
 					"time":       creationTime,
 					"backupName": backupName,
-					"cluster":    extractClusterFromBackupName(backupName),
+					"cluster":    k8s.RestoreCluster(&restore),
 				})
 			}
 		}
@@ -2313,10 +4068,11 @@ func (h *VeleroHandler) GetDashboardMetrics(c *gin.Context) {
 			"details":  clusterHealthMap,
 		},
 		"backups": map[string]interface{}{
-			"total":       totalBackups,
-			"successful":  successfulBackups,
-			"failed":      failedBackups,
-			"successRate": backupSuccessRate,
+			"total":           totalBackups,
+			"successful":      successfulBackups,
+			"failed":          failedBackups,
+			"partiallyFailed": partiallyFailedBackups,
+			"successRate":     backupSuccessRate,
 		},
 		"restores": map[string]interface{}{
 			"total":       totalRestores,
@@ -2325,7 +4081,9 @@ func (h *VeleroHandler) GetDashboardMetrics(c *gin.Context) {
 			"successRate": restoreSuccessRate,
 		},
 		"schedules": map[string]interface{}{
-			"total": len(cronJobList.Items),
+			"total":           len(scheduleNames),
+			"veleroSchedules": veleroSchedules,
+			"cronJobs":        backupCronJobs,
 		},
 		"recentActivity": map[string]interface{}{
 			"backups":  recentBackups,
@@ -2334,7 +4092,7 @@ func (h *VeleroHandler) GetDashboardMetrics(c *gin.Context) {
 		"updatedAt": now,
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondJSONWithETag(c, response, withoutKey(response, "updatedAt"))
 }
 
 // GenerateTestData populates metrics with mock data for testing
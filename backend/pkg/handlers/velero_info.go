@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultTTLFlag        = "--default-backup-ttl"
+	veleroBuiltinTTL      = 720 * time.Hour // Velero's own default when the flag isn't set
+	ttlMismatchMultiplier = 2               // warn when TTLs differ by more than this factor
+)
+
+// VeleroServerInfo describes how the Velero server in the cluster is configured
+type VeleroServerInfo struct {
+	Image            string        `json:"image"`
+	Version          string        `json:"version"`
+	DefaultBackupTTL time.Duration `json:"-"`
+	TTLSource        string        `json:"ttlSource"` // "deployment" or "builtin"
+	Args             []string      `json:"args"`
+}
+
+// getVeleroServerInfo extracts the server configuration from the cached Velero deployment
+func (h *VeleroHandler) getVeleroServerInfo(ctx context.Context) (*VeleroServerInfo, error) {
+	version, err := h.k8sClient.VeleroVersion.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &VeleroServerInfo{
+		Image:            version.Image,
+		Version:          version.Version,
+		Args:             version.Args,
+		DefaultBackupTTL: veleroBuiltinTTL,
+		TTLSource:        "builtin",
+	}
+
+	ttl, found, err := parseDefaultBackupTTL(info.Args)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		info.DefaultBackupTTL = ttl
+		info.TTLSource = "deployment"
+	}
+
+	return info, nil
+}
+
+// parseDefaultBackupTTL finds --default-backup-ttl in the server args, accepting both
+// "--default-backup-ttl=720h" and "--default-backup-ttl 720h" forms
+func parseDefaultBackupTTL(args []string) (time.Duration, bool, error) {
+	for i, arg := range args {
+		var value string
+		switch {
+		case strings.HasPrefix(arg, defaultTTLFlag+"="):
+			value = strings.TrimPrefix(arg, defaultTTLFlag+"=")
+		case arg == defaultTTLFlag && i+1 < len(args):
+			value = args[i+1]
+		default:
+			continue
+		}
+
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid %s value %q: %w", defaultTTLFlag, value, err)
+		}
+		return ttl, true, nil
+	}
+
+	return 0, false, nil
+}
+
+// ttlMismatchWarning returns a warning when the requested TTL is far off Velero's default
+func ttlMismatchWarning(requested, veleroDefault time.Duration) string {
+	if requested <= 0 || veleroDefault <= 0 {
+		return ""
+	}
+
+	if requested > veleroDefault*ttlMismatchMultiplier || requested*ttlMismatchMultiplier < veleroDefault {
+		return fmt.Sprintf("Requested TTL %s differs significantly from Velero's default TTL %s",
+			requested, veleroDefault)
+	}
+
+	return ""
+}
+
+// GetVeleroInfo returns the Velero server configuration, including its default backup TTL
+func (h *VeleroHandler) GetVeleroInfo(c *gin.Context) {
+	info, err := h.getVeleroServerInfo(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to read Velero server configuration",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"image":                   info.Image,
+		"version":                 info.Version,
+		"defaultBackupTTL":        info.DefaultBackupTTL.String(),
+		"defaultBackupTTLSeconds": int64(info.DefaultBackupTTL.Seconds()),
+		"ttlSource":               info.TTLSource,
+		"args":                    info.Args,
+	})
+}
+
+// GetVeleroVersion returns the version of the Velero server, so clients can check whether
+// it supports newer spec fields
+func (h *VeleroHandler) GetVeleroVersion(c *gin.Context) {
+	version, err := h.k8sClient.VeleroVersion.Get(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, ErrCodeVeleroVersionFailed, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+	"velero-manager/pkg/k8s"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newVeleroDeployment fabricates the velero server deployment with the given server args
+func newVeleroDeployment(args ...string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "velero", Namespace: k8s.VeleroNamespace},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:    "velero",
+						Image:   "velero/velero:v1.14.0",
+						Command: []string{"/velero"},
+						Args:    append([]string{"server"}, args...),
+					}},
+				},
+			},
+		},
+	}
+}
+
+func TestParseDefaultBackupTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    time.Duration
+		found   bool
+		wantErr bool
+	}{
+		{name: "equals form", args: []string{"server", "--default-backup-ttl=48h"}, want: 48 * time.Hour, found: true},
+		{name: "separate value", args: []string{"server", "--default-backup-ttl", "72h0m0s", "--uploader-type=kopia"}, want: 72 * time.Hour, found: true},
+		{name: "not set", args: []string{"server", "--uploader-type=kopia"}},
+		{name: "flag without value", args: []string{"server", "--default-backup-ttl"}},
+		{name: "invalid value", args: []string{"server", "--default-backup-ttl=forever"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := parseDefaultBackupTTL(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || found != tt.found {
+				t.Errorf("parseDefaultBackupTTL(%v) = %s, %v; want %s, %v", tt.args, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestGetVeleroInfoReportsDeploymentTTL(t *testing.T) {
+	handler := NewVeleroHandler(newTestClient(newVeleroDeployment("--default-backup-ttl", "48h")), nil)
+
+	w := serve(handler.GetVeleroInfo, http.MethodGet, "/api/v1/velero/info", nil, nil, "viewer")
+	assertStatus(t, w, http.StatusOK)
+
+	body := decodeBody(t, w)
+	if body["defaultBackupTTL"] != "48h0m0s" || body["ttlSource"] != "deployment" || body["version"] != "v1.14.0" {
+		t.Errorf("info = %v, want TTL 48h0m0s from the deployment of v1.14.0", body)
+	}
+}
+
+func TestCreateBackupUsesVeleroDefaultTTL(t *testing.T) {
+	client := newTestClient(newVeleroDeployment("--default-backup-ttl=48h"))
+	handler := NewVeleroHandler(client, nil)
+
+	w := serve(handler.CreateBackup, http.MethodPost, "/api/v1/backups", map[string]interface{}{"name": "first"}, nil, "admin")
+	assertStatus(t, w, http.StatusCreated)
+	if warning, ok := decodeBody(t, w)["warning"]; ok {
+		t.Errorf("unexpected warning for the default TTL: %v", warning)
+	}
+
+	backup, err := client.DynamicClient.Resource(k8s.BackupGVR).Namespace("velero").
+		Get(context.Background(), "first", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl, _, _ := unstructured.NestedString(backup.Object, "spec", "ttl"); ttl != "48h0m0s" {
+		t.Errorf("spec.ttl = %q, want Velero's default 48h0m0s", ttl)
+	}
+
+	// A TTL far off Velero's default is still used, with a warning
+	w = serve(handler.CreateBackup, http.MethodPost, "/api/v1/backups",
+		map[string]interface{}{"name": "second", "ttl": "720h"}, nil, "admin")
+	assertStatus(t, w, http.StatusCreated)
+	if warning, _ := decodeBody(t, w)["warning"].(string); warning == "" {
+		t.Error("no warning for a TTL 15 times Velero's default")
+	}
+
+	// The deployment is read once and then served from the version cache
+	gets := 0
+	for _, action := range client.Clientset.(*fake.Clientset).Actions() {
+		if action.GetVerb() == "get" && action.GetResource().Resource == "deployments" {
+			gets++
+		}
+	}
+	if gets != 1 {
+		t.Errorf("velero deployment read %d times for two backups, want 1", gets)
+	}
+}
+
+func TestCreateBackupFallsBackWithoutVeleroDeployment(t *testing.T) {
+	client := newTestClient()
+	handler := NewVeleroHandler(client, nil)
+
+	w := serve(handler.CreateBackup, http.MethodPost, "/api/v1/backups", map[string]interface{}{"name": "first"}, nil, "admin")
+	assertStatus(t, w, http.StatusCreated)
+
+	backup, err := client.DynamicClient.Resource(k8s.BackupGVR).Namespace("velero").
+		Get(context.Background(), "first", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl, _, _ := unstructured.NestedString(backup.Object, "spec", "ttl"); ttl != "720h0m0s" {
+		t.Errorf("spec.ttl = %q, want the 720h0m0s fallback", ttl)
+	}
+}
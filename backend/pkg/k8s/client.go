@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"velero-manager/pkg/config"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
@@ -18,20 +19,24 @@ type Client struct {
 	DynamicClient dynamic.Interface
 	Config        *rest.Config
 	Context       context.Context
+	// Short-lived cache for full list calls shared by all handlers
+	ListCache *ListCache
+	// Version of the Velero server, shared by the API and the metrics collector
+	VeleroVersion *VersionCache
 }
 
 func NewClient() (*Client, error) {
-	config, err := getKubeConfig()
+	restConfig, err := getKubeConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
+	clientset, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	dynamicClient, err := dynamic.NewForConfig(config)
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -39,11 +44,20 @@ func NewClient() (*Client, error) {
 	return &Client{
 		Clientset:     clientset,
 		DynamicClient: dynamicClient,
-		Config:        config,
+		Config:        restConfig,
 		Context:       context.Background(),
+		ListCache:     NewListCache(dynamicClient, config.GetServerConfig().ListCacheTTL),
+		VeleroVersion: NewVersionCache(clientset),
 	}, nil
 }
 
+// RequestContext returns the context for the Kubernetes calls of a request handler. It
+// ends when the client goes away or after K8S_REQUEST_TIMEOUT, so a hung API server
+// can't hold a handler forever.
+func (c *Client) RequestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, config.GetServerConfig().KubernetesRequestTimeout)
+}
+
 func getKubeConfig() (*rest.Config, error) {
 	// Try in-cluster config first (for running in K8s)
 	if config, err := rest.InClusterConfig(); err == nil {
@@ -63,6 +77,13 @@ func getKubeConfig() (*rest.Config, error) {
 	return clientcmd.BuildConfigFromFlags("", kubeconfig)
 }
 
+// VeleroNamespace is the namespace Velero and its resources are installed in
+const VeleroNamespace = "velero"
+
+// ClusterEnvironmentAnnotation is set on a cluster's credentials secret to group
+// clusters by environment (prod, staging, dev, ...)
+const ClusterEnvironmentAnnotation = "velero-manager.io/environment"
+
 // Velero resource definitions
 var (
 	BackupGVR = schema.GroupVersionResource{
@@ -111,4 +132,10 @@ var (
 		Version:  "v1",
 		Resource: "downloadrequests",
 	}
+
+	DeleteBackupRequestGVR = schema.GroupVersionResource{
+		Group:    "velero.io",
+		Version:  "v1",
+		Resource: "deletebackuprequests",
+	}
 )
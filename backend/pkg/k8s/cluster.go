@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"strings"
+	"velero-manager/pkg/config"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// ClusterLabel is set by velero-manager on backups it creates for a managed cluster
+	ClusterLabel = "velero.io/cluster"
+	// SourceClusterLabel is used by other tooling for the cluster a backup was taken from
+	SourceClusterLabel = "velero.io/source-cluster"
+	// TargetClusterLabel is set by CreateRestore on restores into a managed cluster
+	TargetClusterLabel = "velero.io/target-cluster"
+
+	// UnknownCluster is reported for backups and restores that can't be attributed to a cluster
+	UnknownCluster = "unknown"
+)
+
+// backupNameSeparators are the naming conventions of the backups velero-manager creates:
+// scheduled cluster backups, manual cluster backups and centralized backups
+var backupNameSeparators = []string{"-daily-backup-", "-manual-", "-centralized-"}
+
+// BackupCluster returns the cluster a backup belongs to. The cluster labels take
+// precedence; without them the cluster is parsed from the backup name.
+func BackupCluster(backup *unstructured.Unstructured) string {
+	labels := backup.GetLabels()
+	for _, label := range []string{ClusterLabel, SourceClusterLabel} {
+		if cluster := labels[label]; cluster != "" {
+			return cluster
+		}
+	}
+	return ClusterFromBackupName(backup.GetName())
+}
+
+// ClusterFromBackupName parses the cluster from a backup name, trying
+// BACKUP_CLUSTER_NAME_PATTERN before the built-in naming conventions
+func ClusterFromBackupName(backupName string) string {
+	if pattern := config.GetBackupConfig().ClusterNamePattern; pattern != nil {
+		if match := pattern.FindStringSubmatch(backupName); match != nil {
+			group := 1
+			if named := pattern.SubexpIndex("cluster"); named > 0 {
+				group = named
+			}
+			if match[group] != "" {
+				return match[group]
+			}
+		}
+	}
+
+	for _, separator := range backupNameSeparators {
+		if parts := strings.Split(backupName, separator); len(parts) >= 2 && parts[0] != "" {
+			return parts[0]
+		}
+	}
+
+	return UnknownCluster
+}
+
+// ScheduleCluster returns the cluster a Velero Schedule backs up, from the cluster labels
+// on the schedule or, failing that, on the backups it creates
+func ScheduleCluster(schedule *unstructured.Unstructured) string {
+	templateLabels, _, _ := unstructured.NestedStringMap(schedule.Object, "spec", "template", "metadata", "labels")
+	for _, labels := range []map[string]string{schedule.GetLabels(), templateLabels} {
+		for _, label := range []string{ClusterLabel, SourceClusterLabel} {
+			if cluster := labels[label]; cluster != "" {
+				return cluster
+			}
+		}
+	}
+	return UnknownCluster
+}
+
+// RestoreCluster returns the cluster a restore belongs to. The target and source cluster
+// labels take precedence; without them the cluster is parsed from the restore name and
+// then from the name of the backup it restores. Restores that match none of these are
+// reported as UnknownCluster; they used to be attributed to the management cluster.
+func RestoreCluster(restore *unstructured.Unstructured) string {
+	labels := restore.GetLabels()
+	for _, label := range []string{TargetClusterLabel, SourceClusterLabel} {
+		if cluster := labels[label]; cluster != "" {
+			return cluster
+		}
+	}
+
+	if cluster := ClusterFromBackupName(restore.GetName()); cluster != UnknownCluster {
+		return cluster
+	}
+
+	backupName, _, _ := unstructured.NestedString(restore.Object, "spec", "backupName")
+	return ClusterFromBackupName(backupName)
+}
@@ -0,0 +1,58 @@
+package k8s
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestRestore(name, backupName string, labels map[string]string) *unstructured.Unstructured {
+	restore := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"backupName": backupName},
+	}}
+	restore.SetName(name)
+	restore.SetLabels(labels)
+	return restore
+}
+
+func TestRestoreCluster(t *testing.T) {
+	tests := []struct {
+		name    string
+		restore *unstructured.Unstructured
+		want    string
+	}{
+		{
+			name: "target cluster label",
+			restore: newTestRestore("restore-1", "prod-daily-backup-20260301020000", map[string]string{
+				TargetClusterLabel: "staging",
+				SourceClusterLabel: "prod",
+			}),
+			want: "staging",
+		},
+		{
+			name:    "source cluster label",
+			restore: newTestRestore("restore-1", "prod-daily-backup-20260301020000", map[string]string{SourceClusterLabel: "dr"}),
+			want:    "dr",
+		},
+		{
+			name:    "unlabeled, cluster in restore name",
+			restore: newTestRestore("edge-manual-20260301", "prod-daily-backup-20260301020000", nil),
+			want:    "edge",
+		},
+		{
+			name:    "unlabeled, cluster in backup name",
+			restore: newTestRestore("restore-1", "prod-daily-backup-20260301020000", nil),
+			want:    "prod",
+		},
+		{
+			name:    "unlabeled and unattributable",
+			restore: newTestRestore("restore-1", "nightly", nil),
+			want:    UnknownCluster,
+		},
+	}
+	for _, tt := range tests {
+		if got := RestoreCluster(tt.restore); got != tt.want {
+			t.Errorf("%s: RestoreCluster = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
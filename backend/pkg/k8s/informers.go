@@ -0,0 +1,156 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// informerSyncTimeout is how long Start waits for the initial sync before reporting it.
+// Reads fall back to direct lists until a resource has synced.
+const informerSyncTimeout = time.Minute
+
+// InformerResources are the resources watched by the shared informers
+var InformerResources = []schema.GroupVersionResource{
+	BackupGVR,
+	RestoreGVR,
+	ScheduleGVR,
+	CronJobGVR,
+}
+
+// ClusterIndex is the informer index of backups and restores by the cluster they belong to
+const ClusterIndex = "cluster"
+
+// clusterFuncs return the cluster of the resources that are indexed by cluster
+var clusterFuncs = map[schema.GroupVersionResource]func(*unstructured.Unstructured) string{
+	BackupGVR:  BackupCluster,
+	RestoreGVR: RestoreCluster,
+}
+
+// InformerCache serves lists of the watched resources from shared informers in the
+// velero namespace instead of the API server
+type InformerCache struct {
+	namespace string
+	factory   dynamicinformer.DynamicSharedInformerFactory
+	informers map[schema.GroupVersionResource]informers.GenericInformer
+}
+
+// NewInformerCache creates informers for InformerResources in a namespace
+func NewInformerCache(client dynamic.Interface, namespace string) *InformerCache {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, 0, namespace, nil)
+
+	ic := &InformerCache{
+		namespace: namespace,
+		factory:   factory,
+		informers: make(map[schema.GroupVersionResource]informers.GenericInformer),
+	}
+	for _, gvr := range InformerResources {
+		ic.informers[gvr] = factory.ForResource(gvr)
+	}
+
+	// Indexers have to be added before the informers start
+	for gvr, clusterOf := range clusterFuncs {
+		err := ic.informers[gvr].Informer().AddIndexers(cache.Indexers{
+			ClusterIndex: clusterIndexFunc(clusterOf),
+		})
+		if err != nil {
+			log.Printf("⚠️  Failed to index %s by cluster: %v", gvr.Resource, err)
+		}
+	}
+	return ic
+}
+
+// clusterIndexFunc indexes objects under the cluster clusterOf returns for them
+func clusterIndexFunc(clusterOf func(*unstructured.Unstructured) string) cache.IndexFunc {
+	return func(obj interface{}) ([]string, error) {
+		item, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, nil
+		}
+		return []string{clusterOf(item)}, nil
+	}
+}
+
+// Start runs the informers until ctx is cancelled and waits for the initial sync
+func (ic *InformerCache) Start(ctx context.Context) {
+	ic.factory.Start(ctx.Done())
+
+	syncCtx, cancel := context.WithTimeout(ctx, informerSyncTimeout)
+	defer cancel()
+
+	for gvr, informer := range ic.informers {
+		if cache.WaitForCacheSync(syncCtx.Done(), informer.Informer().HasSynced) {
+			log.Printf("✅ Informer for %s synced", gvr.Resource)
+		} else {
+			log.Printf("⚠️  Informer for %s not synced after %s, falling back to direct lists", gvr.Resource, informerSyncTimeout)
+		}
+	}
+}
+
+// Shutdown stops the informers after their context was cancelled
+func (ic *InformerCache) Shutdown() {
+	ic.factory.Shutdown()
+}
+
+// List returns a copy of the informer's objects. It fails when the resource or namespace
+// isn't watched or the informer hasn't synced, so the caller can list directly instead.
+func (ic *InformerCache) List(gvr schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error) {
+	return ic.ListSelected(gvr, namespace, labels.Everything())
+}
+
+// ListSelected is List restricted to the objects whose labels match selector
+func (ic *InformerCache) ListSelected(gvr schema.GroupVersionResource, namespace string, selector labels.Selector) (*unstructured.UnstructuredList, error) {
+	informer, watched := ic.informers[gvr]
+	if !watched || namespace != ic.namespace {
+		return nil, fmt.Errorf("%s in namespace %s is not watched", gvr.Resource, namespace)
+	}
+	if !informer.Informer().HasSynced() {
+		return nil, fmt.Errorf("informer for %s has not synced", gvr.Resource)
+	}
+
+	objects, err := informer.Lister().ByNamespace(namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	return copyList(objects), nil
+}
+
+// ListByCluster returns a copy of the informer's objects that belong to a cluster, looked
+// up in the cluster index. Like List, it fails when the informer can't serve the list.
+func (ic *InformerCache) ListByCluster(gvr schema.GroupVersionResource, namespace, cluster string) (*unstructured.UnstructuredList, error) {
+	informer, watched := ic.informers[gvr]
+	if _, indexed := clusterFuncs[gvr]; !watched || !indexed || namespace != ic.namespace {
+		return nil, fmt.Errorf("%s in namespace %s is not indexed by cluster", gvr.Resource, namespace)
+	}
+	if !informer.Informer().HasSynced() {
+		return nil, fmt.Errorf("informer for %s has not synced", gvr.Resource)
+	}
+
+	objects, err := informer.Informer().GetIndexer().ByIndex(ClusterIndex, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return copyList(objects), nil
+}
+
+// copyList deep-copies informer objects into a list the caller may modify
+func copyList[T any](objects []T) *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{
+		Items: make([]unstructured.Unstructured, 0, len(objects)),
+	}
+	for _, object := range objects {
+		if item, ok := any(object).(*unstructured.Unstructured); ok {
+			list.Items = append(list.Items, *item.DeepCopy())
+		}
+	}
+	return list
+}
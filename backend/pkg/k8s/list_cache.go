@@ -0,0 +1,176 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+type listCacheKey struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+}
+
+type listCacheEntry struct {
+	list      *unstructured.UnstructuredList
+	fetchedAt time.Time
+}
+
+// ListCache keeps full, unfiltered list results for a short time so that handlers
+// loading the same resources for one page share a single API call. When informers are
+// attached and synced, lists are served from them instead.
+type ListCache struct {
+	client    dynamic.Interface
+	ttl       time.Duration
+	informers *InformerCache
+	entries   map[listCacheKey]listCacheEntry
+	// Bumped by Invalidate, so a list fetched before a write isn't cached after it
+	generations map[schema.GroupVersionResource]uint64
+	mutex       sync.Mutex
+}
+
+// NewListCache creates a list cache; a ttl of zero or less disables caching
+func NewListCache(client dynamic.Interface, ttl time.Duration) *ListCache {
+	return &ListCache{
+		client:      client,
+		ttl:         ttl,
+		entries:     make(map[listCacheKey]listCacheEntry),
+		generations: make(map[schema.GroupVersionResource]uint64),
+	}
+}
+
+// UseInformers serves lists of the informers' resources from them once they have synced.
+// Call it before the cache is used.
+func (lc *ListCache) UseInformers(informers *InformerCache) {
+	lc.informers = informers
+}
+
+// List returns all objects of a resource in a namespace, from the informers or from the
+// cache while it is fresh. The result is a copy the caller may modify.
+func (lc *ListCache) List(ctx context.Context, gvr schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error) {
+	if lc.informers != nil {
+		if list, err := lc.informers.List(gvr, namespace); err == nil {
+			return list, nil
+		}
+	}
+
+	if lc.ttl <= 0 {
+		return lc.list(ctx, gvr, namespace)
+	}
+
+	key := listCacheKey{gvr: gvr, namespace: namespace}
+
+	lc.mutex.Lock()
+	entry, found := lc.entries[key]
+	generation := lc.generations[gvr]
+	lc.mutex.Unlock()
+
+	if found && time.Since(entry.fetchedAt) < lc.ttl {
+		return entry.list.DeepCopy(), nil
+	}
+
+	list, err := lc.list(ctx, gvr, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// An Invalidate during the list means the result may predate a write; return it but
+	// don't cache it
+	lc.mutex.Lock()
+	if lc.generations[gvr] == generation {
+		lc.entries[key] = listCacheEntry{list: list, fetchedAt: time.Now()}
+	}
+	lc.mutex.Unlock()
+
+	return list.DeepCopy(), nil
+}
+
+// ListSelected returns the objects whose labels match selector. Informers and cached lists
+// are filtered in memory; without either, the selector is passed to the API server.
+func (lc *ListCache) ListSelected(ctx context.Context, gvr schema.GroupVersionResource, namespace string, selector labels.Selector) (*unstructured.UnstructuredList, error) {
+	if selector.Empty() {
+		return lc.List(ctx, gvr, namespace)
+	}
+
+	if lc.informers != nil {
+		if list, err := lc.informers.ListSelected(gvr, namespace, selector); err == nil {
+			return list, nil
+		}
+	}
+
+	if lc.ttl <= 0 {
+		return Retry(func() (*unstructured.UnstructuredList, error) {
+			return lc.client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		})
+	}
+
+	list, err := lc.List(ctx, gvr, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	items := list.Items[:0]
+	for i := range list.Items {
+		if selector.Matches(labels.Set(list.Items[i].GetLabels())) {
+			items = append(items, list.Items[i])
+		}
+	}
+	list.Items = items
+	return list, nil
+}
+
+// ListByCluster returns the backups or restores of a cluster, from the informers' cluster
+// index or else by filtering the full list. The result is a copy the caller may modify.
+func (lc *ListCache) ListByCluster(ctx context.Context, gvr schema.GroupVersionResource, namespace, cluster string) (*unstructured.UnstructuredList, error) {
+	clusterOf, indexed := clusterFuncs[gvr]
+	if !indexed {
+		return nil, fmt.Errorf("%s are not attributed to clusters", gvr.Resource)
+	}
+
+	if lc.informers != nil {
+		if list, err := lc.informers.ListByCluster(gvr, namespace, cluster); err == nil {
+			return list, nil
+		}
+	}
+
+	list, err := lc.List(ctx, gvr, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	items := list.Items[:0]
+	for i := range list.Items {
+		if clusterOf(&list.Items[i]) == cluster {
+			items = append(items, list.Items[i])
+		}
+	}
+	list.Items = items
+	return list, nil
+}
+
+// list lists from the API server, retrying transient errors
+func (lc *ListCache) list(ctx context.Context, gvr schema.GroupVersionResource, namespace string) (*unstructured.UnstructuredList, error) {
+	return Retry(func() (*unstructured.UnstructuredList, error) {
+		return lc.client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	})
+}
+
+// Invalidate drops every cached list of a resource, after it was created, changed or deleted
+func (lc *ListCache) Invalidate(gvr schema.GroupVersionResource) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.generations[gvr]++
+	for key := range lc.entries {
+		if key.gvr == gvr {
+			delete(lc.entries, key)
+		}
+	}
+}
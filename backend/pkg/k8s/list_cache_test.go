@@ -0,0 +1,91 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newFakeBackupClient() *dynamicfake.FakeDynamicClient {
+	backup := &unstructured.Unstructured{}
+	backup.SetAPIVersion("velero.io/v1")
+	backup.SetKind("Backup")
+	backup.SetNamespace(VeleroNamespace)
+	backup.SetName("nightly")
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{BackupGVR: "BackupList"}, backup)
+}
+
+func countLists(client *dynamicfake.FakeDynamicClient) int {
+	lists := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "list" {
+			lists++
+		}
+	}
+	return lists
+}
+
+func TestListCacheReusesListsUntilInvalidated(t *testing.T) {
+	client := newFakeBackupClient()
+	cache := NewListCache(client, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		list, err := cache.List(context.Background(), BackupGVR, VeleroNamespace)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list.Items) != 1 {
+			t.Fatalf("listed %d backups, want 1", len(list.Items))
+		}
+	}
+	if lists := countLists(client); lists != 1 {
+		t.Errorf("%d API lists for two cached lists, want 1", lists)
+	}
+
+	cache.Invalidate(BackupGVR)
+	if _, err := cache.List(context.Background(), BackupGVR, VeleroNamespace); err != nil {
+		t.Fatal(err)
+	}
+	if lists := countLists(client); lists != 2 {
+		t.Errorf("%d API lists after Invalidate, want 2", lists)
+	}
+}
+
+func TestListCacheDropsListsRacingInvalidate(t *testing.T) {
+	client := newFakeBackupClient()
+	cache := NewListCache(client, time.Minute)
+
+	// A write lands, and invalidates, while the first list is in flight
+	invalidated := false
+	client.PrependReactor("list", "backups", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if !invalidated {
+			invalidated = true
+			cache.Invalidate(BackupGVR)
+		}
+		return false, nil, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.List(context.Background(), BackupGVR, VeleroNamespace); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lists := countLists(client); lists != 2 {
+		t.Errorf("%d API lists, want 2: the list that raced Invalidate must not be cached", lists)
+	}
+
+	// The second list didn't race anything and is cached
+	if _, err := cache.List(context.Background(), BackupGVR, VeleroNamespace); err != nil {
+		t.Fatal(err)
+	}
+	if lists := countLists(client); lists != 2 {
+		t.Errorf("%d API lists, want the second list to be cached", lists)
+	}
+}
@@ -0,0 +1,61 @@
+package k8s
+
+import (
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// retryBackoff allows three attempts over roughly a second, enough to ride out brief
+// API server throttling without holding up the request for long
+var retryBackoff = wait.Backoff{
+	Steps:    3,
+	Duration: 200 * time.Millisecond,
+	Factor:   3.0,
+	Jitter:   0.1,
+}
+
+// IsTransient reports whether an API error is likely to go away when the call is retried
+func IsTransient(err error) bool {
+	return apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsTimeout(err) ||
+		utilnet.IsConnectionReset(err) ||
+		utilnet.IsProbableEOF(err)
+}
+
+// isRejected reports whether the API server turned a request away without applying it,
+// so a write can be retried without risking it being applied twice
+func isRejected(err error) bool {
+	return apierrors.IsTooManyRequests(err) || apierrors.IsServerTimeout(err)
+}
+
+// Retry runs a read, retrying transient errors with exponential backoff
+func Retry[T any](fn func() (T, error)) (T, error) {
+	var result T
+	err := retry.OnError(retryBackoff, IsTransient, func() (err error) {
+		result, err = fn()
+		return err
+	})
+	return result, err
+}
+
+// RetryDelete runs a delete, retrying transient errors with exponential backoff
+func RetryDelete(fn func() error) error {
+	return retry.OnError(retryBackoff, IsTransient, fn)
+}
+
+// RetryCreate runs a create, retrying only errors where the API server rejected the
+// request, since a create that timed out or failed midway may still have been applied
+func RetryCreate[T any](fn func() (T, error)) (T, error) {
+	var result T
+	err := retry.OnError(retryBackoff, isRejected, func() (err error) {
+		result, err = fn()
+		return err
+	})
+	return result, err
+}
@@ -0,0 +1,54 @@
+package k8s
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ParseSchedule parses a schedule expression with the same parser Velero uses, so the
+// @daily style descriptors, "@every <duration>" and a CRON_TZ=/TZ= prefix all work.
+// Without a prefix the schedule is evaluated in UTC, the time zone of the Velero server
+// image. Next on the result returns the zero time if the schedule never runs again.
+func ParseSchedule(expression string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(expression)
+	if err != nil {
+		return nil, err
+	}
+	if spec, ok := schedule.(*cron.SpecSchedule); ok && spec.Location == time.Local {
+		spec.Location = time.UTC
+	}
+	return schedule, nil
+}
+
+// ScheduleDeadline returns when a schedule counts as overdue, measured from its last
+// backup or, if it has never produced one, its creation, along with that start time.
+// The deadline is zero if the schedule never runs again.
+func ScheduleDeadline(schedule *unstructured.Unstructured, factor float64) (time.Time, time.Time, error) {
+	expression, _, _ := unstructured.NestedString(schedule.Object, "spec", "schedule")
+	parsed, err := ParseSchedule(expression)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	last := schedule.GetCreationTimestamp().Time
+	if lastBackup, _, _ := unstructured.NestedString(schedule.Object, "status", "lastBackup"); lastBackup != "" {
+		if parsedTime, err := time.Parse(time.RFC3339, lastBackup); err == nil {
+			last = parsedTime
+		}
+	}
+
+	return last, overdueDeadline(parsed, last, factor), nil
+}
+
+// overdueDeadline returns factor times the wait from last to the next activation after it.
+// Measuring the wait from last itself keeps irregular schedules such as weekdays-only from
+// looking overdue over a weekend.
+func overdueDeadline(schedule cron.Schedule, last time.Time, factor float64) time.Time {
+	next := schedule.Next(last)
+	if next.IsZero() {
+		return time.Time{}
+	}
+	return last.Add(time.Duration(float64(next.Sub(last)) * factor))
+}
@@ -0,0 +1,71 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2026, 3, 6, 10, 30, 0, 0, time.UTC) // a Friday
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+
+	tests := []struct {
+		expression string
+		want       time.Time
+	}{
+		{"0 2 * * *", time.Date(2026, 3, 7, 2, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 6, 10, 45, 0, 0, time.UTC)},
+		{"0 2 * * 1-5", time.Date(2026, 3, 9, 2, 0, 0, 0, time.UTC)},
+		{"@every 6h", time.Date(2026, 3, 6, 16, 30, 0, 0, time.UTC)},
+		{"CRON_TZ=Europe/Berlin 0 2 * * *", time.Date(2026, 3, 7, 2, 0, 0, 0, berlin)},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.expression)
+		if err != nil {
+			t.Errorf("%q: %v", tt.expression, err)
+			continue
+		}
+		if next := schedule.Next(from); !next.Equal(tt.want) {
+			t.Errorf("%q: next = %s, want %s", tt.expression, next, tt.want)
+		}
+	}
+
+	for _, invalid := range []string{"", "0 2 * *", "61 * * * *", "CRON_TZ=Nowhere/Special 0 2 * * *"} {
+		if _, err := ParseSchedule(invalid); err == nil {
+			t.Errorf("%q: no error", invalid)
+		}
+	}
+
+	// February 30th never comes
+	if schedule, err := ParseSchedule("0 0 30 2 *"); err != nil || !schedule.Next(from).IsZero() {
+		t.Errorf("impossible schedule: %v, next %v; want the zero time", err, schedule)
+	}
+}
+
+func TestScheduleDeadline(t *testing.T) {
+	lastBackup := time.Date(2026, 3, 6, 2, 0, 0, 0, time.UTC) // Friday
+	schedule := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   map[string]interface{}{"schedule": "0 2 * * 1-5"},
+		"status": map[string]interface{}{"lastBackup": lastBackup.Format(time.RFC3339)},
+	}}
+	schedule.SetCreationTimestamp(metav1.NewTime(lastBackup.Add(-30 * 24 * time.Hour)))
+
+	last, deadline, err := ScheduleDeadline(schedule, 1.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !last.Equal(lastBackup) {
+		t.Errorf("last = %s, want the last backup %s", last, lastBackup)
+	}
+	// The next run is Monday, 72 hours later, so the weekend isn't overdue
+	if want := lastBackup.Add(108 * time.Hour); !deadline.Equal(want) {
+		t.Errorf("deadline = %s, want %s", deadline, want)
+	}
+}
@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// veleroVersionTTL is how long the Velero version is cached; it only changes on upgrades
+const veleroVersionTTL = 5 * time.Minute
+
+// VeleroVersion is the version of the Velero server, taken from its deployment's image
+type VeleroVersion struct {
+	Version   string    `json:"version"`
+	Image     string    `json:"image"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	// Command and args of the velero container, for reading server flags
+	Args []string `json:"-"`
+}
+
+// VersionCache caches the Velero server version and flags read from the velero deployment
+type VersionCache struct {
+	clientset kubernetes.Interface
+	cached    *VeleroVersion
+	mutex     sync.Mutex
+}
+
+// NewVersionCache creates a cache for the Velero server version
+func NewVersionCache(clientset kubernetes.Interface) *VersionCache {
+	return &VersionCache{clientset: clientset}
+}
+
+// Get returns the Velero server version, reading the deployment at most every few minutes
+func (vc *VersionCache) Get(ctx context.Context) (VeleroVersion, error) {
+	vc.mutex.Lock()
+	defer vc.mutex.Unlock()
+
+	if vc.cached != nil && time.Since(vc.cached.FetchedAt) < veleroVersionTTL {
+		return *vc.cached, nil
+	}
+
+	deployment, err := vc.clientset.AppsV1().
+		Deployments(VeleroNamespace).
+		Get(ctx, "velero", metav1.GetOptions{})
+	if err != nil {
+		return VeleroVersion{}, err
+	}
+
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == "velero" {
+			vc.cached = &VeleroVersion{
+				Version:   ImageTag(container.Image),
+				Image:     container.Image,
+				FetchedAt: time.Now(),
+				Args:      append(append([]string{}, container.Command...), container.Args...),
+			}
+			return *vc.cached, nil
+		}
+	}
+
+	return VeleroVersion{}, fmt.Errorf("velero deployment has no velero container")
+}
+
+// ImageTag returns the tag of a container image reference, ignoring any registry port and
+// digest, e.g. "v1.12.0" for "registry:5000/velero/velero:v1.12.0@sha256:...". It is empty
+// for untagged images.
+func ImageTag(image string) string {
+	if i := strings.Index(image, "@"); i != -1 {
+		image = image[:i]
+	}
+	i := strings.LastIndex(image, ":")
+	if i == -1 || strings.Contains(image[i+1:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
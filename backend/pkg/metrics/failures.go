@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"log"
+	"time"
+	"velero-manager/pkg/k8s"
+	"velero-manager/pkg/notify"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// failureNotifier notifies once per backup that ends Failed or PartiallyFailed. Backups
+// that finished before it started are left alone, so a restart doesn't re-send old
+// failures.
+type failureNotifier struct {
+	notifier  notify.Notifier
+	startedAt time.Time
+	// Backups already notified about, only used by the collector goroutine
+	notified map[string]bool
+}
+
+func newFailureNotifier(notifier notify.Notifier, now time.Time) *failureNotifier {
+	return &failureNotifier{
+		notifier:  notifier,
+		startedAt: now,
+		notified:  make(map[string]bool),
+	}
+}
+
+// observe sends a notification for every newly failed backup and forgets backups that
+// are gone
+func (fn *failureNotifier) observe(backups []unstructured.Unstructured) {
+	if fn == nil {
+		return
+	}
+
+	seen := make(map[string]bool, len(backups))
+	for _, backup := range backups {
+		name := backup.GetName()
+		seen[name] = true
+
+		phase, _, _ := unstructured.NestedString(backup.Object, "status", "phase")
+		if phase != "Failed" && phase != "PartiallyFailed" {
+			continue
+		}
+		if fn.notified[name] {
+			continue
+		}
+		fn.notified[name] = true
+
+		completion := statusTime(backup, "completionTimestamp", time.Time{})
+		if completion.IsZero() {
+			completion = backup.GetCreationTimestamp().Time
+		}
+		if completion.Before(fn.startedAt) {
+			continue
+		}
+
+		errorCount, _, _ := unstructured.NestedInt64(backup.Object, "status", "errors")
+		warningCount, _, _ := unstructured.NestedInt64(backup.Object, "status", "warnings")
+		reason, _, _ := unstructured.NestedString(backup.Object, "status", "failureReason")
+
+		failure := notify.BackupFailure{
+			Event:          notify.BackupFailedEvent,
+			Backup:         name,
+			Cluster:        k8s.BackupCluster(&backup),
+			Phase:          phase,
+			Schedule:       backup.GetLabels()["velero.io/schedule-name"],
+			Errors:         errorCount,
+			Warnings:       warningCount,
+			FailureReason:  reason,
+			CompletionTime: completion,
+		}
+
+		// Don't hold up the collection on a slow webhook
+		go func() {
+			if err := fn.notifier.NotifyBackupFailure(context.Background(), failure); err != nil {
+				log.Printf("⚠️  Failed to send failure notification for backup %s: %v", failure.Backup, err)
+			}
+		}()
+	}
+
+	for name := range fn.notified {
+		if !seen[name] {
+			delete(fn.notified, name)
+		}
+	}
+}
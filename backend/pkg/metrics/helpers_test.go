@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"velero-manager/pkg/k8s"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	sharedMetrics     *VeleroMetrics
+	sharedMetricsOnce sync.Once
+)
+
+// newTestMetrics returns the metrics reading from client. The metrics register with the
+// default Prometheus registry, so every test shares one set and is handed the client.
+func newTestMetrics(client *k8s.Client) *VeleroMetrics {
+	sharedMetricsOnce.Do(func() {
+		sharedMetrics = NewVeleroMetrics(nil)
+	})
+	sharedMetrics.k8sClient = client
+	return sharedMetrics
+}
+
+// newTestClient returns a client backed by fake API servers. Unstructured objects seed the
+// dynamic client and typed objects the clientset.
+func newTestClient(objects ...runtime.Object) *k8s.Client {
+	var typed, untyped []runtime.Object
+	for _, obj := range objects {
+		if _, ok := obj.(*unstructured.Unstructured); ok {
+			untyped = append(untyped, obj)
+		} else {
+			typed = append(typed, obj)
+		}
+	}
+
+	clientset := fake.NewSimpleClientset(typed...)
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			k8s.BackupGVR:                "BackupList",
+			k8s.RestoreGVR:               "RestoreList",
+			k8s.ScheduleGVR:              "ScheduleList",
+			k8s.BackupStorageLocationGVR: "BackupStorageLocationList",
+		}, untyped...)
+
+	return &k8s.Client{
+		Clientset:     clientset,
+		DynamicClient: dynamicClient,
+		Context:       context.Background(),
+		ListCache:     k8s.NewListCache(dynamicClient, 0),
+		VeleroVersion: k8s.NewVersionCache(clientset),
+	}
+}
+
+// newBackup builds a backup in the velero namespace
+func newBackup(name string, labels map[string]string, status map[string]interface{}) *unstructured.Unstructured {
+	backup := &unstructured.Unstructured{Object: map[string]interface{}{"status": status}}
+	backup.SetAPIVersion("velero.io/v1")
+	backup.SetKind("Backup")
+	backup.SetNamespace("velero")
+	backup.SetName(name)
+	backup.SetLabels(labels)
+	return backup
+}
+
+// sample is one series of a metric in the default registry
+type sample struct {
+	labels map[string]string
+	// Counter or gauge value
+	value float64
+}
+
+// series returns every series of a metric in the default registry
+func series(t *testing.T, name string) []sample {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+
+	var samples []sample
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, pair := range metric.GetLabel() {
+				labels[pair.GetName()] = pair.GetValue()
+			}
+			samples = append(samples, sample{
+				labels: labels,
+				value:  metric.GetCounter().GetValue() + metric.GetGauge().GetValue(),
+			})
+		}
+	}
+	return samples
+}
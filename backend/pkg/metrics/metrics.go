@@ -2,30 +2,40 @@ package metrics
 
 import (
 	"context"
+	"log"
 	"strconv"
-	"strings"
 	"time"
 
+	"velero-manager/pkg/config"
 	"velero-manager/pkg/k8s"
+	"velero-manager/pkg/notify"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 type VeleroMetrics struct {
 	k8sClient *k8s.Client
 
+	// Phase of each running backup, only used by the collector goroutine
+	backupPhases *phaseTracker
+	// Notifies about failed backups; nil when notifications are disabled
+	backupFailures *failureNotifier
+
 	// Backup metrics
 	BackupTotal         prometheus.CounterVec
 	BackupSuccessTotal  prometheus.CounterVec
 	BackupFailureTotal  prometheus.CounterVec
 	BackupDuration      prometheus.HistogramVec
+	BackupPhaseDuration prometheus.HistogramVec
 	BackupSizeBytes     prometheus.GaugeVec
 	BackupItemsTotal    prometheus.GaugeVec
 	BackupItemsBackedUp prometheus.GaugeVec
 	BackupErrors        prometheus.GaugeVec
 	BackupWarnings      prometheus.GaugeVec
+	BackupInProgress    prometheus.GaugeVec
 
 	// Restore metrics
 	RestoreTotal         prometheus.CounterVec
@@ -41,25 +51,48 @@ type VeleroMetrics struct {
 	ScheduleTotal            prometheus.GaugeVec
 	SchedulePaused           prometheus.GaugeVec
 	ScheduleLastBackup       prometheus.GaugeVec
+	ScheduleOverdue          prometheus.GaugeVec
 	ScheduleValidationErrors prometheus.GaugeVec
 
 	// General metrics
 	VeleroAvailable    prometheus.Gauge
+	VeleroVersionInfo  prometheus.GaugeVec
 	APIRequestsTotal   prometheus.CounterVec
 	APIRequestDuration prometheus.HistogramVec
 
+	// Manager metrics; Go runtime and process metrics come from the default
+	// registry's collectors, which /metrics serves
+	ManagerBuildInfo prometheus.GaugeVec
+	ManagerUp        prometheus.Gauge
+	ManagerStartTime prometheus.Gauge
+
 	// Cluster-based metrics
 	ClusterHealthStatus       prometheus.GaugeVec
 	ClusterBackupSuccessRate  prometheus.GaugeVec
 	ClusterRestoreSuccessRate prometheus.GaugeVec
 	ClusterLastBackupTime     prometheus.GaugeVec
+	ClusterLastSuccessAge     prometheus.GaugeVec
 	ClusterBackupTotal        prometheus.GaugeVec
 	ClusterRestoreTotal       prometheus.GaugeVec
+	ClusterBackupSizeBytes    prometheus.GaugeVec
+
+	// Storage location metrics
+	StorageLocationBackupSizeBytes prometheus.GaugeVec
+	StorageLocationAvailable       prometheus.GaugeVec
 }
 
 func NewVeleroMetrics(k8sClient *k8s.Client) *VeleroMetrics {
+	durationBuckets := config.GetMetricsConfig().DurationBuckets
+
+	var backupFailures *failureNotifier
+	if notifier := notify.FromConfig(); notifier != nil {
+		backupFailures = newFailureNotifier(notifier, time.Now())
+	}
+
 	return &VeleroMetrics{
-		k8sClient: k8sClient,
+		k8sClient:      k8sClient,
+		backupPhases:   newPhaseTracker("Completed", "PartiallyFailed", "Failed", "FailedValidation", "Deleting"),
+		backupFailures: backupFailures,
 
 		// Backup metrics
 		BackupTotal: *promauto.NewCounterVec(prometheus.CounterOpts{
@@ -80,9 +113,15 @@ func NewVeleroMetrics(k8sClient *k8s.Client) *VeleroMetrics {
 		BackupDuration: *promauto.NewHistogramVec(prometheus.HistogramOpts{
 			Name:    "velero_backup_duration_seconds",
 			Help:    "Duration of Velero backups in seconds",
-			Buckets: prometheus.ExponentialBuckets(30, 2, 10), // 30s to ~8.5 hours
+			Buckets: durationBuckets,
 		}, []string{"namespace", "schedule", "phase"}),
 
+		BackupPhaseDuration: *promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "velero_backup_phase_duration_seconds",
+			Help:    "Time Velero backups spent in each phase, such as InProgress or Finalizing, in seconds",
+			Buckets: durationBuckets,
+		}, []string{"phase"}),
+
 		BackupSizeBytes: *promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "velero_backup_size_bytes",
 			Help: "Size of Velero backup in bytes",
@@ -108,6 +147,11 @@ func NewVeleroMetrics(k8sClient *k8s.Client) *VeleroMetrics {
 			Help: "Number of warnings in Velero backup",
 		}, []string{"namespace", "backup_name", "phase"}),
 
+		BackupInProgress: *promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "velero_backup_in_progress_duration_seconds",
+			Help: "Seconds since an in-progress Velero backup started",
+		}, []string{"namespace", "backup_name"}),
+
 		// Restore metrics
 		RestoreTotal: *promauto.NewCounterVec(prometheus.CounterOpts{
 			Name: "velero_restore_total",
@@ -127,7 +171,7 @@ func NewVeleroMetrics(k8sClient *k8s.Client) *VeleroMetrics {
 		RestoreDuration: *promauto.NewHistogramVec(prometheus.HistogramOpts{
 			Name:    "velero_restore_duration_seconds",
 			Help:    "Duration of Velero restores in seconds",
-			Buckets: prometheus.ExponentialBuckets(30, 2, 10), // 30s to ~8.5 hours
+			Buckets: durationBuckets,
 		}, []string{"namespace", "backup_name", "phase"}),
 
 		RestoreItemsTotal: *promauto.NewGaugeVec(prometheus.GaugeOpts{
@@ -166,6 +210,11 @@ func NewVeleroMetrics(k8sClient *k8s.Client) *VeleroMetrics {
 			Help: "Timestamp of last backup created by schedule",
 		}, []string{"namespace", "schedule_name"}),
 
+		ScheduleOverdue: *promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "velero_schedule_overdue",
+			Help: "Whether an active schedule has gone too long without a backup (1) or not (0)",
+		}, []string{"namespace", "schedule_name"}),
+
 		ScheduleValidationErrors: *promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "velero_schedule_validation_errors",
 			Help: "Number of validation errors in Velero schedule",
@@ -177,6 +226,11 @@ func NewVeleroMetrics(k8sClient *k8s.Client) *VeleroMetrics {
 			Help: "Whether Velero CRDs are available (1) or not (0)",
 		}),
 
+		VeleroVersionInfo: *promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "velero_version_info",
+			Help: "Version of the Velero server, from its image tag; always 1",
+		}, []string{"version"}),
+
 		APIRequestsTotal: *promauto.NewCounterVec(prometheus.CounterOpts{
 			Name: "velero_manager_api_requests_total",
 			Help: "Total number of API requests to Velero Manager",
@@ -188,39 +242,84 @@ func NewVeleroMetrics(k8sClient *k8s.Client) *VeleroMetrics {
 			Buckets: prometheus.DefBuckets,
 		}, []string{"method", "endpoint"}),
 
+		// Manager metrics
+		ManagerBuildInfo: *promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "velero_manager_build_info",
+			Help: "Version and commit Velero Manager was built from; always 1",
+		}, []string{"version", "commit"}),
+
+		ManagerUp: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "velero_manager_up",
+			Help: "Whether Velero Manager is running (1) or shutting down (0)",
+		}),
+
+		ManagerStartTime: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "velero_manager_start_time_seconds",
+			Help: "Unix timestamp Velero Manager started at",
+		}),
+
 		// Cluster-based metrics
 		ClusterHealthStatus: *promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "velero_cluster_health_status",
 			Help: "Health status of clusters (0=critical, 1=no-backups, 2=warning, 3=healthy)",
-		}, []string{"cluster"}),
+		}, []string{"cluster", "environment"}),
 
 		ClusterBackupSuccessRate: *promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "velero_cluster_backup_success_rate",
 			Help: "Backup success rate percentage per cluster",
-		}, []string{"cluster"}),
+		}, []string{"cluster", "environment"}),
 
 		ClusterRestoreSuccessRate: *promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "velero_cluster_restore_success_rate",
 			Help: "Restore success rate percentage per cluster",
-		}, []string{"cluster"}),
+		}, []string{"cluster", "environment"}),
 
 		ClusterLastBackupTime: *promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "velero_cluster_last_backup_timestamp",
 			Help: "Timestamp of last backup per cluster",
-		}, []string{"cluster"}),
+		}, []string{"cluster", "environment"}),
+
+		ClusterLastSuccessAge: *promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "velero_cluster_last_successful_backup_age_seconds",
+			Help: "Seconds since the last Completed backup per cluster; absent if none has completed",
+		}, []string{"cluster", "environment"}),
 
 		ClusterBackupTotal: *promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "velero_cluster_backup_total",
 			Help: "Total number of backups per cluster",
-		}, []string{"cluster", "status"}),
+		}, []string{"cluster", "environment", "status"}),
 
 		ClusterRestoreTotal: *promauto.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "velero_cluster_restore_total",
 			Help: "Total number of restores per cluster",
-		}, []string{"cluster", "status"}),
+		}, []string{"cluster", "environment", "status"}),
+
+		ClusterBackupSizeBytes: *promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "velero_cluster_backup_size_bytes",
+			Help: "Total size of Velero backups per cluster in bytes",
+		}, []string{"cluster", "environment"}),
+
+		// Storage location metrics
+		StorageLocationBackupSizeBytes: *promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "velero_storage_location_backup_size_bytes",
+			Help: "Total size of Velero backups per storage location in bytes",
+		}, []string{"location"}),
+
+		StorageLocationAvailable: *promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "velero_storage_location_available",
+			Help: "Whether a Velero backup storage location is Available (1) or not (0)",
+		}, []string{"location", "provider"}),
 	}
 }
 
+// SetManagerInfo records the manager's build and marks it up as of now
+func (vm *VeleroMetrics) SetManagerInfo(version, commit string) {
+	vm.ManagerBuildInfo.Reset()
+	vm.ManagerBuildInfo.WithLabelValues(version, commit).Set(1)
+	vm.ManagerUp.Set(1)
+	vm.ManagerStartTime.SetToCurrentTime()
+}
+
 // UpdateVeleroMetrics collects and updates all Velero metrics
 func (vm *VeleroMetrics) UpdateVeleroMetrics() error {
 	// Check if Velero is available
@@ -231,6 +330,11 @@ func (vm *VeleroMetrics) UpdateVeleroMetrics() error {
 	}
 	vm.VeleroAvailable.Set(1)
 
+	// A missing version shouldn't stop the rest of the collection
+	if err := vm.updateVersionMetric(); err != nil {
+		log.Printf("⚠️  Failed to read Velero version: %v", err)
+	}
+
 	// Update backup metrics
 	if err := vm.updateBackupMetrics(); err != nil {
 		return err
@@ -251,14 +355,28 @@ func (vm *VeleroMetrics) UpdateVeleroMetrics() error {
 		return err
 	}
 
+	// Update storage location metrics
+	if err := vm.updateStorageLocationMetrics(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// updateVersionMetric sets velero_version_info for the running Velero server
+func (vm *VeleroMetrics) updateVersionMetric() error {
+	version, err := vm.k8sClient.VeleroVersion.Get(context.Background())
+	if err != nil {
+		return err
+	}
+
+	vm.VeleroVersionInfo.Reset()
+	vm.VeleroVersionInfo.WithLabelValues(version.Version).Set(1)
 	return nil
 }
 
 func (vm *VeleroMetrics) updateBackupMetrics() error {
-	backupList, err := vm.k8sClient.DynamicClient.
-		Resource(k8s.BackupGVR).
-		Namespace("velero").
-		List(context.Background(), metav1.ListOptions{})
+	backupList, err := vm.k8sClient.ListCache.List(context.Background(), k8s.BackupGVR, "velero")
 
 	if err != nil {
 		return err
@@ -270,6 +388,18 @@ func (vm *VeleroMetrics) updateBackupMetrics() error {
 	vm.BackupItemsBackedUp.Reset()
 	vm.BackupErrors.Reset()
 	vm.BackupWarnings.Reset()
+	vm.BackupInProgress.Reset()
+
+	// Completed and failed backups per (namespace, schedule, storage location)
+	type backupGroup struct {
+		namespace, schedule, storageLocation string
+	}
+	completed := make(map[backupGroup]int)
+	failed := make(map[backupGroup]int)
+
+	now := time.Now()
+	vm.backupPhases.observe(backupList.Items, &vm.BackupPhaseDuration, now)
+	vm.backupFailures.observe(backupList.Items)
 
 	for _, backup := range backupList.Items {
 		name := backup.GetName()
@@ -283,7 +413,10 @@ func (vm *VeleroMetrics) updateBackupMetrics() error {
 		}
 
 		// Get storage location from spec
-		_ = "default" // Remove unused variable
+		storageLocation := "default"
+		if location, _, _ := unstructured.NestedString(backup.Object, "spec", "storageLocation"); location != "" {
+			storageLocation = location
+		}
 
 		// Process status
 		if status, found := backup.Object["status"]; found {
@@ -295,6 +428,13 @@ func (vm *VeleroMetrics) updateBackupMetrics() error {
 
 				// Count totals instead of incrementing counters repeatedly
 				// (counters will be set to actual counts after the loop)
+				group := backupGroup{namespace: namespace, schedule: schedule, storageLocation: storageLocation}
+				switch phase {
+				case "Completed":
+					completed[group]++
+				case "Failed", "PartiallyFailed":
+					failed[group]++
+				}
 
 				// Update duration if available
 				if startTime, ok := statusMap["startTimestamp"]; ok && startTime != nil {
@@ -312,6 +452,15 @@ func (vm *VeleroMetrics) updateBackupMetrics() error {
 					}
 				}
 
+				// Track how long in-progress backups have been running
+				if phase == "InProgress" {
+					if startStr, ok := statusMap["startTimestamp"].(string); ok {
+						if start, err := time.Parse(time.RFC3339, startStr); err == nil {
+							vm.BackupInProgress.WithLabelValues(namespace, name).Set(now.Sub(start).Seconds())
+						}
+					}
+				}
+
 				// Update item counts
 				if totalItems, ok := statusMap["totalItems"]; ok {
 					if count, ok := totalItems.(float64); ok {
@@ -340,42 +489,21 @@ func (vm *VeleroMetrics) updateBackupMetrics() error {
 		}
 	}
 
-	// Set actual counts after processing all backups
-	totalCompleted := 0
-	totalFailed := 0
-	for _, backup := range backupList.Items {
-		if status, found := backup.Object["status"]; found {
-			if statusMap, ok := status.(map[string]interface{}); ok {
-				if phase, ok := statusMap["phase"].(string); ok {
-					switch phase {
-					case "Completed":
-						totalCompleted++
-					case "Failed", "PartiallyFailed":
-						totalFailed++
-					}
-				}
-			}
-		}
-	}
-
 	// Reset and set correct values using gauges instead of counters for current state
 	vm.BackupSuccessTotal.Reset()
 	vm.BackupFailureTotal.Reset()
-	if totalCompleted > 0 {
-		vm.BackupSuccessTotal.WithLabelValues("velero", "manual", "default").Add(float64(totalCompleted))
+	for group, count := range completed {
+		vm.BackupSuccessTotal.WithLabelValues(group.namespace, group.schedule, group.storageLocation).Add(float64(count))
 	}
-	if totalFailed > 0 {
-		vm.BackupFailureTotal.WithLabelValues("velero", "manual", "default").Add(float64(totalFailed))
+	for group, count := range failed {
+		vm.BackupFailureTotal.WithLabelValues(group.namespace, group.schedule, group.storageLocation).Add(float64(count))
 	}
 
 	return nil
 }
 
 func (vm *VeleroMetrics) updateRestoreMetrics() error {
-	restoreList, err := vm.k8sClient.DynamicClient.
-		Resource(k8s.RestoreGVR).
-		Namespace("velero").
-		List(context.Background(), metav1.ListOptions{})
+	restoreList, err := vm.k8sClient.ListCache.List(context.Background(), k8s.RestoreGVR, "velero")
 
 	if err != nil {
 		return err
@@ -461,10 +589,7 @@ func (vm *VeleroMetrics) updateRestoreMetrics() error {
 }
 
 func (vm *VeleroMetrics) updateScheduleMetrics() error {
-	scheduleList, err := vm.k8sClient.DynamicClient.
-		Resource(k8s.ScheduleGVR).
-		Namespace("velero").
-		List(context.Background(), metav1.ListOptions{})
+	scheduleList, err := vm.k8sClient.ListCache.List(context.Background(), k8s.ScheduleGVR, "velero")
 
 	if err != nil {
 		return err
@@ -474,8 +599,12 @@ func (vm *VeleroMetrics) updateScheduleMetrics() error {
 	vm.ScheduleTotal.Reset()
 	vm.SchedulePaused.Reset()
 	vm.ScheduleLastBackup.Reset()
+	vm.ScheduleOverdue.Reset()
 	vm.ScheduleValidationErrors.Reset()
 
+	now := time.Now()
+	overdueFactor := config.GetBackupConfig().ScheduleOverdueFactor
+
 	totalSchedules := 0
 	pausedSchedules := 0
 
@@ -486,14 +615,27 @@ func (vm *VeleroMetrics) updateScheduleMetrics() error {
 		totalSchedules++
 
 		// Check if schedule is paused
+		paused := false
 		if spec, found := schedule.Object["spec"]; found {
 			if specMap, ok := spec.(map[string]interface{}); ok {
-				if paused, ok := specMap["paused"].(bool); ok && paused {
+				if p, ok := specMap["paused"].(bool); ok && p {
+					paused = true
 					pausedSchedules++
 				}
 			}
 		}
 
+		// Paused schedules and invalid cron expressions have no expected run
+		if !paused {
+			if _, deadline, err := k8s.ScheduleDeadline(&schedule, overdueFactor); err == nil {
+				overdue := 0.0
+				if !deadline.IsZero() && now.After(deadline) {
+					overdue = 1
+				}
+				vm.ScheduleOverdue.WithLabelValues(namespace, name).Set(overdue)
+			}
+		}
+
 		// Process status
 		if status, found := schedule.Object["status"]; found {
 			if statusMap, ok := status.(map[string]interface{}); ok {
@@ -528,77 +670,121 @@ func (vm *VeleroMetrics) updateScheduleMetrics() error {
 	return nil
 }
 
+func (vm *VeleroMetrics) updateStorageLocationMetrics() error {
+	locationList, err := vm.k8sClient.DynamicClient.
+		Resource(k8s.BackupStorageLocationGVR).
+		Namespace("velero").
+		List(context.Background(), metav1.ListOptions{})
+
+	if err != nil {
+		return err
+	}
+
+	// Reset gauges to avoid stale metrics for deleted locations
+	vm.StorageLocationAvailable.Reset()
+
+	for _, location := range locationList.Items {
+		provider := "unknown"
+		if spec, ok := location.Object["spec"].(map[string]interface{}); ok {
+			if p, ok := spec["provider"].(string); ok && p != "" {
+				provider = p
+			}
+		}
+
+		available := 0.0
+		if status, ok := location.Object["status"].(map[string]interface{}); ok {
+			if phase, ok := status["phase"].(string); ok && phase == "Available" {
+				available = 1.0
+			}
+		}
+
+		vm.StorageLocationAvailable.WithLabelValues(location.GetName(), provider).Set(available)
+	}
+
+	return nil
+}
+
 // RecordAPIRequest records API request metrics
 func (vm *VeleroMetrics) RecordAPIRequest(method, endpoint string, statusCode int, duration time.Duration) {
 	vm.APIRequestsTotal.WithLabelValues(method, endpoint, strconv.Itoa(statusCode)).Inc()
 	vm.APIRequestDuration.WithLabelValues(method, endpoint).Observe(duration.Seconds())
 }
 
-// extractClusterFromBackupName parses cluster name from backup naming convention
-func extractClusterFromBackupName(backupName string) string {
-	parts := strings.Split(backupName, "-daily-backup-")
-	if len(parts) >= 2 {
-		return parts[0]
-	}
-
-	// Fallback for other naming patterns
-	if strings.Contains(backupName, "-centralized-") {
-		parts = strings.Split(backupName, "-centralized-")
-		if len(parts) >= 2 {
-			return parts[0]
+// backupCompletionTime returns when a backup finished, falling back to its creation time
+func backupCompletionTime(backup map[string]interface{}) time.Time {
+	if status, ok := backup["status"].(map[string]interface{}); ok {
+		if completion, ok := status["completionTimestamp"].(string); ok {
+			if completed, err := time.Parse(time.RFC3339, completion); err == nil {
+				return completed
+			}
 		}
 	}
 
-	return "unknown"
+	creation := (&unstructured.Unstructured{Object: backup}).GetCreationTimestamp()
+	return creation.Time
 }
 
 // updateClusterMetrics collects and updates cluster-based metrics
 func (vm *VeleroMetrics) updateClusterMetrics() error {
 	// Get all backups to calculate cluster metrics
-	backupList, err := vm.k8sClient.DynamicClient.
-		Resource(k8s.BackupGVR).
-		Namespace("velero").
-		List(context.Background(), metav1.ListOptions{})
+	backupList, err := vm.k8sClient.ListCache.List(context.Background(), k8s.BackupGVR, "velero")
 
 	if err != nil {
 		return err
 	}
 
 	// Get all restores for cluster restore metrics
-	restoreList, _ := vm.k8sClient.DynamicClient.
-		Resource(k8s.RestoreGVR).
-		Namespace("velero").
-		List(context.Background(), metav1.ListOptions{})
+	restoreList, _ := vm.k8sClient.ListCache.List(context.Background(), k8s.RestoreGVR, "velero")
 
 	// Reset cluster metrics
 	vm.ClusterHealthStatus.Reset()
 	vm.ClusterBackupSuccessRate.Reset()
 	vm.ClusterRestoreSuccessRate.Reset()
 	vm.ClusterLastBackupTime.Reset()
+	vm.ClusterLastSuccessAge.Reset()
 	vm.ClusterBackupTotal.Reset()
 	vm.ClusterRestoreTotal.Reset()
+	vm.ClusterBackupSizeBytes.Reset()
+	vm.StorageLocationBackupSizeBytes.Reset()
+
+	// Backup bytes per storage location, including backups not tied to a cluster
+	locationSizes := make(map[string]float64)
 
 	// Build cluster statistics
 	clusterStats := make(map[string]struct {
-		totalBackups       int
-		successfulBackups  int
-		failedBackups      int
-		lastBackup         time.Time
-		totalRestores      int
-		successfulRestores int
-		failedRestores     int
+		totalBackups           int
+		successfulBackups      int
+		failedBackups          int
+		partiallyFailedBackups int
+		lastBackup             time.Time
+		lastSuccessfulBackup   time.Time
+		totalRestores          int
+		successfulRestores     int
+		failedRestores         int
+		sizeBytes              float64
 	})
 
 	// Process backups
 	if backupList != nil {
 		for _, backup := range backupList.Items {
-			clusterName := extractClusterFromBackupName(backup.GetName())
-			if clusterName == "unknown" {
+			sizeBytes := backupSizeBytes(backup.Object)
+
+			storageLocation := "default"
+			if spec, ok := backup.Object["spec"].(map[string]interface{}); ok {
+				if location, ok := spec["storageLocation"].(string); ok && location != "" {
+					storageLocation = location
+				}
+			}
+			locationSizes[storageLocation] += sizeBytes
+
+			clusterName := k8s.BackupCluster(&backup)
+			if clusterName == k8s.UnknownCluster {
 				continue
 			}
 
 			stats := clusterStats[clusterName]
 			stats.totalBackups++
+			stats.sizeBytes += sizeBytes
 
 			// Get backup status and timing
 			if status, found := backup.Object["status"]; found {
@@ -607,6 +793,11 @@ func (vm *VeleroMetrics) updateClusterMetrics() error {
 						switch phase {
 						case "Completed":
 							stats.successfulBackups++
+							if completed := backupCompletionTime(backup.Object); completed.After(stats.lastSuccessfulBackup) {
+								stats.lastSuccessfulBackup = completed
+							}
+						case "PartiallyFailed":
+							stats.partiallyFailedBackups++
 						case "Failed", "FailedValidation":
 							stats.failedBackups++
 						}
@@ -627,18 +818,8 @@ func (vm *VeleroMetrics) updateClusterMetrics() error {
 	// Process restores
 	if restoreList != nil {
 		for _, restore := range restoreList.Items {
-			// Get cluster name from backup name in restore spec
-			backupName := ""
-			if spec, found := restore.Object["spec"]; found {
-				if specMap, ok := spec.(map[string]interface{}); ok {
-					if bn, ok := specMap["backupName"].(string); ok {
-						backupName = bn
-					}
-				}
-			}
-
-			clusterName := extractClusterFromBackupName(backupName)
-			if clusterName == "unknown" {
+			clusterName := k8s.RestoreCluster(&restore)
+			if clusterName == k8s.UnknownCluster {
 				continue
 			}
 
@@ -663,50 +844,109 @@ func (vm *VeleroMetrics) updateClusterMetrics() error {
 		}
 	}
 
+	environments := vm.clusterEnvironments()
+
 	// Update Prometheus metrics for each cluster
 	for clusterName, stats := range clusterStats {
+		environment := environments[clusterName]
+		if environment == "" {
+			environment = "unknown"
+		}
+
 		// Calculate backup success rate
 		backupSuccessRate := 0.0
 		if stats.totalBackups > 0 {
 			backupSuccessRate = float64(stats.successfulBackups) / float64(stats.totalBackups) * 100
 		}
-		vm.ClusterBackupSuccessRate.WithLabelValues(clusterName).Set(backupSuccessRate)
+		vm.ClusterBackupSuccessRate.WithLabelValues(clusterName, environment).Set(backupSuccessRate)
 
 		// Calculate restore success rate
 		restoreSuccessRate := 0.0
 		if stats.totalRestores > 0 {
 			restoreSuccessRate = float64(stats.successfulRestores) / float64(stats.totalRestores) * 100
 		}
-		vm.ClusterRestoreSuccessRate.WithLabelValues(clusterName).Set(restoreSuccessRate)
+		vm.ClusterRestoreSuccessRate.WithLabelValues(clusterName, environment).Set(restoreSuccessRate)
 
 		// Set health status (0=critical, 1=no-backups, 2=warning, 3=healthy)
 		healthStatus := 1.0 // no-backups
 		if stats.totalBackups == 0 {
 			healthStatus = 1.0 // no-backups
-		} else if stats.failedBackups > 0 && stats.successfulBackups == 0 {
+		} else if stats.failedBackups > 0 && stats.successfulBackups == 0 && stats.partiallyFailedBackups == 0 {
 			healthStatus = 0.0 // critical
 		} else if backupSuccessRate < 70 {
-			healthStatus = 2.0 // warning
+			healthStatus = 2.0 // warning (includes high partial-failure rates)
 		} else {
 			healthStatus = 3.0 // healthy
 		}
-		vm.ClusterHealthStatus.WithLabelValues(clusterName).Set(healthStatus)
+		vm.ClusterHealthStatus.WithLabelValues(clusterName, environment).Set(healthStatus)
 
 		// Set last backup timestamp
 		if !stats.lastBackup.IsZero() {
-			vm.ClusterLastBackupTime.WithLabelValues(clusterName).Set(float64(stats.lastBackup.Unix()))
+			vm.ClusterLastBackupTime.WithLabelValues(clusterName, environment).Set(float64(stats.lastBackup.Unix()))
+		}
+
+		// Age of the last Completed backup, for simple staleness alerts
+		if !stats.lastSuccessfulBackup.IsZero() {
+			vm.ClusterLastSuccessAge.WithLabelValues(clusterName, environment).Set(time.Since(stats.lastSuccessfulBackup).Seconds())
 		}
 
 		// Set backup totals by status
-		vm.ClusterBackupTotal.WithLabelValues(clusterName, "successful").Set(float64(stats.successfulBackups))
-		vm.ClusterBackupTotal.WithLabelValues(clusterName, "failed").Set(float64(stats.failedBackups))
-		vm.ClusterBackupTotal.WithLabelValues(clusterName, "total").Set(float64(stats.totalBackups))
+		vm.ClusterBackupTotal.WithLabelValues(clusterName, environment, "successful").Set(float64(stats.successfulBackups))
+		vm.ClusterBackupTotal.WithLabelValues(clusterName, environment, "failed").Set(float64(stats.failedBackups))
+		vm.ClusterBackupTotal.WithLabelValues(clusterName, environment, "partially_failed").Set(float64(stats.partiallyFailedBackups))
+		vm.ClusterBackupTotal.WithLabelValues(clusterName, environment, "total").Set(float64(stats.totalBackups))
 
 		// Set restore totals by status
-		vm.ClusterRestoreTotal.WithLabelValues(clusterName, "successful").Set(float64(stats.successfulRestores))
-		vm.ClusterRestoreTotal.WithLabelValues(clusterName, "failed").Set(float64(stats.failedRestores))
-		vm.ClusterRestoreTotal.WithLabelValues(clusterName, "total").Set(float64(stats.totalRestores))
+		vm.ClusterRestoreTotal.WithLabelValues(clusterName, environment, "successful").Set(float64(stats.successfulRestores))
+		vm.ClusterRestoreTotal.WithLabelValues(clusterName, environment, "failed").Set(float64(stats.failedRestores))
+		vm.ClusterRestoreTotal.WithLabelValues(clusterName, environment, "total").Set(float64(stats.totalRestores))
+
+		vm.ClusterBackupSizeBytes.WithLabelValues(clusterName, environment).Set(stats.sizeBytes)
+	}
+
+	for location, sizeBytes := range locationSizes {
+		vm.StorageLocationBackupSizeBytes.WithLabelValues(location).Set(sizeBytes)
 	}
 
 	return nil
 }
+
+// backupSizeBytes reads the backup size reported in status, or 0 when it isn't set
+func backupSizeBytes(backup map[string]interface{}) float64 {
+	status, ok := backup["status"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+
+	switch size := status["backupSizeBytes"].(type) {
+	case int64:
+		return float64(size)
+	case float64:
+		return size
+	}
+
+	return 0
+}
+
+// clusterEnvironments maps cluster names to the environment annotated on their
+// credentials secret. Clusters without the annotation are left out.
+func (vm *VeleroMetrics) clusterEnvironments() map[string]string {
+	environments := make(map[string]string)
+
+	secrets, err := vm.k8sClient.Clientset.CoreV1().
+		Secrets("velero").
+		List(context.Background(), metav1.ListOptions{LabelSelector: "velero.io/cluster"})
+
+	if err != nil {
+		return environments
+	}
+
+	for _, secret := range secrets.Items {
+		cluster := secret.Labels["velero.io/cluster"]
+		if environment := secret.Annotations[k8s.ClusterEnvironmentAnnotation]; cluster != "" && environment != "" {
+			environments[cluster] = environment
+		}
+	}
+
+	return environments
+}
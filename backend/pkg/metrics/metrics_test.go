@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"testing"
+	"velero-manager/pkg/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterMetricsCarryEnvironment(t *testing.T) {
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "prod-credentials",
+			Namespace:   "velero",
+			Labels:      map[string]string{"velero.io/cluster": "prod"},
+			Annotations: map[string]string{k8s.ClusterEnvironmentAnnotation: "production"},
+		},
+	}
+	completed := map[string]interface{}{"phase": "Completed"}
+	vm := newTestMetrics(newTestClient(
+		credentials,
+		newBackup("prod-1", map[string]string{k8s.ClusterLabel: "prod"}, completed),
+		newBackup("dev-1", map[string]string{k8s.ClusterLabel: "dev"}, completed),
+	))
+
+	if err := vm.updateClusterMetrics(); err != nil {
+		t.Fatalf("updateClusterMetrics: %v", err)
+	}
+
+	want := map[string]string{"prod": "production", "dev": "unknown"}
+	for _, name := range []string{
+		"velero_cluster_health_status",
+		"velero_cluster_backup_success_rate",
+		"velero_cluster_backup_size_bytes",
+		"velero_cluster_backup_total",
+	} {
+		samples := series(t, name)
+		if len(samples) == 0 {
+			t.Errorf("%s: no series", name)
+		}
+		for _, sample := range samples {
+			labels := sample.labels
+			environment, ok := labels["environment"]
+			if !ok {
+				t.Errorf("%s%v: no environment label", name, labels)
+				continue
+			}
+			if environment != want[labels["cluster"]] {
+				t.Errorf("%s%v: environment = %q, want %q", name, labels, environment, want[labels["cluster"]])
+			}
+		}
+	}
+}
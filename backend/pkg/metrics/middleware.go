@@ -1,11 +1,16 @@
 package metrics
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// unrecordedRoutes are route templates (or template prefixes) that are never recorded:
+// scrapes of /metrics itself and static frontend files
+var unrecordedRoutes = []string{"/metrics", "/static/", "/favicon.ico", "/manifest.json"}
+
 // PrometheusMiddleware returns a Gin middleware that records metrics for HTTP requests
 func (vm *VeleroMetrics) PrometheusMiddleware() gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
@@ -14,17 +19,20 @@ func (vm *VeleroMetrics) PrometheusMiddleware() gin.HandlerFunc {
 		// Process request
 		c.Next()
 
-		// Record metrics after request completion
-		duration := time.Since(start)
-		method := c.Request.Method
+		// Always label by the route template (e.g. /api/v1/backups/:name) so concrete
+		// parameter values never create new series
 		endpoint := c.FullPath()
-		statusCode := c.Writer.Status()
-
-		// Clean up endpoint path for metrics (remove parameter values)
 		if endpoint == "" {
+			// Unmatched routes share one series whatever the requested path
 			endpoint = "unknown"
 		}
 
-		vm.RecordAPIRequest(method, endpoint, statusCode, duration)
+		for _, route := range unrecordedRoutes {
+			if strings.HasPrefix(endpoint, route) {
+				return
+			}
+		}
+
+		vm.RecordAPIRequest(c.Request.Method, endpoint, c.Writer.Status(), time.Since(start))
 	})
 }
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPrometheusMiddlewareLabelsByRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	vm := newTestMetrics(newTestClient())
+
+	router := gin.New()
+	router.Use(vm.PrometheusMiddleware())
+	router.GET("/api/v1/backups/:name", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/metrics", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/api/v1/backups/foo", "/api/v1/backups/bar", "/metrics", "/nowhere/1", "/nowhere/2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	requests := make(map[string]float64)
+	for _, sample := range series(t, "velero_manager_api_requests_total") {
+		requests[sample.labels["endpoint"]] += sample.value
+	}
+
+	if got := requests["/api/v1/backups/:name"]; got != 2 {
+		t.Errorf("/api/v1/backups/:name counted %v requests, want 2 in one series", got)
+	}
+	if got := requests["unknown"]; got != 2 {
+		t.Errorf("unmatched routes counted %v requests under unknown, want 2", got)
+	}
+	for _, endpoint := range []string{"/api/v1/backups/foo", "/api/v1/backups/bar", "/metrics", "/nowhere/1"} {
+		if _, ok := requests[endpoint]; ok {
+			t.Errorf("series recorded for %s", endpoint)
+		}
+	}
+
+	durations := 0
+	for _, sample := range series(t, "velero_manager_api_request_duration_seconds") {
+		if sample.labels["endpoint"] == "/api/v1/backups/:name" {
+			durations++
+		}
+	}
+	if durations != 1 {
+		t.Errorf("%d duration series for /api/v1/backups/:name, want 1", durations)
+	}
+}
@@ -8,12 +8,15 @@ import (
 // GenerateMockData populates metrics with realistic test data
 func (vm *VeleroMetrics) GenerateMockData() {
 	clusters := []string{"core-cl1", "staging-cl2", "dev-cl3"}
+	environments := map[string]string{"core-cl1": "prod", "staging-cl2": "staging", "dev-cl3": "dev"}
 	namespaces := []string{"production", "staging", "development"}
 	schedules := []string{"daily-backup", "weekly-backup", "hourly-snapshot"}
 	storageLocations := []string{"aws-s3", "minio-local", "azure-blob"}
 
 	// Generate cluster health data
 	for _, cluster := range clusters {
+		environment := environments[cluster]
+
 		// Most clusters healthy (3), some warnings (2), rare critical (0-1)
 		healthStatus := 3.0
 		if rand.Float32() < 0.1 {
@@ -22,27 +25,38 @@ func (vm *VeleroMetrics) GenerateMockData() {
 		if rand.Float32() < 0.02 {
 			healthStatus = 0.0 // Critical
 		}
-		vm.ClusterHealthStatus.WithLabelValues(cluster).Set(healthStatus)
+		vm.ClusterHealthStatus.WithLabelValues(cluster, environment).Set(healthStatus)
 
 		// Backup success rate (85-99%)
 		successRate := 85 + rand.Float64()*14
-		vm.ClusterBackupSuccessRate.WithLabelValues(cluster).Set(successRate)
+		vm.ClusterBackupSuccessRate.WithLabelValues(cluster, environment).Set(successRate)
 
 		// Restore success rate (90-100%)
 		restoreRate := 90 + rand.Float64()*10
-		vm.ClusterRestoreSuccessRate.WithLabelValues(cluster).Set(restoreRate)
+		vm.ClusterRestoreSuccessRate.WithLabelValues(cluster, environment).Set(restoreRate)
 
 		// Last backup timestamp (within last 24 hours)
 		lastBackup := time.Now().Add(-time.Duration(rand.Intn(24)) * time.Hour).Unix()
-		vm.ClusterLastBackupTime.WithLabelValues(cluster).Set(float64(lastBackup))
+		vm.ClusterLastBackupTime.WithLabelValues(cluster, environment).Set(float64(lastBackup))
+		vm.ClusterLastSuccessAge.WithLabelValues(cluster, environment).Set(float64(time.Now().Unix() - lastBackup))
 
 		// Total backups (50-500)
 		totalBackups := float64(50 + rand.Intn(450))
-		vm.ClusterBackupTotal.WithLabelValues(cluster, "total").Set(totalBackups)
+		vm.ClusterBackupTotal.WithLabelValues(cluster, environment, "total").Set(totalBackups)
 
 		// Total restores (5-50)
 		totalRestores := float64(5 + rand.Intn(45))
-		vm.ClusterRestoreTotal.WithLabelValues(cluster, "total").Set(totalRestores)
+		vm.ClusterRestoreTotal.WithLabelValues(cluster, environment, "total").Set(totalRestores)
+
+		// Stored backup bytes (10GB-500GB)
+		clusterSize := float64(10*1024*1024*1024) + rand.Float64()*490*1024*1024*1024
+		vm.ClusterBackupSizeBytes.WithLabelValues(cluster, environment).Set(clusterSize)
+	}
+
+	for _, location := range storageLocations {
+		locationSize := float64(50*1024*1024*1024) + rand.Float64()*950*1024*1024*1024
+		vm.StorageLocationBackupSizeBytes.WithLabelValues(location).Set(locationSize)
+		vm.StorageLocationAvailable.WithLabelValues(location, "aws").Set(1)
 	}
 
 	// Generate backup/restore operation data
@@ -62,6 +76,8 @@ func (vm *VeleroMetrics) GenerateMockData() {
 		// Backup duration (30s to 3600s)
 		duration := 30 + rand.Float64()*3570
 		vm.BackupDuration.WithLabelValues(namespace, schedule, "Completed").Observe(duration)
+		vm.BackupPhaseDuration.WithLabelValues("InProgress").Observe(duration * 0.8)
+		vm.BackupPhaseDuration.WithLabelValues("Finalizing").Observe(duration * 0.2)
 
 		// Backup size (100MB to 50GB)
 		sizeBytes := float64(100*1024*1024 + rand.Intn(50*1024*1024*1024))
@@ -119,6 +135,7 @@ func (vm *VeleroMetrics) GenerateMockData() {
 
 	// Set Velero availability
 	vm.VeleroAvailable.Set(1) // Available
+	vm.VeleroVersionInfo.WithLabelValues("v1.14.0").Set(1)
 
 	// Generate some API request metrics
 	apiEndpoints := []string{"/api/v1/backups", "/api/v1/restores", "/api/v1/schedules", "/api/v1/clusters"}
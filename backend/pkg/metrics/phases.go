@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// trackedPhase is the phase an object was last seen in and since when
+type trackedPhase struct {
+	phase string
+	since time.Time
+}
+
+// phaseTracker measures how long objects spend in each non-terminal phase by diffing
+// successive collections. Transitions are only seen once per collection, so durations are
+// accurate to the collection interval except where Velero records the boundary itself:
+// startTimestamp, finalizingTimestamp (when present) and completionTimestamp.
+type phaseTracker struct {
+	terminal map[string]bool
+	phases   map[types.UID]trackedPhase
+}
+
+func newPhaseTracker(terminalPhases ...string) *phaseTracker {
+	terminal := make(map[string]bool, len(terminalPhases))
+	for _, phase := range terminalPhases {
+		terminal[phase] = true
+	}
+	return &phaseTracker{
+		terminal: terminal,
+		phases:   make(map[types.UID]trackedPhase),
+	}
+}
+
+// observe records the time spent in phases the objects left since the last collection
+// and forgets objects that are gone. Objects first seen in a terminal phase finished
+// before tracking started and are skipped.
+func (pt *phaseTracker) observe(items []unstructured.Unstructured, histogram *prometheus.HistogramVec, now time.Time) {
+	seen := make(map[types.UID]bool, len(items))
+
+	for _, item := range items {
+		uid := item.GetUID()
+		seen[uid] = true
+
+		phase, _, _ := unstructured.NestedString(item.Object, "status", "phase")
+		if phase == "" {
+			phase = "New"
+		}
+
+		tracked, found := pt.phases[uid]
+		if !found {
+			if !pt.terminal[phase] {
+				pt.phases[uid] = trackedPhase{phase: phase, since: phaseStart(item, phase, now)}
+			}
+			continue
+		}
+		if tracked.phase == phase {
+			continue
+		}
+
+		// Velero's own timestamps mark some transitions more precisely than the collection
+		boundary := now
+		if pt.terminal[phase] {
+			boundary = statusTime(item, "completionTimestamp", now)
+		} else if phase == "Finalizing" {
+			boundary = statusTime(item, "finalizingTimestamp", now)
+		}
+
+		if duration := boundary.Sub(tracked.since); duration >= 0 {
+			histogram.WithLabelValues(tracked.phase).Observe(duration.Seconds())
+		}
+
+		if pt.terminal[phase] {
+			delete(pt.phases, uid)
+		} else {
+			pt.phases[uid] = trackedPhase{phase: phase, since: boundary}
+		}
+	}
+
+	for uid := range pt.phases {
+		if !seen[uid] {
+			delete(pt.phases, uid)
+		}
+	}
+}
+
+// phaseStart estimates when an object first seen in a phase entered it
+func phaseStart(item unstructured.Unstructured, phase string, now time.Time) time.Time {
+	switch phase {
+	case "New":
+		return item.GetCreationTimestamp().Time
+	case "InProgress":
+		return statusTime(item, "startTimestamp", now)
+	case "Finalizing":
+		return statusTime(item, "finalizingTimestamp", now)
+	}
+	return now
+}
+
+// statusTime parses a timestamp from an object's status, or returns fallback
+func statusTime(item unstructured.Unstructured, field string, fallback time.Time) time.Time {
+	value, found, _ := unstructured.NestedString(item.Object, "status", field)
+	if !found {
+		return fallback
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ActivityRecorder records that an authenticated user made a request
+type ActivityRecorder interface {
+	RecordActivity(username, authMethod, sourceIP string)
+}
+
+// TrackActivity records the caller of every authenticated request; it must run after the
+// auth middleware has set the username
+func TrackActivity(recorder ActivityRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if username := c.GetString("username"); username != "" {
+			recorder.RecordActivity(username, c.GetString("auth_method"), c.ClientIP())
+		}
+		c.Next()
+	}
+}
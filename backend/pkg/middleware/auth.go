@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"velero-manager/pkg/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -19,9 +20,10 @@ var jwtSecret = []byte("velero-manager-secret-key-change-in-production")
 
 // Session store with expiration
 type Session struct {
-	Username string
-	Role     string
-	Expiry   time.Time
+	Username           string
+	Role               string
+	MustChangePassword bool
+	Expiry             time.Time
 }
 
 var (
@@ -29,12 +31,22 @@ var (
 	sessionMutex = sync.RWMutex{}
 )
 
+// SessionTTL is how long issued JWTs stay valid
+const SessionTTL = 24 * time.Hour
+
 // RevokedTokens stores revoked session IDs
 var (
 	revokedSessions = make(map[string]time.Time)
 	revokeMutex     = sync.RWMutex{}
 )
 
+// issuedSessions maps username to the IDs and expiries of the JWT sessions issued to
+// them, so all of a user's sessions can be revoked at once
+var (
+	issuedSessions = make(map[string]map[string]time.Time)
+	issuedMutex    = sync.Mutex{}
+)
+
 // Generate secure random token
 func generateSecureToken() string {
 	bytes := make([]byte, 32)
@@ -49,6 +61,8 @@ type Claims struct {
 	ConfigVersion string `json:"config_version,omitempty"` // Track config version
 	SessionID     string `json:"session_id,omitempty"`     // Track session for revocation
 	AuthMethod    string `json:"auth_method,omitempty"`    // oidc or legacy
+	// Limits the token to changing the password; see RequirePasswordChange
+	MustChangePassword bool `json:"must_change_password,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -57,29 +71,47 @@ func CreateJWTToken(username, role string) (string, error) {
 	return CreateJWTTokenWithConfig(username, role, "", "legacy")
 }
 
+// CreateUserJWTToken creates a legacy JWT for a user managed by velero-manager. The token
+// of a user who must change their password only allows changing it.
+func CreateUserJWTToken(username, role string, mustChangePassword bool) (string, error) {
+	return createJWTToken(&Claims{
+		Username:           username,
+		Role:               role,
+		AuthMethod:         "legacy",
+		MustChangePassword: mustChangePassword,
+	})
+}
+
 // CreateJWTTokenWithConfig creates JWT with additional options
 func CreateJWTTokenWithConfig(username, role, configVersion, authMethod string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour) // 24 hour expiry
-	sessionID := generateSecureToken()[:16]          // Shorter session ID
-
-	claims := &Claims{
+	tokenString, err := createJWTToken(&Claims{
 		Username:      username,
 		Role:          role,
 		ConfigVersion: configVersion,
-		SessionID:     sessionID,
 		AuthMethod:    authMethod,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	})
+
+	if err == nil && authMethod == "oidc" {
+		log.Printf("Created JWT for OIDC user %s with role %s, config %s",
+			username, role, configVersion)
+	}
+
+	return tokenString, err
+}
+
+// createJWTToken signs claims as a new session that expires after SessionTTL
+func createJWTToken(claims *Claims) (string, error) {
+	expirationTime := time.Now().Add(SessionTTL)
+	claims.SessionID = generateSecureToken()[:16] // Shorter session ID
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expirationTime),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString(jwtSecret)
-
-	if err == nil && authMethod == "oidc" {
-		log.Printf("Created JWT for OIDC user %s with role %s, session %s, config %s",
-			username, role, sessionID, configVersion)
+	if err == nil {
+		trackSession(claims.Username, claims.SessionID, expirationTime)
 	}
 
 	return tokenString, err
@@ -124,6 +156,49 @@ func RevokeSession(sessionID string) {
 	log.Printf("Session %s has been revoked", sessionID)
 }
 
+// trackSession records a JWT session issued to username
+func trackSession(username, sessionID string, expiry time.Time) {
+	issuedMutex.Lock()
+	defer issuedMutex.Unlock()
+	if issuedSessions[username] == nil {
+		issuedSessions[username] = make(map[string]time.Time)
+	}
+	issuedSessions[username][sessionID] = expiry
+}
+
+// RevokeUserSessions revokes every unexpired JWT session issued to username and removes
+// their session-store entries, returning how many were revoked
+func RevokeUserSessions(username string) int {
+	now := time.Now()
+	revoked := 0
+
+	issuedMutex.Lock()
+	sessionIDs := issuedSessions[username]
+	delete(issuedSessions, username)
+	issuedMutex.Unlock()
+
+	for sessionID, expiry := range sessionIDs {
+		if now.Before(expiry) && !IsSessionRevoked(sessionID) {
+			RevokeSession(sessionID)
+			revoked++
+		}
+	}
+
+	sessionMutex.Lock()
+	for token, session := range userSessions {
+		if session.Username == username {
+			delete(userSessions, token)
+			if now.Before(session.Expiry) {
+				revoked++
+			}
+		}
+	}
+	sessionMutex.Unlock()
+
+	log.Printf("Revoked %d sessions of user %s", revoked, username)
+	return revoked
+}
+
 // IsSessionRevoked checks if a session has been revoked
 func IsSessionRevoked(sessionID string) bool {
 	revokeMutex.RLock()
@@ -145,12 +220,19 @@ func IsSessionRevoked(sessionID string) bool {
 
 // Store session (fallback for non-JWT clients)
 func StoreSession(username, role, token string) {
+	StoreUserSession(username, role, token, false)
+}
+
+// StoreUserSession stores a session for a user managed by velero-manager, limited to
+// changing the password like their JWT if they must change it
+func StoreUserSession(username, role, token string, mustChangePassword bool) {
 	sessionMutex.Lock()
 	defer sessionMutex.Unlock()
 	userSessions[token] = Session{
-		Username: username,
-		Role:     role,
-		Expiry:   time.Now().Add(24 * time.Hour),
+		Username:           username,
+		Role:               role,
+		MustChangePassword: mustChangePassword,
+		Expiry:             time.Now().Add(24 * time.Hour),
 	}
 }
 
@@ -173,6 +255,19 @@ func CleanExpiredSessions() {
 			delete(revokedSessions, sessionID)
 		}
 	}
+
+	issuedMutex.Lock()
+	defer issuedMutex.Unlock()
+	for username, sessions := range issuedSessions {
+		for sessionID, expiry := range sessions {
+			if now.After(expiry) {
+				delete(sessions, sessionID)
+			}
+		}
+		if len(sessions) == 0 {
+			delete(issuedSessions, username)
+		}
+	}
 }
 
 // ClearSession removes a specific session
@@ -210,6 +305,7 @@ func RequireAuth() gin.HandlerFunc {
 			c.Set("auth_method", claims.AuthMethod)
 			c.Set("session_id", claims.SessionID)
 			c.Set("config_version", claims.ConfigVersion)
+			c.Set(mustChangePasswordKey, claims.MustChangePassword)
 			c.Next()
 			return
 		} else if err != nil {
@@ -257,13 +353,15 @@ func RequireAuth() gin.HandlerFunc {
 		c.Set("username", session.Username)
 		c.Set("role", session.Role)
 		c.Set("auth_method", "session")
+		c.Set(mustChangePasswordKey, session.MustChangePassword)
 		c.Next()
 	}
 }
 
-// UserValidator interface to avoid circular dependency
+// UserValidator interface to avoid circular dependency. GetUser returns nil for users
+// velero-manager doesn't manage, such as OIDC users.
 type UserValidator interface {
-	GetUsers() (map[string]interface{}, error)
+	GetUser(username string) (map[string]interface{}, error)
 }
 
 var globalUserValidator UserValidator
@@ -291,20 +389,16 @@ func RequireAdmin() gin.HandlerFunc {
 
 		// If we have a validator, use it as fallback
 		if globalUserValidator != nil {
-			users, err := globalUserValidator.GetUsers()
+			user, err := globalUserValidator.GetUser(username)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify admin access"})
 				c.Abort()
 				return
 			}
 
-			if user, exists := users[username]; exists {
-				if userMap, ok := user.(map[string]interface{}); ok {
-					if userRole, ok := userMap["role"].(string); ok && userRole == "admin" {
-						c.Next()
-						return
-					}
-				}
+			if userRole, ok := user["role"].(string); ok && userRole == "admin" {
+				c.Next()
+				return
 			}
 		}
 
@@ -313,6 +407,55 @@ func RequireAdmin() gin.HandlerFunc {
 	}
 }
 
+// RequireWriteAccess blocks read-only viewers from routes that create, change or delete
+// anything; every other authenticated role passes
+func RequireWriteAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("username") == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
+
+		if role := c.GetString("role"); role == config.ViewerRole || role == config.NoAccessRole {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Write access required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// passwordChangePath is the only route a user who must change their password may call
+const passwordChangePath = "/api/v1/users/:username/password"
+
+// mustChangePasswordKey is the context key the auth middleware sets from the token
+const mustChangePasswordKey = "must_change_password"
+
+// RequirePasswordChange blocks users flagged to change their password from everything
+// except changing it. The flag is read from the caller's token, which is reissued
+// without it once the password is changed.
+func RequirePasswordChange() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool(mustChangePasswordKey) {
+			c.Next()
+			return
+		}
+
+		if c.FullPath() == passwordChangePath && c.Param("username") == c.GetString("username") {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":              "Password change required",
+			"mustChangePassword": true,
+		})
+		c.Abort()
+	}
+}
+
 // Global OIDC provider reference for config validation
 var globalOIDCProvider interface {
 	GetConfigVersion() string
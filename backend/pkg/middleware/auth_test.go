@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequirePasswordChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	protected := router.Group("/api/v1")
+	protected.Use(RequireAuth(), RequirePasswordChange())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	protected.GET("/backups", ok)
+	protected.PUT("/users/:username/password", ok)
+
+	mustChange, err := CreateUserJWTToken("alice", "user", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := CreateUserJWTToken("alice", "user", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	StoreUserSession("alice", "user", "session-must-change", true)
+	defer ClearSession("session-must-change")
+
+	tests := []struct {
+		token  string
+		method string
+		path   string
+		want   int
+	}{
+		{mustChange, http.MethodGet, "/api/v1/backups", http.StatusForbidden},
+		{mustChange, http.MethodPut, "/api/v1/users/alice/password", http.StatusOK},
+		{mustChange, http.MethodPut, "/api/v1/users/bob/password", http.StatusForbidden},
+		{"session-must-change", http.MethodGet, "/api/v1/backups", http.StatusForbidden},
+		{"session-must-change", http.MethodPut, "/api/v1/users/alice/password", http.StatusOK},
+		{changed, http.MethodGet, "/api/v1/backups", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s %s: status = %d, want %d", tt.method, tt.path, w.Code, tt.want)
+		}
+	}
+}
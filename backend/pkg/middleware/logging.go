@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLogger returns a Gin middleware that writes a structured access log entry per
+// request, skipping any request whose path starts with one of excludedPaths
+func RequestLogger(excludedPaths []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for _, excluded := range excludedPaths {
+			if strings.HasPrefix(path, excluded) {
+				c.Next()
+				return
+			}
+		}
+
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		slog.Log(c.Request.Context(), level, "request",
+			"request_id", c.GetString(RequestIDKey),
+			"username", c.GetString("username"),
+			"method", c.Request.Method,
+			"path", path,
+			"query", c.Request.URL.RawQuery,
+			"status", status,
+			"latency", time.Since(start).String(),
+			"ip", c.ClientIP(),
+		)
+	}
+}
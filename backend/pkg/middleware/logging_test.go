@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestLoggerSkipsExcludedPaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+
+	router := gin.New()
+	router.Use(RequestLogger([]string{"/api/v1/health", "/static/"}))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/v1/health", ok)
+	router.GET("/static/js/main.js", ok)
+	router.GET("/api/v1/backups", ok)
+
+	for _, path := range []string{"/api/v1/health", "/static/js/main.js", "/api/v1/backups"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: status %d", path, w.Code)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d access log entries, want 1:\n%s", len(lines), logs.String())
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("access log entry is not JSON: %v", err)
+	}
+	if entry["path"] != "/api/v1/backups" || entry["status"] != float64(http.StatusOK) {
+		t.Errorf("entry = %v, want path /api/v1/backups with status 200", entry)
+	}
+}
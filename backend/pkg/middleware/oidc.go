@@ -23,8 +23,11 @@ type OIDCProvider struct {
 	OAuth2Config  *oauth2.Config
 	Verifier      *oidc.IDTokenVerifier
 	Config        *config.OIDCConfig
+	httpClient    *http.Client // nil uses the default client
 	configVersion string
 	configMutex   sync.RWMutex
+	done          chan struct{}
+	closeOnce     sync.Once
 }
 
 // Global config version for tracking changes
@@ -39,7 +42,16 @@ func NewOIDCProvider(oidcConfig *config.OIDCConfig) (*OIDCProvider, error) {
 		return nil, fmt.Errorf("invalid OIDC configuration")
 	}
 
+	httpClient, err := oidcConfig.HTTPClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure OIDC HTTP client: %v", err)
+	}
+
 	ctx := context.Background()
+	if httpClient != nil {
+		// The provider keeps this client for fetching signing keys later on
+		ctx = oidc.ClientContext(ctx, httpClient)
+	}
 	provider, err := oidc.NewProvider(ctx, oidcConfig.IssuerURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create OIDC provider: %v", err)
@@ -62,7 +74,9 @@ func NewOIDCProvider(oidcConfig *config.OIDCConfig) (*OIDCProvider, error) {
 		OAuth2Config:  oauth2Config,
 		Verifier:      verifier,
 		Config:        oidcConfig,
+		httpClient:    httpClient,
 		configVersion: generateConfigVersion(oidcConfig),
+		done:          make(chan struct{}),
 	}
 
 	// Update global config version
@@ -79,6 +93,62 @@ func NewOIDCProvider(oidcConfig *config.OIDCConfig) (*OIDCProvider, error) {
 	return oidcProvider, nil
 }
 
+// ClientContext returns ctx carrying the provider's HTTP client, for OAuth2 calls such
+// as the token exchange that must trust the same CA as discovery
+func (p *OIDCProvider) ClientContext(ctx context.Context) context.Context {
+	if p.httpClient == nil {
+		return ctx
+	}
+	return oidc.ClientContext(ctx, p.httpClient)
+}
+
+// CheckSigningKeys fetches the provider's JWKS and fails unless it holds at least one key,
+// so a provider is only put in use once it can verify ID tokens
+func (p *OIDCProvider) CheckSigningKeys(ctx context.Context) error {
+	var discovery struct {
+		JWKSURL string `json:"jwks_uri"`
+	}
+	if err := p.Provider.Claims(&discovery); err != nil {
+		return fmt.Errorf("failed to read provider metadata: %v", err)
+	}
+	if discovery.JWKSURL == "" {
+		return fmt.Errorf("provider metadata has no jwks_uri")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discovery.JWKSURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build JWKS request: %v", err)
+	}
+	client := p.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signing keys: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch signing keys: %s", resp.Status)
+	}
+	var keySet struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&keySet); err != nil {
+		return fmt.Errorf("failed to decode signing keys: %v", err)
+	}
+	if len(keySet.Keys) == 0 {
+		return fmt.Errorf("provider publishes no signing keys")
+	}
+	return nil
+}
+
+// Close stops the provider's background config watcher once it's been replaced
+func (p *OIDCProvider) Close() {
+	p.closeOnce.Do(func() { close(p.done) })
+}
+
 // UserInfo represents user information extracted from OIDC token
 type UserInfo struct {
 	Username   string   `json:"username"`
@@ -261,30 +331,38 @@ func (p *OIDCProvider) extractClientRoles(claims map[string]interface{}) []strin
 	return allRoles
 }
 
-// mapToVeleroRole maps Keycloak roles to velero-manager roles
+// mapToVeleroRole maps Keycloak roles and groups to velero-manager roles, most privileged
+// first. Users matching no admin, user or viewer mapping get DefaultRole, which may be
+// NoAccessRole.
 func (p *OIDCProvider) mapToVeleroRole(roles, groups []string) string {
-	// Check admin roles
-	adminRoles := []string{"velero-admin", "admin"}
-	for _, adminRole := range adminRoles {
-		for _, userRole := range roles {
-			if strings.EqualFold(userRole, adminRole) {
-				return "admin"
-			}
-		}
+	if containsFold(roles, p.Config.AdminRoles) || containsFold(groups, p.Config.AdminGroups) {
+		return "admin"
 	}
 
-	// Check basic user roles
-	userRoles := []string{"velero-user", "velero-viewer"}
-	for _, allowedRole := range userRoles {
-		for _, userRole := range roles {
-			if strings.EqualFold(userRole, allowedRole) {
-				return "user"
+	if containsFold(roles, p.Config.UserRoles) {
+		return "user"
+	}
+
+	if containsFold(roles, p.Config.ViewerRoles) || containsFold(groups, p.Config.ViewerGroups) {
+		return config.ViewerRole
+	}
+
+	if p.Config.DefaultRole == "" {
+		return config.NoAccessRole
+	}
+	return p.Config.DefaultRole
+}
+
+// containsFold reports whether any of values is in allowed, ignoring case
+func containsFold(values, allowed []string) bool {
+	for _, value := range values {
+		for _, candidate := range allowed {
+			if strings.EqualFold(value, candidate) {
+				return true
 			}
 		}
 	}
-
-	// No matching role - deny access
-	return "no-access"
+	return false
 }
 
 // ValidateOIDCToken validates an OIDC ID token and returns user info
@@ -297,9 +375,12 @@ func (p *OIDCProvider) ValidateOIDCToken(tokenString string) (*UserInfo, error)
 	return p.ExtractUserInfo(idToken)
 }
 
-// RequireOIDCAuth middleware that supports both OIDC and legacy auth
-func RequireOIDCAuth(oidcProvider *OIDCProvider) gin.HandlerFunc {
+// RequireOIDCAuth middleware that supports both OIDC and legacy auth. The provider is
+// looked up per request, so OIDC takes effect as soon as it's initialized
+func RequireOIDCAuth(getProvider func() *OIDCProvider) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		oidcProvider := getProvider()
+
 		// If OIDC is not configured, fall back to legacy auth
 		if oidcProvider == nil || !oidcProvider.Config.Enabled {
 			RequireAuth()(c)
@@ -345,6 +426,8 @@ func RequireOIDCAuth(oidcProvider *OIDCProvider) gin.HandlerFunc {
 			c.Set("username", claims.Username)
 			c.Set("role", claims.Role)
 			c.Set("auth_method", "jwt")
+			c.Set("session_id", claims.SessionID)
+			c.Set(mustChangePasswordKey, claims.MustChangePassword)
 			c.Next()
 			return
 		}
@@ -373,6 +456,7 @@ func RequireOIDCAuth(oidcProvider *OIDCProvider) gin.HandlerFunc {
 		c.Set("username", session.Username)
 		c.Set("role", session.Role)
 		c.Set("auth_method", "session")
+		c.Set(mustChangePasswordKey, session.MustChangePassword)
 		c.Next()
 	}
 }
@@ -445,7 +529,13 @@ func (p *OIDCProvider) watchConfigChanges() {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+
 		// Re-read config from environment
 		currentAdminRoles := strings.Split(os.Getenv("OIDC_ADMIN_ROLES"), ",")
 		currentAdminGroups := strings.Split(os.Getenv("OIDC_ADMIN_GROUPS"), ",")
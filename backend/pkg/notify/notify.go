@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"velero-manager/pkg/config"
+)
+
+// BackupFailure describes a backup that ended Failed or PartiallyFailed
+type BackupFailure struct {
+	Event          string    `json:"event"`
+	Backup         string    `json:"backup"`
+	Cluster        string    `json:"cluster"`
+	Phase          string    `json:"phase"`
+	Schedule       string    `json:"schedule,omitempty"`
+	Errors         int64     `json:"errors"`
+	Warnings       int64     `json:"warnings"`
+	FailureReason  string    `json:"failureReason,omitempty"`
+	CompletionTime time.Time `json:"completionTime"`
+}
+
+// BackupFailedEvent is the Event of every BackupFailure
+const BackupFailedEvent = "backup.failed"
+
+// StorageLocationEvent describes a backup storage location that recovered or has been
+// Unavailable for longer than the alert threshold, or schedules paused or resumed for it
+type StorageLocationEvent struct {
+	Event            string     `json:"event"`
+	Location         string     `json:"location"`
+	Phase            string     `json:"phase"`
+	UnavailableSince *time.Time `json:"unavailableSince,omitempty"`
+	RecoveredAt      *time.Time `json:"recoveredAt,omitempty"`
+	Attempts         int        `json:"attempts"`
+	Schedules        []string   `json:"schedules,omitempty"`
+}
+
+// Events of a StorageLocationEvent
+const (
+	StorageLocationRecoveredEvent   = "storage_location.recovered"
+	StorageLocationUnavailableEvent = "storage_location.unavailable"
+	SchedulesPausedEvent            = "storage_location.schedules_paused"
+	SchedulesResumedEvent           = "storage_location.schedules_resumed"
+)
+
+// Notifier delivers notifications to an external system
+type Notifier interface {
+	NotifyBackupFailure(ctx context.Context, failure BackupFailure) error
+	NotifyStorageLocation(ctx context.Context, event StorageLocationEvent) error
+}
+
+// FromConfig returns the notifier configured by the NOTIFICATION_* settings, or nil if
+// notifications are disabled
+func FromConfig() Notifier {
+	cfg := config.GetNotificationConfig()
+	if cfg.WebhookURL == "" {
+		return nil
+	}
+	return &WebhookNotifier{
+		url:    cfg.WebhookURL,
+		format: cfg.WebhookFormat,
+		client: &http.Client{Timeout: cfg.WebhookTimeout},
+	}
+}
+
+// WebhookNotifier POSTs notifications as JSON, either the event itself or a
+// Slack-compatible message
+type WebhookNotifier struct {
+	url    string
+	format string
+	client *http.Client
+}
+
+// NotifyBackupFailure posts a backup failure to the webhook
+func (w *WebhookNotifier) NotifyBackupFailure(ctx context.Context, failure BackupFailure) error {
+	var payload interface{} = failure
+	if w.format == config.WebhookFormatSlack {
+		payload = slackMessage(failure)
+	}
+	return w.post(ctx, payload)
+}
+
+// NotifyStorageLocation posts a storage location transition to the webhook
+func (w *WebhookNotifier) NotifyStorageLocation(ctx context.Context, event StorageLocationEvent) error {
+	var payload interface{} = event
+	if w.format == config.WebhookFormatSlack {
+		payload = slackStorageLocationMessage(event)
+	}
+	return w.post(ctx, payload)
+}
+
+func (w *WebhookNotifier) post(ctx context.Context, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// slackMessage formats a backup failure as a Slack incoming webhook message
+func slackMessage(failure BackupFailure) map[string]interface{} {
+	text := fmt.Sprintf(":x: Backup *%s* of cluster *%s* %s with %d errors and %d warnings",
+		failure.Backup, failure.Cluster, failure.Phase, failure.Errors, failure.Warnings)
+	if failure.Schedule != "" {
+		text += fmt.Sprintf(" (schedule %s)", failure.Schedule)
+	}
+	if failure.FailureReason != "" {
+		text += fmt.Sprintf("\n>%s", failure.FailureReason)
+	}
+	return map[string]interface{}{"text": text}
+}
+
+// slackStorageLocationMessage formats a storage location transition as a Slack incoming
+// webhook message
+func slackStorageLocationMessage(event StorageLocationEvent) map[string]interface{} {
+	var text string
+	switch event.Event {
+	case StorageLocationRecoveredEvent:
+		text = fmt.Sprintf(":white_check_mark: Storage location *%s* recovered after %d re-validation attempt(s)",
+			event.Location, event.Attempts)
+	case SchedulesPausedEvent:
+		text = fmt.Sprintf(":double_vertical_bar: Paused schedules %s: storage location *%s* is %s",
+			strings.Join(event.Schedules, ", "), event.Location, event.Phase)
+	case SchedulesResumedEvent:
+		text = fmt.Sprintf(":arrow_forward: Resumed schedules %s: storage location *%s* recovered",
+			strings.Join(event.Schedules, ", "), event.Location)
+	default:
+		text = fmt.Sprintf(":warning: Storage location *%s* has been %s since %s (%d re-validation attempt(s))",
+			event.Location, event.Phase, event.UnavailableSince.Format(time.RFC3339), event.Attempts)
+	}
+	return map[string]interface{}{"text": text}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"net/http"
+	"velero-manager/pkg/handlers"
+	"velero-manager/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiHandlers are the handlers behind the /api/v1 routes
+type apiHandlers struct {
+	velero           *handlers.VeleroHandler
+	user             *handlers.UserHandler
+	auth             *handlers.AuthHandler
+	oidcConfig       *handlers.OIDCConfigHandler
+	settings         *handlers.SettingsHandler
+	openAPI          *handlers.OpenAPIHandler
+	storageLocations *handlers.StorageLocationReconciler
+	userActivity     *handlers.UserActivityTracker
+}
+
+// registerAPIRoutes adds the /api/v1 routes, grouped by the access they require
+func registerAPIRoutes(router *gin.Engine, h apiHandlers) {
+	api := router.Group("/api/v1")
+	{
+		// Public endpoints (no auth required)
+		api.GET("/health", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"status": "healthy"})
+		})
+
+		// OpenAPI description of this API
+		api.GET("/openapi.json", h.openAPI.GetSpec)
+
+		// Test endpoint for generating mock metrics data
+		api.POST("/test/generate-mock-data", h.velero.GenerateTestData)
+
+		// Auth endpoints
+		auth := api.Group("/auth")
+		{
+			auth.GET("/info", h.auth.GetAuthInfo)                 // Get auth config and user info
+			auth.POST("/login", h.auth.LegacyLogin)               // Legacy username/password login
+			auth.GET("/oidc/login", h.auth.InitiateOIDCLogin)     // Start OIDC flow
+			auth.GET("/oidc/callback", h.auth.HandleOIDCCallback) // OIDC callback
+			auth.POST("/logout", h.auth.Logout)                   // Logout (both OIDC and legacy)
+		}
+
+		// Protected endpoints (authentication required)
+		protected := api.Group("/")
+		protected.Use(middleware.RequireOIDCAuth(h.auth.GetOIDCProvider))
+		protected.Use(middleware.TrackActivity(h.userActivity))
+		protected.Use(middleware.RequirePasswordChange())
+		{
+			// User management - admin only
+			admin := protected.Group("/")
+			admin.Use(middleware.RequireAdmin())
+			{
+				admin.GET("/users", h.user.ListUsers)
+				admin.GET("/users/activity", h.userActivity.ListUserActivity)
+				admin.GET("/users/:username", h.user.GetUserDetails)
+				admin.POST("/users", h.user.CreateUser)
+				admin.DELETE("/users/:username", h.user.DeleteUser)
+				admin.POST("/users/:username/revoke-sessions", h.user.RevokeUserSessions)
+				admin.PATCH("/backups/:name/ttl", h.velero.UpdateBackupTTL)
+				admin.POST("/backups/apply", h.velero.ApplyBackupManifest)
+				admin.POST("/restores/apply", h.velero.ApplyRestoreManifest)
+				admin.POST("/clusters", h.velero.AddCluster)
+				admin.PUT("/clusters/:cluster/description", h.velero.UpdateClusterDescription)
+				admin.POST("/clusters/:cluster/rotate-token", h.velero.RotateClusterToken)
+				admin.POST("/storage-locations", h.velero.CreateStorageLocation)
+				admin.DELETE("/storage-locations/:name", h.velero.DeleteStorageLocation)
+				admin.POST("/storage-locations/:name/recount", h.velero.RecountStorageLocationBackups)
+
+				// OIDC configuration management - admin only for modify operations
+				admin.PUT("/oidc/config", h.oidcConfig.UpdateOIDCConfig)
+				admin.POST("/oidc/test", h.oidcConfig.TestOIDCConnection)
+
+				// Effective configuration of this instance (secrets redacted)
+				admin.GET("/settings", h.settings.GetSettings)
+			}
+
+			// Create, change and delete operations - everyone but read-only viewers
+			writer := protected.Group("/")
+			writer.Use(middleware.RequireWriteAccess())
+
+			// User can change their own password
+			protected.PUT("/users/:username/password", h.user.ChangePassword)
+
+			// Effective non-secret configuration - all authenticated users can view
+			protected.GET("/config", h.settings.GetConfig)
+
+			// OIDC configuration view - all authenticated users can view
+			protected.GET("/oidc/config", h.oidcConfig.GetOIDCConfig)
+
+			// Backup operations (authenticated users)
+			protected.GET("/backups", h.velero.ListBackups)
+			protected.GET("/backups/summary", h.velero.GetBackupsSummary)
+			protected.GET("/backups/expiring", h.velero.ListExpiringBackups)
+			writer.POST("/backups", h.velero.CreateBackup)
+			writer.DELETE("/backups/:name", h.velero.DeleteBackup)
+			protected.GET("/backups/:name/details", h.velero.GetBackupDetails)
+			protected.GET("/backups/:name/logs", h.velero.GetBackupLogs)
+			protected.GET("/backups/:name/download", h.velero.DownloadBackup)
+			protected.GET("/backups/:name/describe", h.velero.DescribeBackup)
+			protected.GET("/backups/:name/volumes", h.velero.GetBackupVolumes)
+			protected.GET("/backups/:name/resource-list", h.velero.GetBackupResourceList)
+			protected.GET("/backups/:name/delete-request", h.velero.GetBackupDeleteRequest)
+			protected.GET("/restorable-backups", h.velero.ListRestorableBackups)
+
+			// Backup deletion tracking
+			protected.GET("/delete-requests", h.velero.ListDeleteRequests)
+
+			// Restore operations (authenticated users)
+			protected.GET("/restores", h.velero.ListRestores)
+			writer.POST("/restores", h.velero.CreateRestore)
+			writer.DELETE("/restores/:name", h.velero.DeleteRestore)
+			protected.GET("/restores/:name/logs", h.velero.GetRestoreLogs)
+			protected.GET("/restores/:name/describe", h.velero.DescribeRestore)
+			protected.GET("/restores/:name/results", h.velero.GetRestoreResults)
+			protected.GET("/restores/:name/wait", h.velero.WaitForRestore)
+
+			// Ordered restore chains (e.g. databases before apps)
+			writer.POST("/restores/chains", h.velero.CreateRestoreChain)
+			protected.GET("/restores/chains", h.velero.ListRestoreChains)
+			protected.GET("/restores/chains/:id", h.velero.GetRestoreChain)
+
+			// Schedule operations (authenticated users)
+			protected.GET("/schedules", h.velero.ListSchedules)
+			protected.GET("/schedules/broken", h.velero.ListBrokenSchedules)
+			protected.GET("/schedules/overdue", h.velero.ListOverdueSchedules)
+			writer.POST("/schedules", h.velero.CreateSchedule)
+			protected.GET("/schedules/:name", h.velero.DescribeSchedule)
+			protected.GET("/schedules/:name/backups", h.velero.ListScheduleBackups)
+			writer.DELETE("/schedules/:name", h.velero.DeleteSchedule)
+			writer.PUT("/schedules/:name", h.velero.UpdateSchedule)
+			writer.POST("/schedules/:name/backup", h.velero.CreateBackupFromSchedule)
+			writer.POST("/schedules/:name/validate", h.velero.ValidateSchedule)
+			writer.POST("/schedules/:name/pause", h.velero.PauseSchedule)
+			writer.POST("/schedules/:name/resume", h.velero.ResumeSchedule)
+
+			// CronJob operations (authenticated users)
+			protected.GET("/cronjobs", h.velero.ListCronJobs)
+			writer.POST("/cronjobs", h.velero.CreateCronJob)
+			writer.DELETE("/cronjobs/:name", h.velero.DeleteCronJob)
+			writer.PUT("/cronjobs/:name", h.velero.UpdateCronJob)
+			writer.POST("/cronjobs/:name/trigger", h.velero.TriggerCronJob)
+			protected.GET("/cronjobs/:name/jobs", h.velero.ListCronJobRuns)
+			protected.GET("/cronjobs/:name/jobs/:job/logs", h.velero.GetCronJobRunLogs)
+
+			// Cluster operations (read operations for all authenticated users)
+			protected.GET("/clusters", h.velero.ListClusters)
+			protected.GET("/clusters/:cluster/backups", h.velero.ListBackupsByCluster)
+			protected.GET("/clusters/:cluster/health", h.velero.GetClusterHealth)
+			protected.GET("/clusters/:cluster/details", h.velero.GetClusterDetails)
+			protected.GET("/clusters/:cluster/durations", h.velero.GetClusterDurations)
+			protected.GET("/clusters/:cluster/schedules", h.velero.ListClusterSchedules)
+			writer.POST("/clusters/:cluster/backup", h.velero.TriggerClusterBackup)
+			writer.POST("/clusters/:cluster/restore-latest", h.velero.RestoreLatestBackup)
+
+			// Storage locations (read operations for all authenticated users)
+			protected.GET("/storage-locations", h.velero.ListStorageLocations)
+			protected.GET("/storage-locations/validation", h.storageLocations.GetStatus)
+
+			// Backup storage usage per namespace, for chargeback
+			protected.GET("/storage/usage", h.velero.GetStorageUsage)
+
+			// Dashboard metrics
+			protected.GET("/dashboard/metrics", h.velero.GetDashboardMetrics)
+
+			// Live backup/restore status updates (Server-Sent Events)
+			protected.GET("/events/stream", h.velero.StreamEvents)
+
+			// Velero server
+			protected.GET("/velero/info", h.velero.GetVeleroInfo)
+			protected.GET("/velero/version", h.velero.GetVeleroVersion)
+		}
+	}
+}
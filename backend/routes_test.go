@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"velero-manager/pkg/config"
+	"velero-manager/pkg/handlers"
+	"velero-manager/pkg/k8s"
+	"velero-manager/pkg/middleware"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newTestRouter returns the API routes backed by fake API servers, with legacy auth
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	clientset := fake.NewSimpleClientset()
+	clientset.Resources = []*metav1.APIResourceList{{GroupVersion: "velero.io/v1"}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		k8s.BackupGVR:  "BackupList",
+		k8s.CronJobGVR: "CronJobList",
+	})
+	client := &k8s.Client{
+		Clientset:     clientset,
+		DynamicClient: dynamicClient,
+		Context:       context.Background(),
+		ListCache:     k8s.NewListCache(dynamicClient, 0),
+		VeleroVersion: k8s.NewVersionCache(clientset),
+	}
+
+	userHandler := handlers.NewUserHandler(client)
+	middleware.SetUserValidator(userHandler)
+	t.Cleanup(func() { middleware.SetUserValidator(nil) })
+
+	authHandler, err := handlers.NewAuthHandler(client, &config.OIDCConfig{})
+	if err != nil {
+		t.Fatalf("create auth handler: %v", err)
+	}
+
+	router := gin.New()
+	registerAPIRoutes(router, apiHandlers{
+		velero:           handlers.NewVeleroHandler(client, nil),
+		user:             userHandler,
+		auth:             authHandler,
+		oidcConfig:       handlers.NewOIDCConfigHandler(client, authHandler),
+		settings:         handlers.NewSettingsHandler(),
+		openAPI:          handlers.NewOpenAPIHandler(router, "test"),
+		storageLocations: handlers.NewStorageLocationReconciler(client),
+		userActivity:     handlers.NewUserActivityTracker(client),
+	})
+	return router
+}
+
+// request sends a request to router, authenticated with a JWT for role
+func request(t *testing.T, router *gin.Engine, method, path, role string) *httptest.ResponseRecorder {
+	t.Helper()
+	token, err := middleware.CreateJWTToken(role+"-user", role)
+	if err != nil {
+		t.Fatalf("create JWT: %v", err)
+	}
+
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestClusterDescriptionRequiresAdmin(t *testing.T) {
+	router := newTestRouter(t)
+
+	for _, role := range []string{config.ViewerRole, "user"} {
+		w := request(t, router, http.MethodPut, "/api/v1/clusters/prod/description", role)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: status = %d, want %d\n%s", role, w.Code, http.StatusForbidden, w.Body.String())
+		}
+	}
+
+	// Admins get past the middleware to the handler, which rejects the empty body
+	if w := request(t, router, http.MethodPut, "/api/v1/clusters/prod/description", "admin"); w.Code != http.StatusBadRequest {
+		t.Errorf("admin: status = %d, want %d\n%s", w.Code, http.StatusBadRequest, w.Body.String())
+	}
+}
+
+func TestViewerIsReadOnly(t *testing.T) {
+	router := newTestRouter(t)
+
+	if w := request(t, router, http.MethodGet, "/api/v1/backups", config.ViewerRole); w.Code != http.StatusOK {
+		t.Errorf("GET /backups: status = %d, want %d\n%s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if w := request(t, router, http.MethodPost, "/api/v1/backups", config.ViewerRole); w.Code != http.StatusForbidden {
+		t.Errorf("POST /backups: status = %d, want %d\n%s", w.Code, http.StatusForbidden, w.Body.String())
+	}
+}
+
+func TestPublicEndpoints(t *testing.T) {
+	router := newTestRouter(t)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/api/v1/health", http.StatusOK},
+		{"/api/v1/openapi.json", http.StatusOK},
+		{"/api/v1/auth/info", http.StatusOK},
+		{"/api/v1/backups", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.want {
+			t.Errorf("GET %s without a token: status = %d, want %d", tt.path, w.Code, tt.want)
+		}
+	}
+}
@@ -13,7 +13,7 @@ cd ..
 
 # Build Docker image with version tag
 echo "Building Docker image..."
-docker build --build-arg REACT_APP_VERSION="$VERSION" -t velero-manager:latest -t "localhost:32000/velero-manager:$VERSION" .
+docker build --build-arg REACT_APP_VERSION="$VERSION" --build-arg VERSION="$VERSION" --build-arg COMMIT="$(git rev-parse --short HEAD)" -t velero-manager:latest -t "localhost:32000/velero-manager:$VERSION" .
 
 # Push to local registry for testing
 echo "Pushing to local registry..."
@@ -40,7 +40,13 @@ interface OIDCConfig {
   groupsClaim: string;
   adminRoles: string[];
   adminGroups: string[];
+  userRoles?: string[];
+  viewerRoles?: string[];
+  viewerGroups?: string[];
   defaultRole: string;
+  caFile?: string;
+  caData?: string;
+  insecureSkipVerify?: boolean;
 }
 
 const OIDCSettings: React.FC = () => {
@@ -119,10 +125,12 @@ const OIDCSettings: React.FC = () => {
       });
 
       if (response.ok) {
-        setSuccess(
-          'OIDC configuration saved successfully. The application will reload to apply changes.'
-        );
-        // Reload after a short delay to apply new configuration
+        const data = await response.json();
+        if (data.applied === false) {
+          throw new Error(`${data.message}: ${data.error}`);
+        }
+        setSuccess('OIDC configuration saved and applied.');
+        // Reload after a short delay so the login page picks up the new configuration
         setTimeout(() => {
           window.location.reload();
         }, 2000);
@@ -153,11 +161,18 @@ const OIDCSettings: React.FC = () => {
           issuerURL: config.issuerURL,
           clientID: config.clientID,
           clientSecret: config.clientSecret,
+          caFile: config.caFile,
+          caData: config.caData,
+          insecureSkipVerify: config.insecureSkipVerify,
         }),
       });
 
       if (response.ok) {
+        const data = await response.json();
         setTestStatus('success');
+        if (data.credentials?.message) {
+          setSuccess(data.credentials.message);
+        }
         setTimeout(() => setTestStatus('idle'), 3000);
       } else {
         const errorData = await response.json();
@@ -323,6 +338,41 @@ const OIDCSettings: React.FC = () => {
                   />
                 </Grid>
 
+                <Grid item xs={12} md={6}>
+                  <TextField
+                    fullWidth
+                    label="CA Certificate (PEM)"
+                    value={config.caData || ''}
+                    onChange={(e) => setConfig({ ...config, caData: e.target.value })}
+                    helperText="CA bundle for providers behind a private CA, trusted in addition to the system roots"
+                    variant="outlined"
+                    multiline
+                    minRows={2}
+                  />
+                </Grid>
+
+                <Grid item xs={12} md={6}>
+                  <TextField
+                    fullWidth
+                    label="CA Certificate File"
+                    value={config.caFile || ''}
+                    onChange={(e) => setConfig({ ...config, caFile: e.target.value })}
+                    helperText="Path to a CA bundle mounted into the backend pod, e.g. /etc/velero-manager/ca.crt"
+                    variant="outlined"
+                  />
+                  <FormControlLabel
+                    control={
+                      <Switch
+                        checked={config.insecureSkipVerify || false}
+                        onChange={(e) =>
+                          setConfig({ ...config, insecureSkipVerify: e.target.checked })
+                        }
+                      />
+                    }
+                    label="Skip TLS verification (development only, insecure)"
+                  />
+                </Grid>
+
                 <Grid item xs={12}>
                   <Button
                     variant="outlined"
@@ -430,7 +480,7 @@ const OIDCSettings: React.FC = () => {
                     label="Default Role"
                     value={config.defaultRole}
                     onChange={(e) => setConfig({ ...config, defaultRole: e.target.value })}
-                    helperText="Default role for authenticated users"
+                    helperText="Role for users without a mapped role: user, viewer (read-only) or no-access"
                     variant="outlined"
                   />
                 </Grid>
@@ -24,12 +24,13 @@ import {
   IconButton,
   CircularProgress,
 } from '@mui/material';
-import { Add, Edit, Delete, Close } from '@mui/icons-material';
+import { Add, Edit, Delete, Close, Logout } from '@mui/icons-material';
 
 interface User {
   username: string;
   role: string;
   created: string;
+  lastLogin?: string;
 }
 
 const UserManagement: React.FC = () => {
@@ -126,6 +127,28 @@ const UserManagement: React.FC = () => {
     }
   };
 
+  const handleRevokeSessions = async (username: string) => {
+    if (!window.confirm(`Log ${username} out of all sessions?`)) return;
+
+    try {
+      const token = localStorage.getItem('velero_token');
+      const response = await fetch(`/api/v1/users/${username}/revoke-sessions`, {
+        method: 'POST',
+        headers: {
+          Authorization: `Bearer ${token}`,
+          'Content-Type': 'application/json',
+        },
+      });
+
+      if (response.ok) {
+        const data = await response.json();
+        alert(`Revoked ${data.revoked} session(s) of ${username}`);
+      }
+    } catch (error) {
+      alert('Failed to revoke sessions');
+    }
+  };
+
   const handleChangePassword = async () => {
     if (passwordData.newPassword !== passwordData.confirmPassword) {
       alert('Passwords do not match');
@@ -204,6 +227,7 @@ const UserManagement: React.FC = () => {
                 <TableCell>Username</TableCell>
                 <TableCell>Role</TableCell>
                 <TableCell>Created</TableCell>
+                <TableCell>Last Login</TableCell>
                 <TableCell>Actions</TableCell>
               </TableRow>
             </TableHead>
@@ -286,6 +310,17 @@ const UserManagement: React.FC = () => {
                         {user.created || 'N/A'}
                       </Typography>
                     </TableCell>
+                    <TableCell>
+                      <Typography
+                        variant="body2"
+                        sx={{
+                          color: 'text.secondary',
+                          fontSize: '0.875rem',
+                        }}
+                      >
+                        {user.lastLogin ? new Date(user.lastLogin).toLocaleString() : 'Never'}
+                      </Typography>
+                    </TableCell>
                     <TableCell>
                       <Box sx={{ display: 'flex', gap: 1 }}>
                         {(isAdmin || user.username === currentUser.username) && (
@@ -301,6 +336,16 @@ const UserManagement: React.FC = () => {
                             Change Password
                           </Button>
                         )}
+                        {isAdmin && (
+                          <Button
+                            variant="outlined"
+                            size="small"
+                            onClick={() => handleRevokeSessions(user.username)}
+                            startIcon={<Logout />}
+                          >
+                            Revoke Sessions
+                          </Button>
+                        )}
                         {isAdmin && user.username !== 'admin' && (
                           <Button
                             variant="outlined"
@@ -431,6 +476,7 @@ const UserManagement: React.FC = () => {
                 onChange={(e) => setNewUser({ ...newUser, role: e.target.value })}
                 label="Role"
               >
+                <MenuItem value="viewer">Viewer (read-only)</MenuItem>
                 <MenuItem value="user">User</MenuItem>
                 <MenuItem value="admin">Admin</MenuItem>
               </Select>
@@ -62,9 +62,12 @@ export const apiService = {
     return response.data;
   },
 
-  async getBackupLogs(cluster: string, name: string) {
-    const response = await api.get(`/backups/${name}/logs`);
-    return response.data;
+  async getBackupLogs(cluster: string, name: string): Promise<{ logs: string }> {
+    const response = await api.get(`/backups/${name}/logs`, {
+      responseType: 'text',
+      timeout: 300000,
+    });
+    return { logs: response.data };
   },
 
   async downloadBackup(cluster: string, name: string) {
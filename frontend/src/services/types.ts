@@ -47,6 +47,8 @@ export interface User {
 
 export interface AuthConfig {
   oidcEnabled: boolean;
+  oidcStatus?: 'disabled' | 'ready' | 'unavailable';
+  oidcError?: string;
   legacyAuthEnabled: boolean;
   authenticated: boolean;
   user?: User;